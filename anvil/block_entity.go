@@ -0,0 +1,281 @@
+package anvil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	block_java "github.com/TriM-Organization/bedrock-world-operator/block/java"
+	"github.com/TriM-Organization/bedrock-world-operator/internal/nbtvalue"
+)
+
+// javaContainers maps the IDs of the Java Edition block entities that hold items
+// in their "Items" to the IDs of the Bedrock Edition ones.
+var javaContainers = map[string]string{
+	"chest":         "Chest",
+	"trapped_chest": "Chest",
+	"barrel":        "Barrel",
+	"shulker_box":   "ShulkerBox",
+	"dispenser":     "Dispenser",
+	"dropper":       "Dropper",
+	"hopper":        "Hopper",
+}
+
+// javaSigns maps the IDs of the Java Edition sign block entities to the IDs of the Bedrock Edition ones.
+var javaSigns = map[string]string{
+	"sign":         "Sign",
+	"hanging_sign": "HangingSign",
+}
+
+// javaEntities maps the IDs of the Java Edition entities that are different
+// from the ones of Bedrock Edition, which are used by the spawners.
+var javaEntities = map[string]string{
+	"minecraft:zombified_piglin": "minecraft:zombie_pigman",
+	"minecraft:evoker":           "minecraft:evocation_illager",
+	"minecraft:experience_orb":   "minecraft:xp_orb",
+}
+
+// signColors maps the names of the dye colors to the ARGB colors of the sign texts.
+var signColors = map[string]uint32{
+	"white":      0xfff0f0f0,
+	"orange":     0xfff9801d,
+	"magenta":    0xffc74ebd,
+	"light_blue": 0xff3ab3da,
+	"yellow":     0xfffed83d,
+	"lime":       0xff80c71f,
+	"pink":       0xfff38baa,
+	"gray":       0xff474f52,
+	"light_gray": 0xff9d9d97,
+	"cyan":       0xff169c9c,
+	"purple":     0xff8932b8,
+	"blue":       0xff3c44aa,
+	"brown":      0xff835432,
+	"green":      0xff5e7c16,
+	"red":        0xffb02e26,
+	"black":      0xff000000,
+}
+
+// blockEntityToBedrock converts the NBT of a Java Edition block entity to the one of Bedrock Edition,
+// where state is the Java Edition block at the position of the block entity. Only the containers, the
+// signs and the spawners are supported, and ok is false for the others.
+func blockEntityToBedrock(nbt map[string]any, state block_java.State) (result map[string]any, ok bool) {
+	id, _ := nbt["id"].(string)
+	id = strings.TrimPrefix(id, "minecraft:")
+
+	x, okX := nbtvalue.Int(nbt["x"])
+	y, okY := nbtvalue.Int(nbt["y"])
+	z, okZ := nbtvalue.Int(nbt["z"])
+	if !okX || !okY || !okZ {
+		return nil, false
+	}
+	result = map[string]any{
+		"x":         int32(x),
+		"y":         int32(y),
+		"z":         int32(z),
+		"isMovable": byte(1),
+	}
+	if name, ok := nbt["CustomName"]; ok {
+		result["CustomName"] = plainText(name)
+	}
+
+	switch {
+	case javaContainers[id] != "":
+		result["id"] = javaContainers[id]
+		result["Findable"] = byte(0)
+		items := make([]map[string]any, 0)
+		if javaItems, ok := nbt["Items"].([]any); ok {
+			for _, javaItem := range javaItems {
+				if item, ok := javaItem.(map[string]any); ok {
+					items = append(items, itemToBedrock(item))
+				}
+			}
+		}
+		result["Items"] = items
+		if id == "chest" || id == "trapped_chest" {
+			pairChest(result, state)
+		}
+	case javaSigns[id] != "":
+		result["id"] = javaSigns[id]
+		isWaxed, _ := nbtvalue.Int(nbt["is_waxed"])
+		result["IsWaxed"] = byte(isWaxed)
+		if front, ok := nbt["front_text"].(map[string]any); ok {
+			back, _ := nbt["back_text"].(map[string]any)
+			result["FrontText"], result["BackText"] = signTextToBedrock(front), signTextToBedrock(back)
+		} else {
+			// The signs before Minecraft 1.20 only have the front side.
+			messages := make([]any, 4)
+			for i := range messages {
+				messages[i] = nbt[fmt.Sprintf("Text%d", i+1)]
+			}
+			glowing, _ := nbtvalue.Int(nbt["GlowingText"])
+			result["FrontText"] = signTextToBedrock(map[string]any{
+				"messages":         messages,
+				"color":            nbt["Color"],
+				"has_glowing_text": byte(glowing),
+			})
+			result["BackText"] = signTextToBedrock(nil)
+		}
+	case id == "mob_spawner":
+		result["id"] = "MobSpawner"
+		spawnData, _ := nbt["SpawnData"].(map[string]any)
+		if entity, ok := spawnData["entity"].(map[string]any); ok {
+			// The spawners since Minecraft 1.18 hold the entity in "entity".
+			spawnData = entity
+		}
+		entityID, _ := spawnData["id"].(string)
+		if bedrockID, ok := javaEntities[entityID]; ok {
+			entityID = bedrockID
+		}
+		result["EntityIdentifier"] = entityID
+		for key, defaultValue := range map[string]int16{
+			"Delay":               20,
+			"MinSpawnDelay":       200,
+			"MaxSpawnDelay":       800,
+			"SpawnCount":          4,
+			"MaxNearbyEntities":   6,
+			"RequiredPlayerRange": 16,
+			"SpawnRange":          4,
+		} {
+			if value, ok := nbtvalue.Int(nbt[key]); ok {
+				defaultValue = int16(value)
+			}
+			result[key] = defaultValue
+		}
+		result["DisplayEntityWidth"], result["DisplayEntityHeight"], result["DisplayEntityScale"] = float32(0.8), float32(1.8), float32(1)
+	default:
+		return nil, false
+	}
+
+	return result, true
+}
+
+// itemToBedrock converts the NBT of a Java Edition item in a container to the one of Bedrock Edition.
+// The names of most items are the same in both editions, so they are kept as they are.
+func itemToBedrock(item map[string]any) map[string]any {
+	name, _ := item["id"].(string)
+	slot, _ := nbtvalue.Int(item["Slot"])
+	count, ok := nbtvalue.Int(item["count"])
+	if !ok {
+		// The items before Minecraft 1.20.5 hold the count in "Count".
+		if count, ok = nbtvalue.Int(item["Count"]); !ok {
+			count = 1
+		}
+	}
+
+	result := map[string]any{
+		"Name":        name,
+		"Count":       byte(count),
+		"Damage":      int16(0),
+		"Slot":        byte(slot),
+		"WasPickedUp": byte(0),
+	}
+
+	// The durability is in the components since Minecraft 1.20.5, and in the tag before.
+	components, _ := item["components"].(map[string]any)
+	tag, _ := item["tag"].(map[string]any)
+	if damage, ok := nbtvalue.Int(components["minecraft:damage"]); ok {
+		result["tag"] = map[string]any{"Damage": int32(damage)}
+	} else if damage, ok := nbtvalue.Int(tag["Damage"]); ok {
+		result["tag"] = map[string]any{"Damage": int32(damage)}
+	}
+
+	return result
+}
+
+// pairChest sets the position of the other half to the Bedrock Edition chest if state is the
+// half of a Java Edition double chest, where "type" tells which half it is.
+func pairChest(result map[string]any, state block_java.State) {
+	chestType := state.Properties["type"]
+	if chestType != "left" && chestType != "right" {
+		return
+	}
+
+	// The other half of the left one is at the clockwise
+	// side of the facing, and the one of the right half is
+	// at the counterclockwise side.
+	var dx, dz int32
+	switch state.Properties["facing"] {
+	case "north":
+		dx = 1
+	case "east":
+		dz = 1
+	case "south":
+		dx = -1
+	case "west":
+		dz = -1
+	default:
+		return
+	}
+	if chestType == "right" {
+		dx, dz = -dx, -dz
+	}
+
+	result["pairx"], result["pairz"] = result["x"].(int32)+dx, result["z"].(int32)+dz
+	if chestType == "left" {
+		result["pairlead"] = byte(1)
+	} else {
+		result["pairlead"] = byte(0)
+	}
+}
+
+// signTextToBedrock converts a side of a Java Edition sign, which holds "messages", "color" and
+// "has_glowing_text", to the one of Bedrock Edition.
+func signTextToBedrock(text map[string]any) map[string]any {
+	messages, _ := text["messages"].([]any)
+	lines := make([]string, len(messages))
+	for i, message := range messages {
+		lines[i] = plainText(message)
+	}
+
+	color, _ := text["color"].(string)
+	argb, ok := signColors[color]
+	if !ok {
+		argb = signColors["black"]
+	}
+	glowing, _ := nbtvalue.Int(text["has_glowing_text"])
+
+	return map[string]any{
+		"Text":              strings.TrimRight(strings.Join(lines, "\n"), "\n"),
+		"TextOwner":         "",
+		"SignTextColor":     int32(argb),
+		"IgnoreLighting":    byte(glowing),
+		"HideGlowOutline":   byte(0),
+		"PersistFormatting": byte(1),
+	}
+}
+
+// plainText converts a Java Edition text component to the plain text. The component is a JSON string
+// before Minecraft 1.21.5, and a string or a compound tag since then. Only the literal texts are kept.
+func plainText(v any) string {
+	if s, ok := v.(string); ok {
+		var component any
+		if err := json.Unmarshal([]byte(s), &component); err != nil {
+			return s
+		}
+		v = component
+	}
+	return componentText(v)
+}
+
+// componentText returns the literal text of a decoded text component, including the ones of its children.
+func componentText(v any) string {
+	switch component := v.(type) {
+	case string:
+		return component
+	case float64, bool:
+		return fmt.Sprint(component)
+	case []any:
+		var builder strings.Builder
+		for _, child := range component {
+			builder.WriteString(componentText(child))
+		}
+		return builder.String()
+	case map[string]any:
+		text, _ := component["text"].(string)
+		if extra, ok := component["extra"].([]any); ok {
+			text += componentText(extra)
+		}
+		return text
+	}
+	return ""
+}
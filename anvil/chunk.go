@@ -0,0 +1,283 @@
+package anvil
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	block_java "github.com/TriM-Organization/bedrock-world-operator/block/java"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/internal/nbtvalue"
+)
+
+// MinDataVersion is the lowest data version of the Java Edition chunks that could be converted,
+// which is the one of Minecraft 1.18.
+const MinDataVersion = 2860
+
+// ErrUnsupportedChunk is returned (wrapped) by ConvertChunk if the chunk is saved in the format before
+// Minecraft 1.18. The worlds upgraded from the older versions usually still hold such chunks in the areas
+// that nobody visited since then, so they are skipped by ConvertRegions.
+var ErrUnsupportedChunk = errors.New("chunk format before 1.18 is not supported")
+
+// alwaysWaterlogged holds the names of the Java Edition blocks that are always in water, so
+// the water is placed in chunk.LiquidLayer for them even if they have no "waterlogged" property.
+var alwaysWaterlogged = map[string]bool{
+	"minecraft:seagrass":      true,
+	"minecraft:tall_seagrass": true,
+	"minecraft:kelp":          true,
+	"minecraft:kelp_plant":    true,
+	"minecraft:bubble_column": true,
+}
+
+// convertedState is a Java Edition block state and the result of converting it.
+type convertedState struct {
+	state       block_java.State
+	runtimeID   uint32
+	waterlogged bool
+}
+
+// Converter converts the chunks of Java Edition to the ones of Bedrock Edition. The results of converting
+// the block states are cached, so a Converter should be reused for all the chunks of a world.
+// It is not safe to call methods on Converter simultaneously from multiple goroutines.
+type Converter struct {
+	states map[string]convertedState
+}
+
+// NewConverter creates a new Converter.
+func NewConverter() *Converter {
+	return &Converter{states: make(map[string]convertedState)}
+}
+
+// ConvertChunk converts the NBT of a Java Edition chunk, which is read from a Region, to a chunk in dm
+// dimension and the NBT of its block entities. The chunks saved in the format before 1.18, which hold a
+// "Level" tag or whose DataVersion is lower than MinDataVersion, are not supported, and an error wrapping
+// ErrUnsupportedChunk is returned for them.
+//
+// The blocks are converted by block_java.ToBedrock, and the blocks that could not be converted become
+// "minecraft:unknown". The waterlogged blocks are placed with water in chunk.LiquidLayer. The sections
+// out of the height range of dm are dropped. See blockEntityToBedrock for the supported block entities.
+//
+// c is nil if the chunk is not fully generated, which is not shown by Java Edition either.
+func (conv *Converter) ConvertChunk(m map[string]any, dm define.Dimension) (position define.ChunkPos, c *chunk.Chunk, blockEntities []map[string]any, err error) {
+	dataVersion, _ := nbtvalue.Int(m["DataVersion"])
+	if _, ok := m["Level"]; ok {
+		return position, nil, nil, fmt.Errorf("ConvertChunk: %w: chunk of data version %d holds a Level tag", ErrUnsupportedChunk, dataVersion)
+	}
+	if dataVersion < MinDataVersion {
+		return position, nil, nil, fmt.Errorf("ConvertChunk: %w: data version %d is lower than %d", ErrUnsupportedChunk, dataVersion, MinDataVersion)
+	}
+
+	x, okX := nbtvalue.Int(m["xPos"])
+	z, okZ := nbtvalue.Int(m["zPos"])
+	if !okX || !okZ {
+		return position, nil, nil, fmt.Errorf("ConvertChunk: chunk position is not found")
+	}
+	position = define.ChunkPos{int32(x), int32(z)}
+
+	status, _ := m["Status"].(string)
+	if strings.TrimPrefix(status, "minecraft:") != "full" {
+		return position, nil, nil, nil
+	}
+
+	// The block entities are indexed by their positions, so
+	// that the block states of them could be found later.
+	javaBlockEntities := make(map[define.BlockPos]map[string]any)
+	javaBlockEntityList, _ := m["block_entities"].([]any)
+	for _, value := range javaBlockEntityList {
+		nbt, _ := value.(map[string]any)
+		x, okX := nbtvalue.Int(nbt["x"])
+		y, okY := nbtvalue.Int(nbt["y"])
+		z, okZ := nbtvalue.Int(nbt["z"])
+		if okX && okY && okZ {
+			javaBlockEntities[define.BlockPos{int32(x), int32(y), int32(z)}] = nbt
+		}
+	}
+	states := make(map[define.BlockPos]block_java.State)
+
+	r := dm.Range()
+	c = chunk.NewChunk(block.AirRuntimeID, r)
+	biomes := c.Biomes()
+
+	sections, _ := m["sections"].([]any)
+	for _, value := range sections {
+		section, _ := value.(map[string]any)
+		sectionY, ok := section["Y"].(byte)
+		if !ok {
+			continue
+		}
+		index := int(int8(sectionY)) - r[0]>>4
+		if index < 0 || index >= len(biomes) {
+			continue
+		}
+
+		blockStates, _ := section["block_states"].(map[string]any)
+		palette, indices, err := conv.blockStates(blockStates)
+		if err != nil {
+			return position, nil, nil, fmt.Errorf("ConvertChunk: section %d of chunk %v: %v", int8(sectionY), position, err)
+		}
+		if err = conv.setBlocks(c.Sub()[index], palette, indices); err != nil {
+			return position, nil, nil, fmt.Errorf("ConvertChunk: section %d of chunk %v: %v", int8(sectionY), position, err)
+		}
+
+		for pos := range javaBlockEntities {
+			if pos[1]>>4 == int32(int8(sectionY)) {
+				states[pos] = palette[indices[(pos[1]&15)<<8|(pos[2]&15)<<4|pos[0]&15]].state
+			}
+		}
+
+		sectionBiomes, _ := section["biomes"].(map[string]any)
+		if biomes[index], err = sectionBiomesToBedrock(sectionBiomes); err != nil {
+			return position, nil, nil, fmt.Errorf("ConvertChunk: section %d of chunk %v: %v", int8(sectionY), position, err)
+		}
+	}
+	c.SetBiomes(biomes)
+
+	blockEntities = make([]map[string]any, 0, len(javaBlockEntities))
+	for pos, nbt := range javaBlockEntities {
+		if pos[1] < int32(r[0]) || pos[1] > int32(r[1]) {
+			continue
+		}
+		if result, ok := blockEntityToBedrock(nbt, states[pos]); ok {
+			blockEntities = append(blockEntities, result)
+		}
+	}
+
+	return position, c, blockEntities, nil
+}
+
+// blockStates converts the palette of a section and unpacks its data, so that the block at x, y and z
+// of the section is palette[indices[(y<<8)|(z<<4)|x]].
+func (conv *Converter) blockStates(blockStates map[string]any) (palette []convertedState, indices []uint16, err error) {
+	javaPalette, _ := blockStates["palette"].([]any)
+	if len(javaPalette) == 0 {
+		// The sections without the palette are filled with air.
+		javaPalette = []any{map[string]any{"Name": "minecraft:air"}}
+	}
+
+	palette = make([]convertedState, len(javaPalette))
+	for i, value := range javaPalette {
+		entry, _ := value.(map[string]any)
+		name, _ := entry["Name"].(string)
+		properties, _ := entry["Properties"].(map[string]any)
+
+		state := block_java.State{Name: name, Properties: make(map[string]string, len(properties))}
+		for key, value := range properties {
+			state.Properties[key], _ = value.(string)
+		}
+		palette[i] = conv.convertState(state)
+	}
+
+	data, _ := nbtvalue.Int64s(blockStates["data"])
+	if indices, err = unpackIndices(data, len(palette), 4096, 4); err != nil {
+		return nil, nil, fmt.Errorf("block states: %v", err)
+	}
+	return palette, indices, nil
+}
+
+// convertState converts the Java Edition block state and caches the result.
+func (conv *Converter) convertState(state block_java.State) convertedState {
+	key := state.String()
+	if result, ok := conv.states[key]; ok {
+		return result
+	}
+
+	result := convertedState{state: state, runtimeID: block.UnknownRuntimeID}
+	result.waterlogged = state.Properties["waterlogged"] == "true" || alwaysWaterlogged[state.Name]
+
+	withoutWater := block_java.State{Name: state.Name, Properties: make(map[string]string, len(state.Properties))}
+	for key, value := range state.Properties {
+		if key != "waterlogged" {
+			withoutWater.Properties[key] = value
+		}
+	}
+	if runtimeID, _, found := block_java.ToBedrock(withoutWater); found {
+		result.runtimeID = runtimeID
+	}
+
+	conv.states[key] = result
+	return result
+}
+
+// setBlocks sets the blocks of a section to sub, where javaIndices is in the order of (y<<8)|(z<<4)|x.
+func (conv *Converter) setBlocks(sub *chunk.SubChunk, palette []convertedState, javaIndices []uint16) error {
+	runtimeIDs, waterlogged := make([]uint32, len(palette)), false
+	for i, state := range palette {
+		runtimeIDs[i], waterlogged = state.runtimeID, waterlogged || state.waterlogged
+	}
+
+	// The order of the indices of the sub chunk is (x<<8)|(z<<4)|y.
+	indices, liquidIndices := make([]uint16, 4096), make([]uint16, 4096)
+	for i, index := range javaIndices {
+		offset := (i&15)<<8 | (i>>4&15)<<4 | i>>8
+		indices[offset] = index
+		if palette[index].waterlogged {
+			liquidIndices[offset] = 1
+		}
+	}
+
+	if err := sub.SetLayerIndices(chunk.BlockLayer, runtimeIDs, indices); err != nil {
+		return fmt.Errorf("setBlocks: %v", err)
+	}
+	if waterlogged {
+		if err := sub.SetLayerIndices(chunk.LiquidLayer, []uint32{block.AirRuntimeID, block.WaterRuntimeID}, liquidIndices); err != nil {
+			return fmt.Errorf("setBlocks: %v", err)
+		}
+	}
+	return nil
+}
+
+// sectionBiomesToBedrock converts the 4x4x4 biomes of a section to the biome of every block of a
+// sub chunk, which is in the order of (x<<8)|(y<<4)|z.
+func sectionBiomesToBedrock(sectionBiomes map[string]any) ([]uint32, error) {
+	javaPalette, _ := sectionBiomes["palette"].([]any)
+	if len(javaPalette) == 0 {
+		javaPalette = []any{"minecraft:plains"}
+	}
+
+	palette := make([]uint32, len(javaPalette))
+	for i, value := range javaPalette {
+		name, _ := value.(string)
+		palette[i] = biomeToBedrock(name)
+	}
+
+	data, _ := nbtvalue.Int64s(sectionBiomes["data"])
+	indices, err := unpackIndices(data, len(palette), 64, 0)
+	if err != nil {
+		return nil, fmt.Errorf("biomes: %v", err)
+	}
+
+	result := make([]uint32, 4096)
+	for i := range result {
+		x, y, z := i>>8, i>>4&15, i&15
+		result[i] = palette[indices[(y>>2)<<4|(z>>2)<<2|x>>2]]
+	}
+	return result, nil
+}
+
+// unpackIndices unpacks n palette indices from data, where each index uses the bits that are enough
+// to hold paletteSize values but no less than minBits, and the indices never span across two longs.
+func unpackIndices(data []int64, paletteSize int, n int, minBits int) ([]uint16, error) {
+	indices := make([]uint16, n)
+	if paletteSize <= 1 {
+		return indices, nil
+	}
+
+	bitsPerIndex := max(bits.Len(uint(paletteSize-1)), minBits)
+	indicesPerLong := 64 / bitsPerIndex
+	if len(data) < (n+indicesPerLong-1)/indicesPerLong {
+		return nil, fmt.Errorf("expected %d longs, but got %d", (n+indicesPerLong-1)/indicesPerLong, len(data))
+	}
+
+	mask := uint64(1)<<bitsPerIndex - 1
+	for i := range indices {
+		index := uint64(data[i/indicesPerLong]) >> (uint(i%indicesPerLong) * uint(bitsPerIndex)) & mask
+		if int(index) >= paletteSize {
+			return nil, fmt.Errorf("index %d out of palette (len=%d)", index, paletteSize)
+		}
+		indices[i] = uint16(index)
+	}
+	return indices, nil
+}
@@ -0,0 +1,150 @@
+package anvil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// testJavaChunk returns the NBT of a Java Edition chunk at x and z in the format of 1.18, whose
+// section 0 holds a waterlogged oak stairs at (0, 0, 0), a stone at (1, 2, 3) and air elsewhere,
+// and whose biomes are all desert.
+func testJavaChunk(x, z int32) map[string]any {
+	var data [256]int64
+	data[0] = 2                     // index 0, (0, 0, 0)
+	data[(2<<8|3<<4|1)/16] = 1 << 4 // index 561, (1, 2, 3)
+
+	return map[string]any{
+		"DataVersion": int32(3465),
+		"xPos":        x,
+		"zPos":        z,
+		"Status":      "minecraft:full",
+		"sections": []any{
+			map[string]any{
+				"Y": byte(0),
+				"block_states": map[string]any{
+					"palette": []any{
+						map[string]any{"Name": "minecraft:air"},
+						map[string]any{"Name": "minecraft:stone"},
+						map[string]any{"Name": "minecraft:oak_stairs", "Properties": map[string]any{
+							"facing": "north", "half": "bottom", "shape": "straight", "waterlogged": "true",
+						}},
+					},
+					"data": data,
+				},
+				"biomes": map[string]any{"palette": []any{"minecraft:desert"}},
+			},
+		},
+	}
+}
+
+// writeTestRegion writes the chunks to the region file at name uncompressed, where each chunk
+// is placed at the relative position of its xPos and zPos.
+func writeTestRegion(t *testing.T, name string, chunks []map[string]any) {
+	data := make([]byte, sectorSize*2)
+	for _, m := range chunks {
+		var buf bytes.Buffer
+		if err := nbt.NewEncoderWithEncoding(&buf, nbt.BigEndian).Encode(m); err != nil {
+			t.Fatal(err)
+		}
+
+		payload := binary.BigEndian.AppendUint32(nil, uint32(buf.Len()+1))
+		payload = append(append(payload, compressionNone), buf.Bytes()...)
+		sectors := (len(payload) + sectorSize - 1) / sectorSize
+
+		x, z := m["xPos"].(int32), m["zPos"].(int32)
+		binary.BigEndian.PutUint32(data[((z&31)<<5|x&31)*4:], uint32(len(data)/sectorSize)<<8|uint32(sectors))
+		data = append(data, make([]byte, sectors*sectorSize)...)
+		copy(data[len(data)-sectors*sectorSize:], payload)
+	}
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvertChunk(t *testing.T) {
+	position, c, _, err := NewConverter().ConvertChunk(testJavaChunk(2, -3), define.DimensionIDOverworld)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position != (define.ChunkPos{2, -3}) {
+		t.Fatalf("expected position (2, -3), but got %v", position)
+	}
+
+	stone, _ := block.StateToRuntimeID("minecraft:stone", nil)
+	if got := c.Block(1, 2, 3, 0); got != stone {
+		t.Fatalf("expected stone at (1, 2, 3), but got %d", got)
+	}
+	if got := c.Block(3, 2, 1, 0); got != block.AirRuntimeID {
+		t.Fatalf("expected air at (3, 2, 1), but got %d", got)
+	}
+
+	name, states, _ := block.RuntimeIDToState(c.Block(0, 0, 0, 0))
+	if name != "minecraft:oak_stairs" || states["upside_down_bit"] != byte(0) {
+		t.Fatalf("expected bottom oak stairs at (0, 0, 0), but got %v %v", name, states)
+	}
+	if got := c.Block(0, 0, 0, 1); got != block.WaterRuntimeID {
+		t.Fatalf("expected water in layer 1 at (0, 0, 0), but got %d", got)
+	}
+	if got := c.Block(1, 2, 3, 1); got != block.AirRuntimeID {
+		t.Fatalf("expected air in layer 1 at (1, 2, 3), but got %d", got)
+	}
+	if got := c.Biome(5, 7, 9); got != javaBiomes["desert"] {
+		t.Fatalf("expected desert at (5, 7, 9), but got %d", got)
+	}
+}
+
+func TestConvertChunkUnsupported(t *testing.T) {
+	old := testJavaChunk(0, 0)
+	old["Level"] = map[string]any{}
+	_, _, _, err := NewConverter().ConvertChunk(old, define.DimensionIDOverworld)
+	if !errors.Is(err, ErrUnsupportedChunk) {
+		t.Fatalf("expected ErrUnsupportedChunk for chunk with Level, but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Level") {
+		t.Fatalf("expected the error to mention the Level tag, but got %v", err)
+	}
+
+	old = testJavaChunk(0, 0)
+	old["DataVersion"] = int32(2730)
+	if _, _, _, err = NewConverter().ConvertChunk(old, define.DimensionIDOverworld); !errors.Is(err, ErrUnsupportedChunk) {
+		t.Fatalf("expected ErrUnsupportedChunk for data version 2730, but got %v", err)
+	}
+}
+
+func TestConvertRegionsSkipsUnsupported(t *testing.T) {
+	regionDir := t.TempDir()
+	old := testJavaChunk(1, 0)
+	old["Level"] = map[string]any{}
+	writeTestRegion(t, filepath.Join(regionDir, "r.0.0.mca"), []map[string]any{testJavaChunk(0, 0), old})
+
+	w, err := world.Open(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.CloseWorld()
+
+	chunks, skipped, err := NewConverter().ConvertRegions(regionDir, w, define.DimensionIDOverworld)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks != 1 || skipped != 1 {
+		t.Fatalf("expected 1 chunk converted and 1 skipped, but got %d and %d", chunks, skipped)
+	}
+
+	if _, exists, _ := w.LoadChunk(define.DimensionIDOverworld, define.ChunkPos{0, 0}); !exists {
+		t.Fatal("chunk (0, 0) is not written")
+	}
+	if _, exists, _ := w.LoadChunk(define.DimensionIDOverworld, define.ChunkPos{1, 0}); exists {
+		t.Fatal("chunk (1, 0) in the old format is written")
+	}
+}
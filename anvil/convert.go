@@ -0,0 +1,94 @@
+package anvil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world"
+)
+
+// javaDimensions maps the dimensions to the directories of their region files in a Java Edition world.
+var javaDimensions = map[define.Dimension]string{
+	define.DimensionIDOverworld: "region",
+	define.DimensionIDNether:    filepath.Join("DIM-1", "region"),
+	define.DimensionIDEnd:       filepath.Join("DIM1", "region"),
+}
+
+// ConvertWorld converts the Java Edition world at dir, which holds the level.dat, and writes the chunks to
+// w. The overworld, the nether and the end are converted if their region files are exist. chunks is the
+// count of the chunks that are written, and skipped is the count of the chunks that are skipped because
+// they are saved in the format before 1.18, see ConvertRegions.
+//
+// Only the chunks and their block entities are converted, and the level.dat of w is not changed.
+func ConvertWorld(dir string, w world.World) (chunks int, skipped int, err error) {
+	conv := NewConverter()
+	for _, dm := range []define.Dimension{define.DimensionIDOverworld, define.DimensionIDNether, define.DimensionIDEnd} {
+		regionDir := filepath.Join(dir, javaDimensions[dm])
+		if _, err := os.Stat(regionDir); os.IsNotExist(err) {
+			continue
+		}
+
+		n, s, err := conv.ConvertRegions(regionDir, w, dm)
+		chunks, skipped = chunks+n, skipped+s
+		if err != nil {
+			return chunks, skipped, fmt.Errorf("ConvertWorld: %v", err)
+		}
+	}
+	return chunks, skipped, nil
+}
+
+// ConvertRegions converts all the region (.mca) files in dir and writes the chunks to dm dimension of
+// w by SaveChunk and SaveNBT. The chunks that are not fully generated are skipped. chunks is the count
+// of the chunks that are written.
+//
+// The chunks saved in the format before 1.18 (see ErrUnsupportedChunk) are skipped instead of failing
+// the whole conversion, because the upgraded worlds usually still hold them in the areas that nobody
+// visited since then. skipped is the count of such chunks, which are not written to w.
+//
+// Note that the blob hashes of the written chunks are not updated.
+func (conv *Converter) ConvertRegions(dir string, w world.World, dm define.Dimension) (chunks int, skipped int, err error) {
+	names, err := filepath.Glob(filepath.Join(dir, "r.*.*.mca"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("ConvertRegions: %v", err)
+	}
+
+	for _, name := range names {
+		region, err := ReadRegionFile(name)
+		if err != nil {
+			return chunks, skipped, fmt.Errorf("ConvertRegions: %v", err)
+		}
+
+		for _, relative := range region.ChunkPositions() {
+			m, _, err := region.Chunk(relative[0], relative[1])
+			if err != nil {
+				return chunks, skipped, fmt.Errorf("ConvertRegions: %v", err)
+			}
+
+			position, c, blockEntities, err := conv.ConvertChunk(m, dm)
+			if errors.Is(err, ErrUnsupportedChunk) {
+				skipped++
+				continue
+			}
+			if err != nil {
+				return chunks, skipped, fmt.Errorf("ConvertRegions: %v: %v", name, err)
+			}
+			if c == nil {
+				continue
+			}
+
+			c.Compact()
+			if err = w.SaveChunk(dm, position, c); err != nil {
+				return chunks, skipped, fmt.Errorf("ConvertRegions: %v", err)
+			}
+			if err = w.SaveNBT(dm, position, blockEntities); err != nil {
+				return chunks, skipped, fmt.Errorf("ConvertRegions: %v", err)
+			}
+			chunks++
+		}
+	}
+
+	return chunks, skipped, nil
+}
@@ -0,0 +1,127 @@
+package anvil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// sectorSize is the size of a sector in a region file.
+const sectorSize = 4096
+
+// The compression types of the chunks in a region file.
+const (
+	compressionGzip         = 1
+	compressionZlib         = 2
+	compressionNone         = 3
+	compressionLZ4          = 4
+	compressionExternalFlag = 128
+)
+
+// Region is a region (.mca) file of Java Edition, which holds 32x32 chunks. The region at rx and rz holds
+// the chunks from (rx*32, rz*32) to (rx*32+31, rz*32+31).
+type Region struct {
+	// name is the path of the region file, which is used to
+	// find the external chunk (.mcc) files of the large chunks.
+	name string
+	// data is the whole content of the region file.
+	data []byte
+}
+
+// ReadRegionFile reads a region (.mca) file at a path and returns it.
+func ReadRegionFile(name string) (*Region, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("region: read file: %w", err)
+	}
+	if len(data) != 0 && len(data) < sectorSize*2 {
+		return nil, fmt.Errorf("region: file %v is too short (len=%d)", name, len(data))
+	}
+	return &Region{name: name, data: data}, nil
+}
+
+// ChunkPositions returns the positions of all the chunks that are exist in r. The positions are relative
+// to the region, which are in a range of 0-31.
+func (r *Region) ChunkPositions() []define.ChunkPos {
+	result := make([]define.ChunkPos, 0)
+	if len(r.data) == 0 {
+		return result
+	}
+	for index := range int32(1024) {
+		if binary.BigEndian.Uint32(r.data[index*4:]) != 0 {
+			result = append(result, define.ChunkPos{index & 31, index >> 5})
+		}
+	}
+	return result
+}
+
+// Chunk reads the NBT of the chunk at the relative position x and z, which must be in a range of 0-31.
+// If the chunk is not exist, exists is false.
+func (r *Region) Chunk(x, z int32) (m map[string]any, exists bool, err error) {
+	if len(r.data) == 0 {
+		return nil, false, nil
+	}
+
+	location := binary.BigEndian.Uint32(r.data[((z&31)<<5|x&31)*4:])
+	offset, sectors := int(location>>8)*sectorSize, int(location&0xff)
+	if location == 0 {
+		return nil, false, nil
+	}
+	if offset < sectorSize*2 || offset+5 > len(r.data) || sectors == 0 {
+		return nil, true, fmt.Errorf("Chunk: invalid location of chunk (%d, %d) in %v", x, z, r.name)
+	}
+
+	length := int(binary.BigEndian.Uint32(r.data[offset:]))
+	compression := r.data[offset+4]
+	if length < 1 || offset+4+length > len(r.data) {
+		return nil, true, fmt.Errorf("Chunk: invalid length %d of chunk (%d, %d) in %v", length, x, z, r.name)
+	}
+	data := r.data[offset+5 : offset+4+length]
+
+	// The large chunks are stored in the external files,
+	// which are named as c.<x>.<z>.mcc and next to the region.
+	if compression&compressionExternalFlag != 0 {
+		compression &^= compressionExternalFlag
+		if data, err = os.ReadFile(r.externalChunkFile(x, z)); err != nil {
+			return nil, true, fmt.Errorf("Chunk: %v", err)
+		}
+	}
+
+	var reader io.Reader
+	switch compression {
+	case compressionGzip:
+		if reader, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, true, fmt.Errorf("Chunk: %v", err)
+		}
+	case compressionZlib:
+		if reader, err = zlib.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, true, fmt.Errorf("Chunk: %v", err)
+		}
+	case compressionNone:
+		reader = bytes.NewReader(data)
+	case compressionLZ4:
+		return nil, true, fmt.Errorf("Chunk: LZ4 compressed chunk (%d, %d) in %v is not supported", x, z, r.name)
+	default:
+		return nil, true, fmt.Errorf("Chunk: unknown compression type %d of chunk (%d, %d) in %v", compression, x, z, r.name)
+	}
+
+	if err = nbt.NewDecoderWithEncoding(reader, nbt.BigEndian).Decode(&m); err != nil {
+		return nil, true, fmt.Errorf("Chunk: %v", err)
+	}
+	return m, true, nil
+}
+
+// externalChunkFile returns the path of the external file of the chunk at the relative position x and z.
+func (r *Region) externalChunkFile(x, z int32) string {
+	var regionX, regionZ int32
+	_, _ = fmt.Sscanf(filepath.Base(r.name), "r.%d.%d.mca", &regionX, &regionZ)
+	return filepath.Join(filepath.Dir(r.name), fmt.Sprintf("c.%d.%d.mcc", regionX<<5|x&31, regionZ<<5|z&31))
+}
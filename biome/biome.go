@@ -0,0 +1,209 @@
+package biome
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Biome is a biome of Bedrock Edition, which holds its climate and the colors that the blocks are tinted with.
+type Biome struct {
+	// ID is the numeric ID of the biome, which is the one stored in the chunks.
+	ID uint32
+	// Name is the name of the biome, e.g. minecraft:plains.
+	Name string
+	// Temperature and Downfall are the climate of the biome, which decide
+	// whether it rains or snows, and the grass and foliage colors.
+	Temperature, Downfall float64
+	// GrassColor is the color that grass blocks, short grass and other plants are tinted with.
+	GrassColor color.RGBA
+	// FoliageColor is the color that leaves and vines are tinted with.
+	FoliageColor color.RGBA
+	// WaterColor is the color that water is tinted with.
+	WaterColor color.RGBA
+}
+
+// biomeEntry is an entry of biomes.json. grass and foliage are empty if they
+// are computed from the climate, and darkGrass darkens the grass color.
+type biomeEntry struct {
+	ID          uint32  `json:"id"`
+	Name        string  `json:"name"`
+	Temperature float64 `json:"temperature"`
+	Downfall    float64 `json:"downfall"`
+	Grass       string  `json:"grass"`
+	Foliage     string  `json:"foliage"`
+	Water       string  `json:"water"`
+	DarkGrass   bool    `json:"darkGrass"`
+}
+
+// The colors at the corners of the grass and foliage color maps, which are hot and wet, hot and dry,
+// and cold. The colors between them are interpolated.
+var (
+	grassCorners   = [3]uint32{0x47cd33, 0xbfb755, 0x80b497}
+	foliageCorners = [3]uint32{0x1abf00, 0xaea42a, 0x60a17b}
+)
+
+var (
+	// biomesJSON holds the vanilla biomes of Bedrock Edition only. The NetEase additions are not supported,
+	// because their IDs and names are not published anywhere that could be embedded and checked, so the
+	// biomes that only exist in NetEase worlds are unknown to this package unless they are registered by
+	// Register.
+	//
+	//go:embed biomes.json
+	biomesJSON []byte
+	// mu protects biomesByID and biomesByName, which could be modified by Register.
+	mu sync.RWMutex
+	// biomesByID holds all registered biomes, which is indexed by their numeric IDs.
+	biomesByID = map[uint32]Biome{}
+	// biomesByName holds all registered biomes, which is indexed by their names.
+	biomesByName = map[string]Biome{}
+)
+
+func init() {
+	var entries []biomeEntry
+	if err := json.Unmarshal(biomesJSON, &entries); err != nil {
+		panic(fmt.Sprintf("biome: biomes.json: %v", err))
+	}
+	biomesJSON = nil
+
+	for _, entry := range entries {
+		b := Biome{
+			ID:          entry.ID,
+			Name:        entry.Name,
+			Temperature: entry.Temperature,
+			Downfall:    entry.Downfall,
+			WaterColor:  parseColor(entry.Water),
+		}
+
+		grass := climateColor(grassCorners, b.Temperature, b.Downfall)
+		if entry.Grass != "" {
+			grass = rgbOf(parseColor(entry.Grass))
+		}
+		if entry.DarkGrass {
+			grass = ((grass & 0xfefefe) + 0x28340a) >> 1
+		}
+		b.GrassColor = colorOf(grass)
+
+		b.FoliageColor = colorOf(climateColor(foliageCorners, b.Temperature, b.Downfall))
+		if entry.Foliage != "" {
+			b.FoliageColor = parseColor(entry.Foliage)
+		}
+
+		if err := Register(b); err != nil {
+			panic(fmt.Sprintf("biome: biomes.json: %v", err))
+		}
+	}
+}
+
+// Register registers b, so that it could be found by its ID and its name. It is used to register the biomes
+// that are not vanilla, e.g. the custom biomes of add-ons and the ones only exist in NetEase worlds, which are
+// not supported by this package. The name of b could omit the "minecraft:" prefix, and an error is returned if its ID or name
+// is already registered.
+func Register(b Biome) error {
+	if !strings.Contains(b.Name, ":") {
+		b.Name = "minecraft:" + b.Name
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if existing, ok := biomesByID[b.ID]; ok {
+		return fmt.Errorf("Register: ID %d is already registered by %v", b.ID, existing.Name)
+	}
+	if existing, ok := biomesByName[b.Name]; ok {
+		return fmt.Errorf("Register: %v is already registered with ID %d", b.Name, existing.ID)
+	}
+	biomesByID[b.ID], biomesByName[b.Name] = b, b
+	return nil
+}
+
+// ByID returns the biome whose numeric ID is id. found is false if the biome is not registered.
+func ByID(id uint32) (b Biome, found bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	b, found = biomesByID[id]
+	return
+}
+
+// ByName returns the biome whose name is name, which could omit the "minecraft:" prefix.
+// found is false if the biome is not registered.
+func ByName(name string) (b Biome, found bool) {
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	b, found = biomesByName[name]
+	return
+}
+
+// Biomes returns all registered biomes, which are sorted by their numeric IDs.
+func Biomes() []Biome {
+	mu.RLock()
+	result := make([]Biome, 0, len(biomesByID))
+	for _, b := range biomesByID {
+		result = append(result, b)
+	}
+	mu.RUnlock()
+
+	slices.SortFunc(result, func(a, b Biome) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// Name returns the name of the biome whose numeric ID is id, e.g. minecraft:plains for 1.
+// found is false if the biome is not registered, e.g. a custom biome.
+func Name(id uint32) (name string, found bool) {
+	b, found := ByID(id)
+	return b.Name, found
+}
+
+// ID returns the numeric ID of the biome whose name is name. name could omit the
+// "minecraft:" prefix. found is false if the biome is not registered.
+func ID(name string) (id uint32, found bool) {
+	b, found := ByName(name)
+	return b.ID, found
+}
+
+// climateColor interpolates the corners of a color map by temperature and downfall, which is
+// the same as looking up the color map of Java Edition.
+func climateColor(corners [3]uint32, temperature, downfall float64) uint32 {
+	t := min(max(temperature, 0), 1)
+	d := min(max(downfall, 0), 1) * t
+	weights := [3]float64{d, t - d, 1 - t}
+
+	var result uint32
+	for shift := 0; shift <= 16; shift += 8 {
+		var channel float64
+		for i, corner := range corners {
+			channel += weights[i] * float64(corner>>shift&0xff)
+		}
+		result |= uint32(min(channel+0.5, 255)) << shift
+	}
+	return result
+}
+
+// parseColor parses a color in #rrggbb, which panics if s is not valid.
+func parseColor(s string) color.RGBA {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 {
+		panic(fmt.Sprintf("biome: invalid color %#v", s))
+	}
+	return colorOf(uint32(v))
+}
+
+// colorOf converts rgb in 0xrrggbb to an opaque color.
+func colorOf(rgb uint32) color.RGBA {
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
+
+// rgbOf converts c to 0xrrggbb.
+func rgbOf(c color.RGBA) uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
@@ -27,7 +27,8 @@ var (
 	//go:embed block_states.bin
 	blockStates []byte
 
-	// blockProperties ..
+	// blockProperties holds the first seen state properties of each block, which
+	// is used as the default properties when the given ones are not valid.
 	blockProperties = map[string][]block_general.IndexBlockProperty{}
 	// blockStateMapping holds a map for looking up a block entry by the network runtime id it produces.
 	blockStateMapping = map[uint32]blockEntry{}
@@ -45,6 +46,10 @@ func init() {
 	}
 
 	blockStates = nil
+	finishBlockPropertySchema()
+	finishLegacyMapping()
+	finishDowngrade()
+	finishClassification()
 
 	RuntimeIDToState = func(runtimeID uint32) (name string, properties map[string]any, found bool) {
 		s, found := blockStateMapping[runtimeID]
@@ -100,24 +105,8 @@ func decodeToNormalBlockProperties(p []block_general.IndexBlockProperty) map[str
 	result := make(map[string]any)
 
 	for _, value := range p {
-		buf := bytes.NewBuffer(value.Value)
-		r := protocol.NewReader(buf, 0, false)
-
 		key := blockStatesSet[value.KeyIndex]
-		keyName := stringSet[key.KeyNameIndex]
-
-		switch key.KeyType {
-		case block_general.StateKeyTypeString:
-			var ind uint32
-			r.Varuint32(&ind)
-			result[keyName] = stringSet[ind]
-		case block_general.StateKeyTypeInt32:
-			var val int32
-			r.Varint32(&val)
-			result[keyName] = val
-		case block_general.StateKeyTypeByte:
-			result[keyName] = value.Value[0]
-		}
+		result[stringSet[key.KeyNameIndex]] = decodePropertyValue(value)
 	}
 
 	return result
@@ -146,6 +135,7 @@ func registerBlockState(s block_general.IndexBlockState) {
 	if _, ok := blockProperties[realBlock.Name]; !ok {
 		blockProperties[realBlock.Name] = s.BlockProperties
 	}
+	recordBlockProperties(realBlock.Name, s.BlockProperties)
 
 	if block_general.UseNetworkBlockRuntimeID {
 		rid = hash
@@ -156,6 +146,9 @@ func registerBlockState(s block_general.IndexBlockState) {
 	if realBlock.Name == "minecraft:air" {
 		AirRuntimeID = rid
 	}
+	if realBlock.Name == "minecraft:water" && fmt.Sprint(realBlock.Properties["liquid_depth"]) == "0" {
+		WaterRuntimeID = rid
+	}
 
 	blockStateMapping[hash] = blockEntry{
 		block: s,
@@ -0,0 +1,139 @@
+package block
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// Classification holds the physical and lighting
+// categories of a block, which is used by rendering,
+// heightmap computing and lighting.
+type Classification struct {
+	// Solid is true if the block has collision, so that
+	// entities could stand on it, e.g. stone, glass and slab.
+	Solid bool `json:"solid"`
+	// Liquid is true if the block is water or lava.
+	Liquid bool `json:"liquid"`
+	// Transparent is true if the block does not fully occlude
+	// its neighbours when rendering, e.g. glass, leaves and stairs.
+	Transparent bool `json:"transparent"`
+	// LightEmission is the light level (0 to 15) that the block emits.
+	LightEmission uint8 `json:"lightEmission"`
+	// LightFiltering is the light level (0 to 15) that the block takes
+	// from the light passing through it. Opaque blocks have 15.
+	LightFiltering uint8 `json:"lightFiltering"`
+	// Waterloggable is true if the block could be placed
+	// in the same position with water (at layer 1).
+	Waterloggable bool `json:"waterloggable"`
+}
+
+// AirLike reports whether the block has neither collision nor liquid,
+// e.g. air, short grass, flowers and torches. Such a block should not
+// be treated as the ground.
+func (c Classification) AirLike() bool {
+	return !c.Solid && !c.Liquid
+}
+
+// classificationRule sets the categories of the
+// blocks that match Blocks and When.
+type classificationRule struct {
+	// Blocks holds the name patterns of the blocks that this rule matches,
+	// and each pattern follows the syntax of path.Match.
+	Blocks []string `json:"blocks"`
+	// When holds the states that the block must have to match this rule.
+	When map[string]any `json:"when"`
+
+	// The categories to set, and nil means not change.
+	Solid          *bool  `json:"solid"`
+	Liquid         *bool  `json:"liquid"`
+	Transparent    *bool  `json:"transparent"`
+	LightEmission  *uint8 `json:"lightEmission"`
+	LightFiltering *uint8 `json:"lightFiltering"`
+	Waterloggable  *bool  `json:"waterloggable"`
+}
+
+// classificationTable is the JSON representation of block_classification.json.
+type classificationTable struct {
+	Default Classification       `json:"default"`
+	Rules   []classificationRule `json:"rules"`
+}
+
+var (
+	//go:embed block_classification.json
+	blockClassification []byte
+	// blockClassifications holds the classification
+	// of each block, which is indexed by runtime ID.
+	blockClassifications = map[uint32]Classification{}
+)
+
+// Classify returns the classification of the block whose runtime ID is runtimeID.
+// found is false if runtimeID is not exist, and the classification of air is returned.
+func Classify(runtimeID uint32) (result Classification, found bool) {
+	result, found = blockClassifications[runtimeID]
+	if !found {
+		return blockClassifications[AirRuntimeID], false
+	}
+	return result, true
+}
+
+// apply sets the categories of c by r if the block
+// whose name is name and states is states matches r.
+func (r classificationRule) apply(c *Classification, name string, states map[string]any) {
+	var matched bool
+	for _, pattern := range r.Blocks {
+		if matched, _ = path.Match(pattern, name); matched {
+			break
+		}
+	}
+	if !matched {
+		return
+	}
+	for key, value := range r.When {
+		if fmt.Sprint(states[key]) != fmt.Sprint(value) {
+			return
+		}
+	}
+
+	if r.Solid != nil {
+		c.Solid = *r.Solid
+	}
+	if r.Liquid != nil {
+		c.Liquid = *r.Liquid
+	}
+	if r.Transparent != nil {
+		c.Transparent = *r.Transparent
+	}
+	if r.LightEmission != nil {
+		c.LightEmission = *r.LightEmission
+	}
+	if r.LightFiltering != nil {
+		c.LightFiltering = *r.LightFiltering
+	}
+	if r.Waterloggable != nil {
+		c.Waterloggable = *r.Waterloggable
+	}
+}
+
+// finishClassification decodes block_classification.json and classifies
+// all registered blocks. It must be called after all block states are registered.
+func finishClassification() {
+	var table classificationTable
+	if err := json.Unmarshal(blockClassification, &table); err != nil {
+		panic(fmt.Sprintf("finishClassification: Failed to decode block_classification.json; err = %v", err))
+	}
+
+	for _, entry := range blockStateMapping {
+		realBlock := decodeToNormalBlockState(entry.block)
+		c := table.Default
+		// All the matched rules are applied in order,
+		// so the latter one could override the former.
+		for _, rule := range table.Rules {
+			rule.apply(&c, realBlock.Name, realBlock.Properties)
+		}
+		blockClassifications[entry.rid] = c
+	}
+
+	blockClassification = nil
+}
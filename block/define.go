@@ -5,6 +5,12 @@ import block_general "github.com/TriM-Organization/bedrock-world-operator/block/
 var (
 	// AirRuntimeID is the runtime ID of an air block.
 	AirRuntimeID uint32
+	// WaterRuntimeID is the runtime ID of a water source block,
+	// which is the block in layer 1 of a waterlogged block.
+	WaterRuntimeID uint32
+	// UnknownRuntimeID is the runtime ID of "minecraft:unknown", which the blocks
+	// that could not be converted or decoded are redirected to.
+	UnknownRuntimeID = ComputeBlockHash("minecraft:unknown", map[string]any{})
 )
 
 var (
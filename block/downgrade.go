@@ -0,0 +1,438 @@
+package block
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Happy2018new/worldupgrader/blockupgrader"
+	block_general "github.com/TriM-Organization/bedrock-world-operator/block/general"
+)
+
+// downgradeTag is a typed block state value in block_downgrade.json,
+// and only one of its fields is set.
+type downgradeTag struct {
+	Byte   *byte   `json:"byte"`
+	Int    *int32  `json:"int"`
+	String *string `json:"string"`
+}
+
+// downgradeFlatten describes a block state which is flattened into the block name
+// by an upgrade schema, e.g. "minecraft:wool" with color "red" to "minecraft:red_wool".
+type downgradeFlatten struct {
+	Prefix                string            `json:"prefix"`
+	FlattenedProperty     string            `json:"flattenedProperty"`
+	Suffix                string            `json:"suffix"`
+	FlattenedValueRemaps  map[string]string `json:"flattenedValueRemaps"`
+	FlattenedPropertyType string            `json:"flattenedPropertyType"`
+	// Values holds all valid values of FlattenedProperty before flattening.
+	// If it is empty, then any value is considered as valid.
+	Values []string `json:"values"`
+}
+
+// downgradeValueRemap describes a value of a block state which is changed by an upgrade schema.
+type downgradeValueRemap struct {
+	Old downgradeTag `json:"old"`
+	New downgradeTag `json:"new"`
+}
+
+// downgradeStateRemap describes a whole block state which is changed by an upgrade schema.
+type downgradeStateRemap struct {
+	OldState         map[string]downgradeTag `json:"oldState"`
+	NewName          string                  `json:"newName"`
+	NewFlattenedName downgradeFlatten        `json:"newFlattenedName"`
+	NewState         map[string]downgradeTag `json:"newState"`
+	CopiedState      []string                `json:"copiedState"`
+}
+
+// downgradeSchema is an upgrade schema of blockupgrader,
+// which is used to find the block state before upgrading.
+type downgradeSchema struct {
+	Version                string                                      `json:"version"`
+	RenamedIDs             map[string]string                           `json:"renamedIds"`
+	AddedProperties        map[string]map[string]downgradeTag          `json:"addedProperties"`
+	RenamedProperties      map[string]map[string]string                `json:"renamedProperties"`
+	RemappedPropertyValues map[string]map[string][]downgradeValueRemap `json:"remappedPropertyValues"`
+	FlattenedProperties    map[string]downgradeFlatten                 `json:"flattenedProperties"`
+	RemappedStates         map[string][]downgradeStateRemap            `json:"remappedStates"`
+
+	// version is the parsed Version.
+	version int32
+}
+
+// downgradeAdded holds the name patterns of the blocks that added at Version.
+type downgradeAdded struct {
+	Version string   `json:"version"`
+	Blocks  []string `json:"blocks"`
+
+	// version is the parsed Version.
+	version int32
+}
+
+// downgradeTable is the JSON representation of block_downgrade.json.
+type downgradeTable struct {
+	Added   []downgradeAdded  `json:"added"`
+	Schemas []downgradeSchema `json:"schemas"`
+}
+
+// downgradeKey is the key of downgradeCache.
+type downgradeKey struct {
+	runtimeID uint32
+	version   int32
+}
+
+// downgradeResult is the value of downgradeCache.
+type downgradeResult struct {
+	name       string
+	properties map[string]any
+	found      bool
+}
+
+var (
+	//go:embed block_downgrade.json
+	blockDowngrade []byte
+	// downgradeSchemas holds the upgrade schemas in descending order of version.
+	downgradeSchemas []downgradeSchema
+	// downgradeAddedBlocks holds the name patterns of the blocks that added after
+	// Minecraft 1.16.0, which is used to find the blocks not exist at an older version.
+	downgradeAddedBlocks []downgradeAdded
+	// downgradeCache caches the results of Downgrade.
+	downgradeCache sync.Map
+)
+
+// Downgrade converts the block whose runtime ID is runtimeID to the name and state properties
+// that valid at the block version version, which is composed of 4 bytes like CurrentBlockVersion
+// of the chunk package, e.g. 1.20.80.0 is {1, 20, 80, 0}.
+//
+// The conversion is the inverse of blockupgrader.Upgrade, so upgrading the result from version
+// gives the original block again. found is false if runtimeID is not exist, or the block is not
+// exist at version, e.g. the blocks of the features that added after version.
+func Downgrade(runtimeID uint32, version int32) (name string, properties map[string]any, found bool) {
+	key := downgradeKey{runtimeID: runtimeID, version: version}
+	if result, ok := downgradeCache.Load(key); ok {
+		r := result.(downgradeResult)
+		return r.name, maps.Clone(r.properties), r.found
+	}
+
+	name, properties, found = RuntimeIDToState(runtimeID)
+	if found {
+		if downgradeAddedVersion(name) > version {
+			name, properties, found = "", nil, false
+		} else {
+			name, properties = downgradeState(name, properties, version)
+		}
+	}
+
+	downgradeCache.Store(key, downgradeResult{name: name, properties: properties, found: found})
+	return name, maps.Clone(properties), found
+}
+
+// downgradeAddedVersion returns the version that the block named
+// name added in, or 0 if the block is known by all versions.
+func downgradeAddedVersion(name string) (version int32) {
+	for _, added := range downgradeAddedBlocks {
+		for _, pattern := range added.Blocks {
+			if matched, _ := path.Match(pattern, name); matched {
+				version = max(version, added.version)
+			}
+		}
+	}
+	return
+}
+
+// downgradeState converts the block of the current version, whose name is name
+// and state properties is properties, to the block that valid at version.
+func downgradeState(name string, properties map[string]any, version int32) (string, map[string]any) {
+	target := blockupgrader.BlockState{Name: name, Properties: properties}
+
+	for _, s := range downgradeSchemas {
+		if s.version <= version {
+			break
+		}
+		// Each candidate is checked by upgrading it again, and the first one that gives
+		// the original block is used. If none of them works, then the block is not changed
+		// by this schema, or it is not exist at this version and could be kept as it is.
+		for _, candidate := range s.candidates(name, properties) {
+			upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{
+				Name:       candidate.Name,
+				Properties: maps.Clone(candidate.Properties),
+				Version:    s.version,
+			})
+			if upgraded.Name == target.Name && equalDowngradeStates(upgraded.Properties, target.Properties) {
+				name, properties = candidate.Name, candidate.Properties
+				break
+			}
+		}
+	}
+
+	return name, properties
+}
+
+// candidates returns the blocks before upgrading by s that
+// may give the block whose name is name and state is states.
+func (s downgradeSchema) candidates(name string, states map[string]any) (result []blockupgrader.BlockState) {
+	for _, oldName := range slices.Sorted(maps.Keys(s.RemappedStates)) {
+		for _, remap := range s.RemappedStates[oldName] {
+			result = append(result, remap.candidates(oldName, name, states)...)
+		}
+	}
+
+	for _, oldName := range slices.Sorted(maps.Keys(s.RenamedIDs)) {
+		if s.RenamedIDs[oldName] == name {
+			result = append(result, blockupgrader.BlockState{Name: oldName, Properties: s.oldProperties(oldName, states)})
+		}
+	}
+	for _, oldName := range slices.Sorted(maps.Keys(s.FlattenedProperties)) {
+		info := s.FlattenedProperties[oldName]
+		for _, value := range info.values(name) {
+			properties := s.oldProperties(oldName, states)
+			properties[info.FlattenedProperty] = value
+			result = append(result, blockupgrader.BlockState{Name: oldName, Properties: properties})
+		}
+	}
+
+	return append(result, blockupgrader.BlockState{Name: name, Properties: s.oldProperties(name, states)})
+}
+
+// oldProperties returns the state properties before upgrading by s, where
+// oldName is the block name before upgrading and states is the upgraded one.
+func (s downgradeSchema) oldProperties(oldName string, states map[string]any) map[string]any {
+	result := maps.Clone(states)
+	if result == nil {
+		result = make(map[string]any)
+	}
+	remapped := s.RemappedPropertyValues[oldName]
+	renamed := s.RenamedProperties[oldName]
+
+	for oldKey, pairs := range remapped {
+		if _, ok := renamed[oldKey]; ok {
+			continue
+		}
+		if value, ok := result[oldKey]; ok {
+			result[oldKey] = oldPropertyValue(pairs, value)
+		}
+	}
+	for oldKey, newKey := range renamed {
+		if value, ok := result[newKey]; ok {
+			delete(result, newKey)
+			result[oldKey] = oldPropertyValue(remapped[oldKey], value)
+		}
+	}
+	for key, tag := range s.AddedProperties[oldName] {
+		if result[key] == tag.value() {
+			delete(result, key)
+		}
+	}
+
+	return result
+}
+
+// candidates returns the blocks before upgrading by r, where oldName is the name of the
+// block that r remaps from, and name and states describe the upgraded block.
+func (r downgradeStateRemap) candidates(oldName string, name string, states map[string]any) (result []blockupgrader.BlockState) {
+	var flattened []any
+	switch {
+	case len(r.NewName) > 0:
+		if r.NewName != name {
+			return nil
+		}
+		flattened = []any{nil}
+	default:
+		if flattened = r.NewFlattenedName.values(name); len(flattened) == 0 {
+			return nil
+		}
+	}
+
+	for key, tag := range r.NewState {
+		if states[key] != tag.value() {
+			return nil
+		}
+	}
+	for key := range states {
+		if _, ok := r.NewState[key]; !ok && !slices.Contains(r.CopiedState, key) {
+			return nil
+		}
+	}
+
+	for _, value := range flattened {
+		properties := make(map[string]any)
+		for key, tag := range r.OldState {
+			properties[key] = tag.value()
+		}
+		for _, key := range r.CopiedState {
+			if value, ok := states[key]; ok {
+				properties[key] = value
+			}
+		}
+		if value != nil {
+			properties[r.NewFlattenedName.FlattenedProperty] = value
+		}
+		result = append(result, blockupgrader.BlockState{Name: oldName, Properties: properties})
+	}
+	return result
+}
+
+// values returns all values of the flattened property that could give the block named name.
+func (f downgradeFlatten) values(name string) (result []any) {
+	if len(f.FlattenedProperty) == 0 || len(name) < len(f.Prefix)+len(f.Suffix) {
+		return nil
+	}
+	if !strings.HasPrefix(name, f.Prefix) || !strings.HasSuffix(name, f.Suffix) {
+		return nil
+	}
+	embed := name[len(f.Prefix) : len(name)-len(f.Suffix)]
+
+	var keys []string
+	for _, key := range slices.Sorted(maps.Keys(f.FlattenedValueRemaps)) {
+		if f.FlattenedValueRemaps[key] == embed {
+			keys = append(keys, key)
+		}
+	}
+	if _, ok := f.FlattenedValueRemaps[embed]; !ok && len(embed) > 0 {
+		keys = append(keys, embed)
+	}
+
+	for _, key := range keys {
+		if len(f.Values) > 0 && !slices.Contains(f.Values, key) {
+			continue
+		}
+		switch f.FlattenedPropertyType {
+		case "int":
+			if number, err := strconv.ParseInt(key, 10, 32); err == nil {
+				result = append(result, int32(number))
+			}
+		case "byte":
+			if number, err := strconv.ParseUint(key, 10, 8); err == nil {
+				result = append(result, byte(number))
+			}
+		default:
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// value returns the value that t holds.
+func (t downgradeTag) value() any {
+	switch {
+	case t.Byte != nil:
+		return *t.Byte
+	case t.Int != nil:
+		return *t.Int
+	case t.String != nil:
+		return *t.String
+	}
+	return nil
+}
+
+// oldPropertyValue returns the value before remapping by pairs, where value is the remapped one.
+func oldPropertyValue(pairs []downgradeValueRemap, value any) any {
+	var from []any
+	olds := make(map[any]bool)
+	for _, pair := range pairs {
+		olds[pair.Old.value()] = true
+		if pair.New.value() == value {
+			from = append(from, pair.Old.value())
+		}
+	}
+	if len(from) == 0 {
+		return value
+	}
+	// value must come from another value if itself is remapped,
+	// or the values before and after remapping have different types.
+	if olds[value] || stateKeyTypeOf(from[0]) != stateKeyTypeOf(value) {
+		return from[0]
+	}
+	// The remap that shifts the values (e.g. 0 to 5, 1 to 4 and 2 to 3) is the real change,
+	// but the one that only redirects the invalid values (e.g. 6 and 7 to 0) is not, and
+	// value is more likely unchanged for the latter case.
+	for _, pair := range pairs {
+		if olds[pair.New.value()] {
+			return from[0]
+		}
+	}
+	return value
+}
+
+// stateKeyTypeOf returns the type of the block state value v.
+func stateKeyTypeOf(v any) uint8 {
+	switch v.(type) {
+	case int32:
+		return block_general.StateKeyTypeInt32
+	case byte:
+		return block_general.StateKeyTypeByte
+	default:
+		return block_general.StateKeyTypeString
+	}
+}
+
+// equalDowngradeStates checks whether the two block states are the same.
+func equalDowngradeStates(a map[string]any, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBlockVersion parses a version string like "1.21.60.28" to
+// a block version. The missing parts of version are treated as 0.
+func parseBlockVersion(version string) (result int32, err error) {
+	parts := strings.Split(version, ".")
+	if len(parts) > 4 {
+		return 0, fmt.Errorf("parseBlockVersion: Invalid version %#v", version)
+	}
+	for index := range 4 {
+		var number uint64
+		if index < len(parts) {
+			if number, err = strconv.ParseUint(parts[index], 10, 8); err != nil {
+				return 0, fmt.Errorf("parseBlockVersion: Invalid version %#v; err = %v", version, err)
+			}
+		}
+		result = result<<8 | int32(number)
+	}
+	return result, nil
+}
+
+// finishDowngrade decodes block_downgrade.json and builds
+// downgradeSchemas and downgradeAddedBlocks.
+func finishDowngrade() {
+	var table downgradeTable
+	if err := json.Unmarshal(blockDowngrade, &table); err != nil {
+		panic(fmt.Sprintf("finishDowngrade: Failed to decode block_downgrade.json; err = %v", err))
+	}
+
+	for _, s := range table.Schemas {
+		version, err := parseBlockVersion(s.Version)
+		if err != nil {
+			panic(fmt.Sprintf("finishDowngrade: %v", err))
+		}
+		s.version = version
+		downgradeSchemas = append(downgradeSchemas, s)
+	}
+	slices.SortStableFunc(downgradeSchemas, func(a downgradeSchema, b downgradeSchema) int {
+		return cmp.Compare(b.version, a.version)
+	})
+
+	for _, added := range table.Added {
+		version, err := parseBlockVersion(added.Version)
+		if err != nil {
+			panic(fmt.Sprintf("finishDowngrade: %v", err))
+		}
+		added.version = version
+		downgradeAddedBlocks = append(downgradeAddedBlocks, added)
+	}
+
+	blockDowngrade = nil
+}
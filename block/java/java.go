@@ -0,0 +1,429 @@
+package block_java
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	block_general "github.com/TriM-Organization/bedrock-world-operator/block/general"
+)
+
+// State is a Java Edition block state, which holds
+// the name of the block and its properties.
+type State struct {
+	Name       string
+	Properties map[string]string
+}
+
+// ParseState parses a Java Edition block state string, such as
+// "minecraft:oak_stairs[facing=north,half=top]" or "stone".
+// The "minecraft:" prefix of the block name could be omitted.
+func ParseState(state string) (result State, err error) {
+	state = strings.TrimSpace(state)
+	result.Properties = make(map[string]string)
+
+	name, properties, hasProperties := strings.Cut(state, "[")
+	result.Name = fixBlockName(name)
+	if len(name) == 0 {
+		return State{}, fmt.Errorf("ParseState: Block name of %#v is empty", state)
+	}
+	if !hasProperties {
+		return result, nil
+	}
+
+	properties, ok := strings.CutSuffix(properties, "]")
+	if !ok {
+		return State{}, fmt.Errorf("ParseState: Properties of %#v are not closed", state)
+	}
+	if len(strings.TrimSpace(properties)) == 0 {
+		return result, nil
+	}
+
+	for _, pair := range strings.Split(properties, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || len(key) == 0 || len(value) == 0 {
+			return State{}, fmt.Errorf("ParseState: Invalid property %#v in %#v", pair, state)
+		}
+		result.Properties[key] = value
+	}
+
+	return result, nil
+}
+
+// String returns the string represent of s, e.g. "minecraft:oak_stairs[facing=north,half=top]".
+// The properties are sorted by their names, so the result is stable.
+func (s State) String() string {
+	if len(s.Properties) == 0 {
+		return s.Name
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	pairs := make([]string, len(keys))
+	for index, key := range keys {
+		pairs[index] = key + "=" + s.Properties[key]
+	}
+	return s.Name + "[" + strings.Join(pairs, ",") + "]"
+}
+
+// ToBedrock converts the Java Edition block state s to Bedrock block runtime ID.
+//
+// lost describes the information of s that could not be represented by the result
+// block, and it is empty if the conversion is lossless. Each element of lost is
+// either a Java property in the form of "key=value", or a message that describes
+// the lost.
+//
+// found is false if s could not be converted to any Bedrock block.
+func ToBedrock(s State) (runtimeID uint32, lost []string, found bool) {
+	s.Name = fixBlockName(s.Name)
+	bedrockName := s.Name
+
+	entry, wildcard, matched := findJavaEntry(s)
+	if matched {
+		bedrockName = fillPattern(entry.Bedrock, wildcard)
+		if len(entry.Lossy) > 0 {
+			lost = append(lost, entry.Lossy)
+		}
+	}
+
+	keyTypes, states, ok := bedrockSchema(bedrockName)
+	if !ok {
+		return 0, nil, false
+	}
+	consumed := make(map[string]bool)
+
+	for key := range entry.When {
+		consumed[key] = true
+	}
+	for key, value := range entry.States {
+		if converted, ok := toBedrockValue(value, keyTypes[key]); ok {
+			states[key] = converted
+		}
+	}
+
+	for _, rule := range entry.rules {
+		if len(rule.Bits) > 0 {
+			var bits int32
+			for _, pair := range rule.Bits {
+				key := javaString(pair[0])
+				consumed[key] = true
+				if s.Properties[key] == "true" {
+					bits += int32(toFloat(pair[1]))
+				}
+			}
+			if converted, ok := toBedrockValue(bits, keyTypes[rule.Bedrock]); ok {
+				states[rule.Bedrock] = converted
+			}
+			continue
+		}
+
+		value, ok := s.Properties[rule.Java]
+		if len(rule.Java) == 0 || !ok {
+			continue
+		}
+		consumed[rule.Java] = true
+
+		switch {
+		case rule.Ignore:
+		case len(rule.Bedrock) == 0:
+			if value != rule.Default {
+				lost = append(lost, rule.Java+"="+value)
+			}
+		default:
+			converted, ok := ruleToBedrock(rule, value, keyTypes[rule.Bedrock])
+			if !ok {
+				lost = append(lost, rule.Java+"="+value)
+				continue
+			}
+			states[rule.Bedrock] = converted
+		}
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if consumed[key] {
+			continue
+		}
+		// Properties that no rule refers to are copied directly,
+		// as long as the Bedrock block has the state of the same name.
+		keyType, ok := keyTypes[key]
+		if ok {
+			var converted any
+			if converted, ok = toBedrockValue(s.Properties[key], keyType); ok {
+				states[key] = converted
+			}
+		}
+		if !ok {
+			lost = append(lost, key+"="+s.Properties[key])
+		}
+	}
+
+	runtimeID, found = block.StateToRuntimeID(bedrockName, states)
+	if !found {
+		return 0, nil, false
+	}
+	if _, realStates, _ := block.RuntimeIDToState(runtimeID); !equalStates(realStates, states) {
+		lost = append(lost, fmt.Sprintf("invalid Bedrock states %v, fall back to the default states", states))
+	}
+
+	return runtimeID, lost, true
+}
+
+// FromBedrock converts the Bedrock block whose runtime ID is runtimeID to Java Edition block state.
+//
+// lost describes the Bedrock states that could not be represented by the result block, and it is
+// empty if the conversion is lossless. Each element of lost is in the form of "key=value".
+//
+// found is false if runtimeID is not exist. Note that the name of the Bedrock block is used as the
+// Java block name if there is no mapping for this block, so the name of result block may not exist
+// in Java Edition.
+func FromBedrock(runtimeID uint32) (result State, lost []string, found bool) {
+	name, states, found := block.RuntimeIDToState(runtimeID)
+	if !found {
+		return State{}, nil, false
+	}
+
+	result = State{Name: name, Properties: make(map[string]string)}
+	consumed := make(map[string]bool)
+
+	entry, javaName, matched := findBedrockEntry(name, states)
+	if matched {
+		result.Name = javaName
+	}
+	for key, value := range entry.When {
+		result.Properties[key] = value
+	}
+	for key := range entry.States {
+		consumed[key] = true
+	}
+
+	for _, rule := range entry.rules {
+		if len(rule.Bits) > 0 {
+			value, ok := states[rule.Bedrock]
+			if !ok {
+				continue
+			}
+			consumed[rule.Bedrock] = true
+			bits := int32(toFloat(value))
+			for _, pair := range rule.Bits {
+				result.Properties[javaString(pair[0])] = strconv.FormatBool(bits&int32(toFloat(pair[1])) != 0)
+			}
+			continue
+		}
+
+		if len(rule.Bedrock) == 0 {
+			if len(rule.Java) > 0 && len(rule.Default) > 0 {
+				result.Properties[rule.Java] = rule.Default
+			}
+			continue
+		}
+
+		value, ok := states[rule.Bedrock]
+		if !ok {
+			continue
+		}
+		consumed[rule.Bedrock] = true
+		if rule.Ignore || len(rule.Java) == 0 {
+			continue
+		}
+
+		converted, ok := ruleToJava(rule, value)
+		if !ok {
+			lost = append(lost, fmt.Sprintf("%s=%v", rule.Bedrock, value))
+			continue
+		}
+		result.Properties[rule.Java] = converted
+	}
+
+	_, defaultStates, _ := bedrockSchema(name)
+	keys := make([]string, 0, len(states))
+	for key := range states {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if consumed[key] || states[key] == defaultStates[key] {
+			continue
+		}
+		lost = append(lost, fmt.Sprintf("%s=%v", key, states[key]))
+	}
+
+	return result, lost, true
+}
+
+// fixBlockName makes name lowercase and
+// adds the "minecraft:" prefix if needed.
+func fixBlockName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "minecraft:") {
+		name = "minecraft:" + name
+	}
+	return name
+}
+
+// bedrockSchema returns the type of each state of the Bedrock block whose name
+// is name, and also a copy of its default states. ok is false if the block is
+// not exist.
+func bedrockSchema(name string) (keyTypes map[string]uint8, defaultStates map[string]any, ok bool) {
+	properties, ok := block.Properties(name)
+	if !ok {
+		return nil, nil, false
+	}
+
+	keyTypes = make(map[string]uint8)
+	for _, value := range properties {
+		keyTypes[value.Name] = value.Type
+	}
+
+	defaultStates = make(map[string]any)
+	if runtimeID, found := block.StateToRuntimeID(name, nil); found {
+		_, states, _ := block.RuntimeIDToState(runtimeID)
+		for key, value := range states {
+			defaultStates[key] = value
+		}
+	}
+
+	return keyTypes, defaultStates, true
+}
+
+// ruleToBedrock converts the Java value to Bedrock value by rule.
+func ruleToBedrock(rule propertyRule, value string, keyType uint8) (any, bool) {
+	if len(rule.Values) > 0 {
+		for _, pair := range rule.Values {
+			if javaString(pair[0]) == value {
+				return toBedrockValue(pair[1], keyType)
+			}
+		}
+		return nil, false
+	}
+
+	if rule.Offset != 0 {
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, false
+		}
+		return toBedrockValue(int32(number)+rule.Offset, keyType)
+	}
+
+	return toBedrockValue(value, keyType)
+}
+
+// ruleToJava converts the Bedrock value to Java value by rule.
+func ruleToJava(rule propertyRule, value any) (string, bool) {
+	if len(rule.Values) > 0 {
+		for _, pair := range rule.Values {
+			if converted, ok := toBedrockValue(pair[1], stateKeyType(value)); ok && converted == value {
+				return javaString(pair[0]), true
+			}
+		}
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int32:
+		return strconv.FormatInt(int64(v-rule.Offset), 10), true
+	case byte:
+		if rule.Offset != 0 {
+			return strconv.FormatInt(int64(int32(v)-rule.Offset), 10), true
+		}
+		return strconv.FormatBool(v != 0), true
+	}
+	return "", false
+}
+
+// toBedrockValue converts value to the Go type of Bedrock state whose type is keyType.
+// value could be a string from Java, or a string, number or bool from the mapping table.
+func toBedrockValue(value any, keyType uint8) (any, bool) {
+	switch keyType {
+	case block_general.StateKeyTypeString:
+		return javaString(value), true
+	case block_general.StateKeyTypeInt32:
+		if v, ok := value.(string); ok {
+			number, err := strconv.ParseInt(v, 10, 32)
+			return int32(number), err == nil
+		}
+		return int32(toFloat(value)), true
+	case block_general.StateKeyTypeByte:
+		if v, ok := value.(string); ok {
+			switch v {
+			case "true":
+				return byte(1), true
+			case "false":
+				return byte(0), true
+			}
+			number, err := strconv.ParseUint(v, 10, 8)
+			return byte(number), err == nil
+		}
+		return byte(toFloat(value)), true
+	}
+	return nil, false
+}
+
+// stateKeyType returns the type of the Bedrock state value v.
+func stateKeyType(v any) uint8 {
+	switch v.(type) {
+	case int32:
+		return block_general.StateKeyTypeInt32
+	case byte:
+		return block_general.StateKeyTypeByte
+	default:
+		return block_general.StateKeyTypeString
+	}
+}
+
+// javaString returns the string represent of v, which is used as Java value.
+func javaString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// toFloat converts the number (or bool) v to float64.
+func toFloat(v any) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int32:
+		return float64(val)
+	case byte:
+		return float64(val)
+	case bool:
+		if val {
+			return 1
+		}
+	}
+	return 0
+}
+
+// equalStates checks whether the two Bedrock states are the same.
+func equalStates(a map[string]any, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package block_java
+
+import (
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+func TestParseState(t *testing.T) {
+	s, err := ParseState(" oak_stairs[ half=top , facing=north ] ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "minecraft:oak_stairs[facing=north,half=top]"; s.String() != expected {
+		t.Fatalf("expected %s, but got %s", expected, s.String())
+	}
+
+	for _, state := range []string{"", "stone[", "stone[facing]", "stone[=north]"} {
+		if _, err = ParseState(state); err == nil {
+			t.Fatalf("expected an error for %#v", state)
+		}
+	}
+}
+
+func TestToBedrockRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		java    string
+		bedrock string
+		states  map[string]any
+		back    string
+	}{
+		{"stone", "minecraft:stone", map[string]any{}, "minecraft:stone"},
+		{"red_wool", "minecraft:red_wool", map[string]any{}, "minecraft:red_wool"},
+		{"oak_log[axis=x]", "minecraft:oak_log", map[string]any{"pillar_axis": "x"}, "minecraft:oak_log[axis=x]"},
+		{
+			"oak_stairs[facing=east,half=top,shape=straight,waterlogged=false]",
+			"minecraft:oak_stairs", map[string]any{"upside_down_bit": byte(1), "weirdo_direction": int32(0)},
+			"minecraft:oak_stairs[facing=east,half=top,waterlogged=false]",
+		},
+		{
+			"oak_slab[type=double,waterlogged=false]",
+			"minecraft:oak_double_slab", map[string]any{"minecraft:vertical_half": "bottom"},
+			"minecraft:oak_slab[type=double,waterlogged=false]",
+		},
+		{
+			"chest[facing=west,type=single,waterlogged=false]",
+			"minecraft:chest", map[string]any{"minecraft:cardinal_direction": "west"},
+			"minecraft:chest[facing=west,waterlogged=false]",
+		},
+		{"water[level=0]", "minecraft:water", map[string]any{"liquid_depth": int32(0)}, "minecraft:water[level=0]"},
+	} {
+		state, err := ParseState(test.java)
+		if err != nil {
+			t.Fatal(err)
+		}
+		runtimeID, lost, found := ToBedrock(state)
+		if !found {
+			t.Fatalf("%s is not converted", test.java)
+		}
+		if len(lost) != 0 {
+			t.Fatalf("expected %s to be converted losslessly, but lost %v", test.java, lost)
+		}
+
+		expected, found := block.StateToRuntimeID(test.bedrock, test.states)
+		if !found {
+			t.Fatalf("%s %v is not found", test.bedrock, test.states)
+		}
+		if runtimeID != expected {
+			name, states, _ := block.RuntimeIDToState(runtimeID)
+			t.Fatalf("expected %s to be converted to %s %v, but got %s %v", test.java, test.bedrock, test.states, name, states)
+		}
+
+		back, lost, found := FromBedrock(runtimeID)
+		if !found || len(lost) != 0 || back.String() != test.back {
+			t.Fatalf("expected %s %v to be converted back to %s, but got %s (lost %v)", test.bedrock, test.states, test.back, back.String(), lost)
+		}
+	}
+}
+
+func TestToBedrockNotFound(t *testing.T) {
+	if _, _, found := ToBedrock(State{Name: "minecraft:not_a_block"}); found {
+		t.Fatal("expected minecraft:not_a_block not to be converted")
+	}
+}
@@ -0,0 +1,189 @@
+package block_java
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// propertyRule describes how a single Java Edition block property
+// is converted to (or from) a Bedrock Edition block state.
+type propertyRule struct {
+	// Java is the name of the Java property that this rule consumes.
+	// It is empty if this rule only refers to a Bedrock state.
+	Java string `json:"java"`
+	// Bedrock is the name of the Bedrock state that this rule produces.
+	// It is empty if the Java property has no Bedrock equivalent.
+	Bedrock string `json:"bedrock"`
+	// Values holds the value pairs of [Java value, Bedrock value]. If it is
+	// empty, then the value is converted directly by the type of the Bedrock
+	// state, and Offset is added to the number values.
+	Values [][2]any `json:"values"`
+	// Offset is added to the Java value to get the Bedrock value.
+	// It is only used when Values is empty.
+	Offset int32 `json:"offset"`
+	// Bits holds the pairs of [Java property, bit value]. Each Java property
+	// is a boolean, and the Bedrock value is the sum of the bits that are true.
+	Bits [][2]any `json:"bits"`
+	// Default holds the only Java value that could be dropped without loss.
+	// The rule have no Bedrock equivalent if Default is not empty.
+	Default string `json:"default"`
+	// Ignore means the property (or state) could be derived from the world,
+	// so it is silently dropped when converting.
+	Ignore bool `json:"ignore"`
+}
+
+// blockEntry describes how a Java Edition block is converted to
+// (or from) a Bedrock Edition block.
+type blockEntry struct {
+	// Java is the Java block name that this entry matches. It could contains
+	// one "*" which matches any string, e.g. "minecraft:*_stairs".
+	Java string `json:"java"`
+	// When holds the Java properties that the block must have to match this entry.
+	// When converting from Bedrock, these properties are set on the Java block.
+	When map[string]string `json:"when"`
+	// Bedrock is the Bedrock block name that this entry produces.
+	// The "*" in it is replaced by the part matched in Java.
+	Bedrock string `json:"bedrock"`
+	// States holds the fixed Bedrock states that this entry produces.
+	// When converting from Bedrock, the block must have these states.
+	States map[string]any `json:"states"`
+	// Rules holds the names of the property rules that this entry uses.
+	Rules []string `json:"rules"`
+	// Reverse is the Java block name to use when converting from Bedrock,
+	// which is only needed if Java contains "*" but Bedrock not.
+	Reverse string `json:"reverse"`
+	// OneWay means this entry is only used when converting from Java.
+	OneWay bool `json:"oneway"`
+	// Lossy describes the information that always lost when converting from
+	// Java, e.g. the information that Bedrock stores in block entity.
+	Lossy string `json:"lossy"`
+
+	// rules is the resolved Rules.
+	rules []propertyRule
+}
+
+// mappingTable is the JSON representation of java_block_mapping.json.
+type mappingTable struct {
+	Rules  map[string][]propertyRule `json:"rules"`
+	Blocks []blockEntry              `json:"blocks"`
+}
+
+var (
+	//go:embed java_block_mapping.json
+	javaBlockMapping []byte
+	// blockEntries holds all block entries in the order of
+	// priority, and the first matched entry is used.
+	blockEntries []blockEntry
+)
+
+func init() {
+	var table mappingTable
+	if err := json.Unmarshal(javaBlockMapping, &table); err != nil {
+		panic(fmt.Sprintf("init: Failed to decode java_block_mapping.json; err = %v", err))
+	}
+
+	for _, entry := range table.Blocks {
+		for _, name := range entry.Rules {
+			rules, ok := table.Rules[name]
+			if !ok {
+				panic(fmt.Sprintf("init: Block entry %#v refers to an unknown rule %#v", entry.Java, name))
+			}
+			entry.rules = append(entry.rules, rules...)
+		}
+		blockEntries = append(blockEntries, entry)
+	}
+
+	javaBlockMapping = nil
+}
+
+// matchPattern matches name with pattern, which could contains one "*".
+// The part of name that matched by "*" is returned as wildcard.
+func matchPattern(pattern string, name string) (wildcard string, ok bool) {
+	prefix, suffix, hasWildcard := strings.Cut(pattern, "*")
+	if !hasWildcard {
+		return "", pattern == name
+	}
+	if len(name) < len(prefix)+len(suffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return "", false
+	}
+	return name[len(prefix) : len(name)-len(suffix)], true
+}
+
+// fillPattern replaces the "*" in pattern with wildcard.
+func fillPattern(pattern string, wildcard string) string {
+	return strings.Replace(pattern, "*", wildcard, 1)
+}
+
+// findJavaEntry finds the first block entry that matches the Java block s.
+func findJavaEntry(s State) (entry blockEntry, wildcard string, found bool) {
+	for _, entry := range blockEntries {
+		wildcard, ok := matchPattern(entry.Java, s.Name)
+		if !ok {
+			continue
+		}
+		if !matchJavaProperties(entry.When, s.Properties) {
+			continue
+		}
+		return entry, wildcard, true
+	}
+	return blockEntry{}, "", false
+}
+
+// findBedrockEntry finds the first block entry that could convert
+// the Bedrock block whose name is name and states is states to Java.
+func findBedrockEntry(name string, states map[string]any) (entry blockEntry, javaName string, found bool) {
+	for _, entry := range blockEntries {
+		if entry.OneWay {
+			continue
+		}
+
+		wildcard, ok := matchPattern(entry.Bedrock, name)
+		if !ok {
+			continue
+		}
+		if !matchBedrockStates(entry.States, states) {
+			continue
+		}
+
+		switch {
+		case !strings.Contains(entry.Java, "*"):
+			javaName = entry.Java
+		case strings.Contains(entry.Bedrock, "*"):
+			javaName = fillPattern(entry.Java, wildcard)
+		case len(entry.Reverse) > 0:
+			javaName = entry.Reverse
+		default:
+			continue
+		}
+
+		return entry, javaName, true
+	}
+	return blockEntry{}, "", false
+}
+
+// matchJavaProperties checks whether properties holds all the properties in when.
+func matchJavaProperties(when map[string]string, properties map[string]string) bool {
+	for key, value := range when {
+		if properties[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBedrockStates checks whether states holds all the states in fixed.
+func matchBedrockStates(fixed map[string]any, states map[string]any) bool {
+	for key, value := range fixed {
+		current, ok := states[key]
+		if !ok {
+			return false
+		}
+		expected, ok := toBedrockValue(value, stateKeyType(current))
+		if !ok || expected != current {
+			return false
+		}
+	}
+	return true
+}
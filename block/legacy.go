@@ -0,0 +1,190 @@
+package block
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Happy2018new/worldupgrader/blockupgrader"
+)
+
+// legacyBlockVersion is the block version of the states that decoded
+// from the legacy metadata, which is the version of Minecraft 1.12.0.
+const legacyBlockVersion int32 = 1<<24 | 12<<16
+
+// legacyField describes how a block state is decoded from
+// the bits of the legacy metadata.
+type legacyField struct {
+	// State is the name of the block state.
+	State string `json:"state"`
+	// Shift and Bits describe the bits of the metadata that
+	// holds the value, which is (meta >> Shift) & (1<<Bits - 1).
+	Shift uint8 `json:"shift"`
+	Bits  uint8 `json:"bits"`
+	// Values holds the string values of the state, which is indexed
+	// by the bits of the metadata. If it is empty, then the bits is
+	// used as the value directly, and Type decides the type of it.
+	Values []string `json:"values"`
+	// Type is "int" or "byte", and it is only used when Values is empty.
+	Type string `json:"type"`
+}
+
+// legacyBlock describes a block of the legacy numeric ID.
+type legacyBlock struct {
+	// ID is the legacy numeric ID of the block.
+	ID uint16 `json:"id"`
+	// Name is the name of the block in Minecraft 1.12.0.
+	Name string `json:"name"`
+	// Fields holds the names of the field groups that the metadata of this block uses.
+	Fields []string `json:"fields"`
+
+	// fields is the resolved Fields.
+	fields []legacyField
+}
+
+// legacyMappingTable is the JSON representation of legacy_block_mapping.json.
+type legacyMappingTable struct {
+	Fields map[string][]legacyField `json:"fields"`
+	Blocks []legacyBlock            `json:"blocks"`
+}
+
+// legacyState is the legacy numeric ID and metadata of a block.
+type legacyState struct {
+	id   uint16
+	meta uint16
+}
+
+var (
+	//go:embed legacy_block_mapping.json
+	legacyBlockMapping []byte
+	// legacyBlocks holds all blocks of the legacy mapping table, which is indexed by the numeric ID.
+	legacyBlocks = map[uint16]legacyBlock{}
+	// legacyBlockIDs maps the names of the legacy blocks to their numeric IDs.
+	legacyBlockIDs = map[string]uint16{}
+	// legacyStateMapping holds a map for looking up the smallest legacy numeric ID and
+	// metadata that exactly produces a runtime ID.
+	legacyStateMapping = map[uint32]legacyState{}
+)
+
+// LegacyToRuntimeID converts the legacy numeric block ID and metadata, which are used by the
+// worlds before Minecraft 1.13.0 and many old tools, to the runtime ID of the current block.
+//
+// The block is upgraded to the current version by blockupgrader. If meta is not valid for the
+// block, then the runtime ID of the default state of the block is returned, which is the same
+// as StateToRuntimeID. found is false if id is unknown or the upgraded block is not exist.
+func LegacyToRuntimeID(id uint16, meta uint16) (runtimeID uint32, found bool) {
+	name, properties, ok := legacyToState(id, meta)
+	if !ok {
+		return 0, false
+	}
+	return StateToRuntimeID(name, properties)
+}
+
+// LegacyNameToRuntimeID is the same as LegacyToRuntimeID, but the block is given by its name in Minecraft
+// 1.12.0 instead of its numeric ID, e.g. minecraft:wool, which is used by the old superflat presets. name
+// could omit the "minecraft:" prefix. found is false if name is not a legacy block.
+func LegacyNameToRuntimeID(name string, meta uint16) (runtimeID uint32, found bool) {
+	if !strings.HasPrefix(name, "minecraft:") {
+		name = "minecraft:" + name
+	}
+	id, ok := legacyBlockIDs[name]
+	if !ok {
+		return 0, false
+	}
+	return LegacyToRuntimeID(id, meta)
+}
+
+// RuntimeIDToLegacy converts the runtime ID of a block to its legacy numeric block ID and metadata.
+// If several legacy blocks produce the same runtime ID, then the one with the smallest ID (and then
+// the smallest metadata) is returned.
+//
+// found is false if there is no legacy block that exactly produces runtimeID, e.g. the blocks that
+// were added after Minecraft 1.12.0.
+func RuntimeIDToLegacy(runtimeID uint32) (id uint16, meta uint16, found bool) {
+	s, found := legacyStateMapping[runtimeID]
+	return s.id, s.meta, found
+}
+
+// legacyToState decodes the legacy numeric block ID and metadata to the name and state
+// properties of the current block. ok is false if id is unknown or meta is out of range.
+func legacyToState(id uint16, meta uint16) (name string, properties map[string]any, ok bool) {
+	b, ok := legacyBlocks[id]
+	if !ok {
+		return "", nil, false
+	}
+
+	states := make(map[string]any)
+	for _, field := range b.fields {
+		value := (meta >> field.Shift) & (1<<field.Bits - 1)
+		switch {
+		case len(field.Values) > 0:
+			if int(value) >= len(field.Values) {
+				return "", nil, false
+			}
+			states[field.State] = field.Values[value]
+		case field.Type == "byte":
+			states[field.State] = byte(value)
+		default:
+			states[field.State] = int32(value)
+		}
+	}
+
+	upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{
+		Name:       b.Name,
+		Properties: states,
+		Version:    legacyBlockVersion,
+	})
+
+	// The states that added after Minecraft 1.12.0 but not
+	// handled by blockupgrader are filled with the default value.
+	properties = decodeToNormalBlockProperties(blockProperties[upgraded.Name])
+	for key, value := range upgraded.Properties {
+		if _, ok := properties[key]; ok {
+			properties[key] = value
+		}
+	}
+
+	return upgraded.Name, properties, true
+}
+
+// finishLegacyMapping decodes the legacy mapping table and builds legacyStateMapping.
+// It must be called after all block states are registered.
+func finishLegacyMapping() {
+	var table legacyMappingTable
+	if err := json.Unmarshal(legacyBlockMapping, &table); err != nil {
+		panic(fmt.Sprintf("finishLegacyMapping: Failed to decode legacy_block_mapping.json; err = %v", err))
+	}
+
+	for _, b := range table.Blocks {
+		for _, name := range b.Fields {
+			fields, ok := table.Fields[name]
+			if !ok {
+				panic(fmt.Sprintf("finishLegacyMapping: Legacy block %d refers to an unknown field group %#v", b.ID, name))
+			}
+			b.fields = append(b.fields, fields...)
+		}
+		legacyBlocks[b.ID] = b
+		if _, ok := legacyBlockIDs[b.Name]; !ok {
+			legacyBlockIDs[b.Name] = b.ID
+		}
+	}
+
+	for _, b := range table.Blocks {
+		for meta := uint16(0); meta < 16; meta++ {
+			name, properties, ok := legacyToState(b.ID, meta)
+			if !ok {
+				continue
+			}
+			s, ok := blockStateMapping[ComputeBlockHash(name, properties)]
+			if !ok {
+				continue
+			}
+			if _, ok := legacyStateMapping[s.rid]; !ok {
+				legacyStateMapping[s.rid] = legacyState{id: b.ID, meta: meta}
+			}
+		}
+	}
+
+	legacyBlockMapping = nil
+}
@@ -0,0 +1,151 @@
+package block
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MapTint is the kind of the biome color that a block is tinted with.
+type MapTint uint8
+
+const (
+	// MapTintNone means the color of the block is not changed by biomes.
+	MapTintNone MapTint = iota
+	// MapTintGrass means the block is tinted with the grass color, e.g. grass block and short grass.
+	MapTintGrass
+	// MapTintFoliage means the block is tinted with the foliage color, e.g. oak leaves and vines.
+	MapTintFoliage
+	// MapTintWater means the block is tinted with the water color.
+	MapTintWater
+)
+
+// MapColor is the color of a block when seen from above, which is used to render the maps.
+type MapColor struct {
+	// Color is the color of the block. For the tinted blocks, it should be multiplied
+	// by the biome color. The blocks that are not shown on the map have zero alpha.
+	Color color.RGBA
+	// Tint is the kind of the biome color that the block is tinted with.
+	Tint MapTint
+}
+
+// mapColorRule sets the map color of the blocks that match Blocks.
+type mapColorRule struct {
+	// Blocks holds the name patterns of the blocks that this rule matches,
+	// and each pattern follows the syntax of path.Match.
+	Blocks []string `json:"blocks"`
+	// Color is the color in #rrggbb or #rrggbbaa.
+	Color string `json:"color"`
+	// Tint is one of "grass", "foliage" and "water", and empty means not tinted.
+	Tint string `json:"tint"`
+}
+
+// mapColorTable is the JSON representation of block_map_color.json.
+type mapColorTable struct {
+	Default mapColorRule   `json:"default"`
+	Rules   []mapColorRule `json:"rules"`
+}
+
+var (
+	//go:embed block_map_color.json
+	blockMapColor []byte
+	// blockMapColors holds the map color of each block, which is indexed by runtime ID.
+	blockMapColors = map[uint32]MapColor{}
+	// mapColorOnce makes the map colors computed when they are first used,
+	// because only the renderers need them.
+	mapColorOnce sync.Once
+)
+
+// MapColorOf returns the map color of the block whose runtime ID is runtimeID, which comes from the map
+// colors of Java Edition. found is false if runtimeID is not exist, and the map color of air is returned.
+func MapColorOf(runtimeID uint32) (result MapColor, found bool) {
+	mapColorOnce.Do(finishMapColor)
+	result, found = blockMapColors[runtimeID]
+	if !found {
+		return blockMapColors[AirRuntimeID], false
+	}
+	return result, true
+}
+
+// matches reports whether the block whose name is name matches r.
+func (r mapColorRule) matches(name string) bool {
+	for _, pattern := range r.Blocks {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mapColor parses the color and the tint of r.
+func (r mapColorRule) mapColor() (result MapColor, err error) {
+	hex := strings.TrimPrefix(r.Color, "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return result, fmt.Errorf("invalid color %#v", r.Color)
+	}
+	result.Color = color.RGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}
+
+	switch r.Tint {
+	case "":
+	case "grass":
+		result.Tint = MapTintGrass
+	case "foliage":
+		result.Tint = MapTintFoliage
+	case "water":
+		result.Tint = MapTintWater
+	default:
+		return result, fmt.Errorf("invalid tint %#v", r.Tint)
+	}
+	return result, nil
+}
+
+// finishMapColor decodes block_map_color.json and computes the map colors of all registered blocks.
+// It must be called after all block states are registered, and it is called by mapColorOnce when
+// MapColorOf is first called.
+func finishMapColor() {
+	var table mapColorTable
+	if err := json.Unmarshal(blockMapColor, &table); err != nil {
+		panic(fmt.Sprintf("finishMapColor: Failed to decode block_map_color.json; err = %v", err))
+	}
+
+	defaultColor, err := table.Default.mapColor()
+	if err != nil {
+		panic(fmt.Sprintf("finishMapColor: %v", err))
+	}
+	colors := make([]MapColor, len(table.Rules))
+	for i, rule := range table.Rules {
+		if colors[i], err = rule.mapColor(); err != nil {
+			panic(fmt.Sprintf("finishMapColor: %v", err))
+		}
+	}
+
+	// The map color only depends on the name of the block.
+	colorByName := make(map[string]MapColor)
+	for hash, entry := range blockStateMapping {
+		name := decodeToNormalBlockState(entry.block).Name
+		c, ok := colorByName[name]
+		if !ok {
+			// All the matched rules are applied in order,
+			// so the latter one could override the former.
+			c = defaultColor
+			for i, rule := range table.Rules {
+				if rule.matches(name) {
+					c = colors[i]
+				}
+			}
+			colorByName[name] = c
+		}
+		blockMapColors[hash] = c
+	}
+
+	blockMapColor = nil
+}
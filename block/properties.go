@@ -0,0 +1,154 @@
+package block
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+
+	block_general "github.com/TriM-Organization/bedrock-world-operator/block/general"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// BlockProperty describes a single state key of a block, together with its
+// value type and every value that the key could hold.
+type BlockProperty struct {
+	// Name is the name of this state key, e.g. "facing_direction".
+	Name string
+	// Type is the type of the values of this state key, which is one of
+	// block_general.StateKeyTypeString, block_general.StateKeyTypeInt32
+	// and block_general.StateKeyTypeByte.
+	Type uint8
+	// Values holds all valid values of this state key in ascending order.
+	// The concrete type of each element is string, int32 or byte, which
+	// depends on Type.
+	Values []any
+}
+
+var (
+	// blockNames holds the names of all registered blocks in ascending order.
+	blockNames []string
+	// blockPropertySchema holds the state keys of each block, which
+	// is indexed by the name of the block.
+	blockPropertySchema = map[string][]BlockProperty{}
+	// pendingPropertySchema is used to collect the state values of each block
+	// while registering block states. It is released after init finished.
+	pendingPropertySchema = map[string]map[uint32]map[string]any{}
+)
+
+// BlockNames returns the names of all blocks that registered in the block
+// states table in ascending order. The returned slice could be modified freely.
+func BlockNames() []string {
+	return slices.Clone(blockNames)
+}
+
+// Properties returns all state keys of the block whose name is name, and
+// each state key holds its type and all valid values. The keys in the
+// returned slice are sorted by their names.
+//
+// name could omit the "minecraft:" prefix. found is false if the block
+// is not exist. Note that for a block who have no state, the returned
+// slice is empty but found is still true.
+func Properties(name string) (result []BlockProperty, found bool) {
+	if !strings.HasPrefix(name, "minecraft:") {
+		name = "minecraft:" + name
+	}
+
+	properties, found := blockPropertySchema[name]
+	if !found {
+		return nil, false
+	}
+
+	result = make([]BlockProperty, len(properties))
+	for index, value := range properties {
+		result[index] = BlockProperty{
+			Name:   value.Name,
+			Type:   value.Type,
+			Values: slices.Clone(value.Values),
+		}
+	}
+	return result, true
+}
+
+// recordBlockProperties records all state values of the block whose name is
+// name, so that its schema could be built by finishBlockPropertySchema.
+func recordBlockProperties(name string, p []block_general.IndexBlockProperty) {
+	keys, ok := pendingPropertySchema[name]
+	if !ok {
+		keys = make(map[uint32]map[string]any)
+		pendingPropertySchema[name] = keys
+	}
+
+	for _, value := range p {
+		values, ok := keys[value.KeyIndex]
+		if !ok {
+			values = make(map[string]any)
+			keys[value.KeyIndex] = values
+		}
+		// The encoded bytes is unique for each value of the
+		// same key, so it could be used as the map key here.
+		values[string(value.Value)] = decodePropertyValue(value)
+	}
+}
+
+// finishBlockPropertySchema builds blockNames and blockPropertySchema
+// by the state values collected by recordBlockProperties.
+func finishBlockPropertySchema() {
+	for name, keys := range pendingPropertySchema {
+		properties := make([]BlockProperty, 0, len(keys))
+
+		for keyIndex, values := range keys {
+			key := blockStatesSet[keyIndex]
+			property := BlockProperty{
+				Name:   stringSet[key.KeyNameIndex],
+				Type:   key.KeyType,
+				Values: make([]any, 0, len(values)),
+			}
+			for _, val := range values {
+				property.Values = append(property.Values, val)
+			}
+			slices.SortFunc(property.Values, comparePropertyValue)
+			properties = append(properties, property)
+		}
+
+		slices.SortFunc(properties, func(a BlockProperty, b BlockProperty) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		blockPropertySchema[name] = properties
+		blockNames = append(blockNames, name)
+	}
+
+	slices.Sort(blockNames)
+	pendingPropertySchema = nil
+}
+
+// decodePropertyValue decodes the value of p to string, int32 or byte.
+func decodePropertyValue(p block_general.IndexBlockProperty) any {
+	r := protocol.NewReader(bytes.NewBuffer(p.Value), 0, false)
+
+	switch blockStatesSet[p.KeyIndex].KeyType {
+	case block_general.StateKeyTypeString:
+		var ind uint32
+		r.Varuint32(&ind)
+		return stringSet[ind]
+	case block_general.StateKeyTypeInt32:
+		var val int32
+		r.Varint32(&val)
+		return val
+	default:
+		return p.Value[0]
+	}
+}
+
+// comparePropertyValue compares two values of the same state key.
+func comparePropertyValue(a any, b any) int {
+	switch v := a.(type) {
+	case string:
+		return cmp.Compare(v, b.(string))
+	case int32:
+		return cmp.Compare(v, b.(int32))
+	case byte:
+		return cmp.Compare(v, b.(byte))
+	}
+	return 0
+}
@@ -0,0 +1,324 @@
+package block
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// transformRule rewrites the state properties of the blocks that match
+// Blocks when these blocks are rotated or mirrored. Only one of Values,
+// Angles, Bits, Mirrored and Sides should be set.
+type transformRule struct {
+	// Blocks holds the name patterns of the blocks that this rule matches,
+	// and each pattern follows the syntax of path.Match.
+	Blocks []string `json:"blocks"`
+	// Property is the state key that this rule rewrites.
+	Property string `json:"property"`
+
+	// Values maps each value of Property to the directions it means, e.g.
+	// "north" and "south" for a rail which connects north and south. The
+	// tokens that are not horizontal directions (e.g. "up") are kept as is.
+	Values map[string][]string `json:"values"`
+	// Angles is the count of the values of Property that divide a circle evenly,
+	// where 0 is south and the values increase clockwise.
+	Angles int `json:"angles"`
+	// Bits maps the directions to the bits that Property holds.
+	Bits map[string]int `json:"bits"`
+	// Mirrored maps each value of Property to the one after mirroring, which
+	// is not changed by rotation, e.g. the hinge of a door.
+	Mirrored map[string]string `json:"mirrored"`
+	// Sides maps the directions to the state keys that hold the data of that
+	// side, and the data is moved to the side after transformed.
+	Sides map[string]string `json:"sides"`
+}
+
+// transformTable is the JSON representation of block_transform.json.
+type transformTable struct {
+	Rules []transformRule `json:"rules"`
+}
+
+// stateTransform is a rotation or mirroring that applied to block states.
+type stateTransform struct {
+	// direction returns the horizontal direction after transformed.
+	direction func(d string) string
+	// angle returns the angle after transformed, where the circle is divided into angles.
+	angle func(v int, angles int) int
+	// mirror is true if this transform is a mirroring.
+	mirror bool
+}
+
+var (
+	//go:embed block_transform.json
+	blockTransform []byte
+	// rotatedStates holds the runtime ID of each block after rotated 90 degrees clockwise,
+	// and mirroredStates holds the ones after mirrored, which is indexed by define.Mirror.
+	// Only the blocks that are changed by the transform are recorded.
+	rotatedStates  = map[uint32]uint32{}
+	mirroredStates = [...]map[uint32]uint32{define.MirrorX: {}, define.MirrorZ: {}}
+	// transformOnce makes the transformed states computed when they are first used,
+	// because most users never transform blocks, and it is expensive to compute them.
+	transformOnce sync.Once
+)
+
+// Rotate returns the runtime ID of the block whose runtime ID is runtimeID after the block is rotated by
+// rotation, which rewrites the state properties that hold a direction, e.g. facing_direction, pillar_axis
+// and weirdo_direction. runtimeID itself is returned if the block is not changed by rotation.
+func Rotate(runtimeID uint32, rotation define.Rotation) uint32 {
+	transformOnce.Do(finishTransform)
+	for range rotation % 4 {
+		if rotated, ok := rotatedStates[runtimeID]; ok {
+			runtimeID = rotated
+		}
+	}
+	return runtimeID
+}
+
+// Mirror returns the runtime ID of the block whose runtime ID is runtimeID after the block is mirrored
+// by mirror. See Rotate for more information.
+func Mirror(runtimeID uint32, mirror define.Mirror) uint32 {
+	transformOnce.Do(finishTransform)
+	if int(mirror) >= len(mirroredStates) || mirroredStates[mirror] == nil {
+		return runtimeID
+	}
+	if mirrored, ok := mirroredStates[mirror][runtimeID]; ok {
+		return mirrored
+	}
+	return runtimeID
+}
+
+// rotateDirection rotates the horizontal direction d 90 degrees clockwise.
+func rotateDirection(d string) string {
+	switch d {
+	case "north":
+		return "east"
+	case "east":
+		return "south"
+	case "south":
+		return "west"
+	case "west":
+		return "north"
+	}
+	return d
+}
+
+// mirrorDirection returns a function that mirrors the horizontal directions by mirror.
+func mirrorDirection(mirror define.Mirror) func(d string) string {
+	swap := [2]string{"east", "west"}
+	if mirror == define.MirrorZ {
+		swap = [2]string{"north", "south"}
+	}
+	return func(d string) string {
+		switch d {
+		case swap[0]:
+			return swap[1]
+		case swap[1]:
+			return swap[0]
+		}
+		return d
+	}
+}
+
+// typedValue converts value to the same type as the state value origin.
+func typedValue(origin any, value string) (result any, ok bool) {
+	switch origin.(type) {
+	case string:
+		return value, true
+	case int32:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return int32(v), err == nil
+	case byte:
+		v, err := strconv.ParseUint(value, 10, 8)
+		return byte(v), err == nil
+	}
+	return nil, false
+}
+
+// matches reports whether the block whose name is name matches r.
+func (r transformRule) matches(name string) bool {
+	for _, pattern := range r.Blocks {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// apply rewrites states by t and writes the new states to result. The state keys that
+// handled are recorded in handled, and they are not handled again by the latter rules.
+func (r transformRule) apply(t stateTransform, states map[string]any, result map[string]any, handled map[string]bool) {
+	if r.Sides != nil {
+		for d, key := range r.Sides {
+			if value, ok := states[key]; ok && !handled[key] {
+				result[r.Sides[t.direction(d)]] = value
+			}
+		}
+		for _, key := range r.Sides {
+			handled[key] = true
+		}
+		return
+	}
+
+	origin, ok := states[r.Property]
+	if !ok || handled[r.Property] {
+		return
+	}
+	handled[r.Property] = true
+	current := fmt.Sprint(origin)
+
+	var newValue string
+	switch {
+	case r.Values != nil:
+		tokens, ok := r.Values[current]
+		if !ok {
+			return
+		}
+		newTokens := make([]string, len(tokens))
+		for i, token := range tokens {
+			newTokens[i] = t.direction(token)
+		}
+		newValue, ok = matchTokens(r.Values, newTokens)
+		if !ok {
+			return
+		}
+	case r.Angles > 0:
+		v, err := strconv.Atoi(current)
+		if err != nil {
+			return
+		}
+		newValue = strconv.Itoa(t.angle(v, r.Angles))
+	case r.Bits != nil:
+		v, err := strconv.Atoi(current)
+		if err != nil {
+			return
+		}
+		newBits := v
+		for _, bit := range r.Bits {
+			newBits &^= bit
+		}
+		for d, bit := range r.Bits {
+			if v&bit != 0 {
+				newBits |= r.Bits[t.direction(d)]
+			}
+		}
+		newValue = strconv.Itoa(newBits)
+	case r.Mirrored != nil:
+		if !t.mirror {
+			return
+		}
+		if newValue, ok = r.Mirrored[current]; !ok {
+			return
+		}
+	default:
+		return
+	}
+
+	if value, ok := typedValue(origin, newValue); ok {
+		result[r.Property] = value
+	}
+}
+
+// matchTokens finds the value in values whose directions are tokens. The directions in the same order
+// are preferred, and then the ones in any order (e.g. "north_south" and "south_north" of a rail).
+func matchTokens(values map[string][]string, tokens []string) (value string, ok bool) {
+	for value, valueTokens := range values {
+		if slices.Equal(valueTokens, tokens) {
+			return value, true
+		}
+	}
+
+	sorted := slices.Sorted(slices.Values(tokens))
+	for value, valueTokens := range values {
+		if slices.Equal(slices.Sorted(slices.Values(valueTokens)), sorted) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// finishTransform computes the rotated and mirrored states of all registered blocks.
+// It must be called after all block states are registered, and it is called by
+// transformOnce when Rotate or Mirror is first called.
+func finishTransform() {
+	var table transformTable
+	if err := json.Unmarshal(blockTransform, &table); err != nil {
+		panic(fmt.Sprintf("finishTransform: Failed to decode block_transform.json; err = %v", err))
+	}
+
+	transforms := []struct {
+		t      stateTransform
+		result map[uint32]uint32
+	}{
+		{
+			t: stateTransform{
+				direction: rotateDirection,
+				angle:     func(v int, angles int) int { return (v + angles/4) % angles },
+			},
+			result: rotatedStates,
+		},
+		{
+			t: stateTransform{
+				direction: mirrorDirection(define.MirrorX),
+				angle:     func(v int, angles int) int { return (angles - v) % angles },
+				mirror:    true,
+			},
+			result: mirroredStates[define.MirrorX],
+		},
+		{
+			t: stateTransform{
+				direction: mirrorDirection(define.MirrorZ),
+				angle:     func(v int, angles int) int { return (angles*3/2 - v) % angles },
+				mirror:    true,
+			},
+			result: mirroredStates[define.MirrorZ],
+		},
+	}
+
+	// The rules that each block matches, which is indexed by the name of the block.
+	matchedRules := make(map[string][]transformRule)
+	for hash, entry := range blockStateMapping {
+		realBlock := decodeToNormalBlockState(entry.block)
+
+		rules, ok := matchedRules[realBlock.Name]
+		if !ok {
+			for _, rule := range table.Rules {
+				if rule.matches(realBlock.Name) {
+					rules = append(rules, rule)
+				}
+			}
+			matchedRules[realBlock.Name] = rules
+		}
+		if len(rules) == 0 {
+			continue
+		}
+
+		for _, transform := range transforms {
+			newStates := make(map[string]any, len(realBlock.Properties))
+			for key, value := range realBlock.Properties {
+				newStates[key] = value
+			}
+
+			handled := make(map[string]bool)
+			for _, rule := range rules {
+				rule.apply(transform.t, realBlock.Properties, newStates, handled)
+			}
+
+			if maps.Equal(newStates, realBlock.Properties) {
+				continue
+			}
+			newHash := ComputeBlockHash(realBlock.Name, newStates)
+			if newEntry, ok := blockStateMapping[newHash]; ok && newHash != hash {
+				transform.result[entry.rid] = newEntry.rid
+			}
+		}
+	}
+
+	blockTransform = nil
+}
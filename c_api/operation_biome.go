@@ -0,0 +1,90 @@
+package main
+
+import "C"
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/TriM-Organization/bedrock-world-operator/biome"
+)
+
+//export BiomeByID
+func BiomeByID(id C.int) (complexReturn *C.char) {
+	b, found := biome.ByID(uint32(id))
+	if !found {
+		// not found
+		return asCbytes([]byte{0})
+	}
+	// found
+	return asCbytes(append([]byte{1}, packBiome(b)...))
+}
+
+//export BiomeByName
+func BiomeByName(name *C.char) (complexReturn *C.char) {
+	b, found := biome.ByName(C.GoString(name))
+	if !found {
+		// not found
+		return asCbytes([]byte{0})
+	}
+	// found
+	return asCbytes(append([]byte{1}, packBiome(b)...))
+}
+
+//export AllBiomes
+func AllBiomes() (complexReturn *C.char) {
+	result := make([]byte, 0)
+	for _, b := range biome.Biomes() {
+		result = append(result, packBiome(b)...)
+	}
+	return asCbytes(result)
+}
+
+//export RegisterBiome
+func RegisterBiome(payload *C.char) (err *C.char) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = C.CString(fmt.Sprintf("RegisterBiome: %v", r))
+		}
+	}()
+
+	b := unpackBiome(asGoBytes(payload))
+	if err := biome.Register(b); err != nil {
+		return C.CString(err.Error())
+	}
+	return C.CString("")
+}
+
+// packBiome packs b as its ID (uint32), its name (with an uint16 length), its temperature
+// and downfall (float64), and its grass, foliage and water colors (3 bytes of RGB for each).
+func packBiome(b biome.Biome) []byte {
+	result := binary.LittleEndian.AppendUint32(nil, b.ID)
+	result = binary.LittleEndian.AppendUint16(result, uint16(len(b.Name)))
+	result = append(result, b.Name...)
+	result = binary.LittleEndian.AppendUint64(result, math.Float64bits(b.Temperature))
+	result = binary.LittleEndian.AppendUint64(result, math.Float64bits(b.Downfall))
+	for _, c := range []color.RGBA{b.GrassColor, b.FoliageColor, b.WaterColor} {
+		result = append(result, c.R, c.G, c.B)
+	}
+	return result
+}
+
+// unpackBiome unpacks a biome packed by packBiome.
+func unpackBiome(payload []byte) (b biome.Biome) {
+	b.ID = binary.LittleEndian.Uint32(payload)
+	nameLength := int(binary.LittleEndian.Uint16(payload[4:]))
+	b.Name = string(payload[6 : 6+nameLength])
+	payload = payload[6+nameLength:]
+
+	b.Temperature = math.Float64frombits(binary.LittleEndian.Uint64(payload))
+	b.Downfall = math.Float64frombits(binary.LittleEndian.Uint64(payload[8:]))
+	payload = payload[16:]
+
+	colors := []*color.RGBA{&b.GrassColor, &b.FoliageColor, &b.WaterColor}
+	for i, c := range colors {
+		*c = color.RGBA{R: payload[i*3], G: payload[i*3+1], B: payload[i*3+2], A: 0xff}
+	}
+	return b
+}
@@ -5,9 +5,11 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 
 	"github.com/TriM-Organization/bedrock-world-operator/block"
 	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
 )
 
 var savedChunk = NewSimpleManager[*chunk.Chunk]()
@@ -23,6 +25,15 @@ func ReleaseChunk(id C.longlong) {
 	savedChunk.ReleaseObject(int(id))
 }
 
+//export ChunkFromJSON
+func ChunkFromJSON(payload *C.char) (complexReturn *C.char) {
+	c := new(chunk.Chunk)
+	if err := c.UnmarshalJSON(asGoBytes(payload)); err != nil {
+		return packChunkRangeAndID(define.Range{0, -1}, -1)
+	}
+	return packChunkRangeAndID(c.Range(), savedChunk.AddObject(c))
+}
+
 //export Chunk_Biome
 func Chunk_Biome(id C.longlong, x C.int, y C.int, z C.int) C.int {
 	c := savedChunk.LoadObject(int(id))
@@ -32,6 +43,15 @@ func Chunk_Biome(id C.longlong, x C.int, y C.int, z C.int) C.int {
 	return C.int((*c).Biome(uint8(x), int16(y), uint8(z)))
 }
 
+//export Chunk_BiomeCounts
+func Chunk_BiomeCounts(id C.longlong) *C.char {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return asCbytes(nil)
+	}
+	return asCbytes(packBlockCounts((*c).BiomeCounts()))
+}
+
 //export Chunk_Biomes
 func Chunk_Biomes(id C.longlong) *C.char {
 	c := savedChunk.LoadObject(int(id))
@@ -51,6 +71,15 @@ func Chunk_Block(id C.longlong, x C.int, y C.int, z C.int, layer C.int) (blockRu
 	return C.int((*c).Block(uint8(x), int16(y), uint8(z), uint8(layer)))
 }
 
+//export Chunk_BlockCounts
+func Chunk_BlockCounts(id C.longlong, layer C.int) *C.char {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return asCbytes(nil)
+	}
+	return asCbytes(packBlockCounts((*c).BlockCounts(uint8(layer))))
+}
+
 //export Chunk_Blocks
 func Chunk_Blocks(id C.longlong, layer C.int) (complexReturn *C.char) {
 	c := savedChunk.LoadObject(int(id))
@@ -60,6 +89,15 @@ func Chunk_Blocks(id C.longlong, layer C.int) (complexReturn *C.char) {
 	return asCbytes(packDenseBlockMatrix((*c).Blocks(uint8(layer)), 4096))
 }
 
+//export Chunk_Clone
+func Chunk_Clone(id C.longlong) C.longlong {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return C.longlong(savedChunk.AddObject((*c).Clone()))
+}
+
 //export Chunk_Compact
 func Chunk_Compact(id C.longlong) *C.char {
 	c := savedChunk.LoadObject(int(id))
@@ -80,6 +118,62 @@ func Chunk_Equals(id C.longlong, anotherChunkID C.longlong) C.int {
 	return asCbool((*c1).Equals(*c2))
 }
 
+//export Chunk_ComputeLight
+func Chunk_ComputeLight(id C.longlong, posx C.int, posz C.int, neighbours *C.char) *C.char {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return C.CString("Chunk_ComputeLight: Chunk not found")
+	}
+
+	m, err := unpackChunkNeighbours(asGoBytes(neighbours))
+	if err != nil {
+		return C.CString(fmt.Sprintf("Chunk_ComputeLight: %v", err))
+	}
+	chunk.ComputeLight(define.ChunkPos{int32(posx), int32(posz)}, *c, m)
+
+	return C.CString("")
+}
+
+//export Chunk_SkyLight
+func Chunk_SkyLight(id C.longlong, x C.int, y C.int, z C.int) C.int {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return C.int((*c).SkyLight(uint8(x), int16(y), uint8(z)))
+}
+
+//export Chunk_BlockLight
+func Chunk_BlockLight(id C.longlong, x C.int, y C.int, z C.int) C.int {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return C.int((*c).BlockLight(uint8(x), int16(y), uint8(z)))
+}
+
+//export Chunk_HighestBlock
+func Chunk_HighestBlock(id C.longlong, x C.int, z C.int) C.int {
+	// -1 is a valid height, so the chunk not found is
+	// told by math.MinInt16, which is out of any range.
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return math.MinInt16
+	}
+	return C.int((*c).HighestBlock(uint8(x), uint8(z)))
+}
+
+//export Chunk_HighestSolidBlock
+func Chunk_HighestSolidBlock(id C.longlong, x C.int, z C.int) C.int {
+	// -1 is a valid height, so the chunk not found is
+	// told by math.MinInt16, which is out of any range.
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return math.MinInt16
+	}
+	return C.int((*c).HighestSolidBlock(uint8(x), uint8(z)))
+}
+
 //export Chunk_HighestFilledSubChunk
 func Chunk_HighestFilledSubChunk(id C.longlong) C.int {
 	c := savedChunk.LoadObject(int(id))
@@ -89,6 +183,58 @@ func Chunk_HighestFilledSubChunk(id C.longlong) C.int {
 	return C.int((*c).HighestFilledSubChunk())
 }
 
+//export Chunk_HighestLightBlocker
+func Chunk_HighestLightBlocker(id C.longlong, x C.int, z C.int) C.int {
+	// -1 is a valid height, so the chunk not found is
+	// told by math.MinInt16, which is out of any range.
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return math.MinInt16
+	}
+	return C.int((*c).HighestLightBlocker(uint8(x), uint8(z)))
+}
+
+//export Chunk_IsWaterlogged
+func Chunk_IsWaterlogged(id C.longlong, x C.int, y C.int, z C.int) C.int {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return asCbool((*c).IsWaterlogged(uint8(x), int16(y), uint8(z)))
+}
+
+//export Chunk_MarshalJSON
+func Chunk_MarshalJSON(id C.longlong) *C.char {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return asCbytes(nil)
+	}
+	data, err := (*c).MarshalJSON()
+	if err != nil {
+		return asCbytes(nil)
+	}
+	return asCbytes(data)
+}
+
+//export Chunk_PlaceBlock
+func Chunk_PlaceBlock(id C.longlong, x C.int, y C.int, z C.int, block C.int) *C.char {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return C.CString("Chunk_PlaceBlock: Chunk not found")
+	}
+	(*c).PlaceBlock(uint8(x), int16(y), uint8(z), uint32(block))
+	return C.CString("")
+}
+
+//export Chunk_ReplaceBlocks
+func Chunk_ReplaceBlocks(id C.longlong, layer C.int, mapping *C.char) C.int {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return asCbool((*c).ReplaceBlocks(uint8(layer), unpackBlockMapping(asGoBytes(mapping))))
+}
+
 //export Chunk_SetBiome
 func Chunk_SetBiome(id C.longlong, x C.int, y C.int, z C.int, biomeId C.int) *C.char {
 	c := savedChunk.LoadObject(int(id))
@@ -129,6 +275,15 @@ func Chunk_SetBlocks(id C.longlong, layer C.int, payload *C.char) *C.char {
 	return C.CString("")
 }
 
+//export Chunk_SetWaterlogged
+func Chunk_SetWaterlogged(id C.longlong, x C.int, y C.int, z C.int, waterlogged C.int) C.int {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return -1
+	}
+	return asCbool((*c).SetWaterlogged(uint8(x), int16(y), uint8(z), asGoBool(waterlogged)))
+}
+
 //export Chunk_Sub
 func Chunk_Sub(id C.longlong) (complexReturn *C.char) {
 	c := savedChunk.LoadObject(int(id))
@@ -81,6 +81,56 @@ func StateToRuntimeID(name *C.char, states *C.char) (complexReturn *C.char) {
 	return asCbytes(result)
 }
 
+//export BlockClassification
+func BlockClassification(runtimeID C.int) (complexReturn *C.char) {
+	c, found := block.Classify(uint32(runtimeID))
+	if !found {
+		// not found
+		return asCbytes([]byte{0})
+	}
+
+	// found
+	result := []byte{1}
+
+	// categories
+	result = append(result, byte(asCbool(c.Solid)), byte(asCbool(c.Liquid)), byte(asCbool(c.Transparent)))
+	result = append(result, c.LightEmission, c.LightFiltering, byte(asCbool(c.Waterloggable)))
+
+	return asCbytes(result)
+}
+
+//export ChunkNetworkPayload
+func ChunkNetworkPayload(id C.longlong, blockEntities *C.char) (complexReturn *C.char) {
+	c := savedChunk.LoadObject(int(id))
+	if c == nil {
+		return asCbytes(nil)
+	}
+
+	goPayload := asGoBytes(blockEntities)
+	nbts := make([]map[string]any, 0)
+	for len(goPayload) > 0 {
+		var decodeAns map[string]any
+
+		l := binary.LittleEndian.Uint32(goPayload)
+		_ = nbt.NewDecoderWithEncoding(bytes.NewBuffer(goPayload[4:4+l]), nbt.LittleEndian).Decode(&decodeAns)
+		if len(decodeAns) > 0 {
+			nbts = append(nbts, decodeAns)
+		}
+
+		goPayload = goPayload[4+l:]
+	}
+
+	payload, subChunkCount := chunk.NetworkEncode(*c, nbts)
+
+	// sub chunk count
+	result := make([]byte, 4)
+	binary.LittleEndian.PutUint32(result, subChunkCount)
+	// payload
+	result = append(result, payload...)
+
+	return asCbytes(result)
+}
+
 //export SubChunkNetworkPayload
 func SubChunkNetworkPayload(subChunkId C.longlong, rangeStart C.int, rangeEnd C.int, ind C.int) *C.char {
 	return subChunkPayload(subChunkId, rangeStart, rangeEnd, ind, chunk.NetworkEncoding)
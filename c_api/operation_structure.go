@@ -0,0 +1,154 @@
+package main
+
+import "C"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/structure"
+)
+
+var savedStructure = NewSimpleManager[*structure.Structure]()
+
+//export NewStructure
+func NewStructure(x C.int, y C.int, z C.int) C.longlong {
+	size := [3]int32{int32(x), int32(y), int32(z)}
+	if _, ok := structure.Volume(size); !ok {
+		return -1
+	}
+	s := structure.NewStructure(size)
+	return C.longlong(savedStructure.AddObject(s))
+}
+
+//export ReleaseStructure
+func ReleaseStructure(id C.longlong) {
+	savedStructure.ReleaseObject(int(id))
+}
+
+//export FromMCStructure
+func FromMCStructure(payload *C.char) C.longlong {
+	s, err := structure.ReadMCStructure(bytes.NewBuffer(asGoBytes(payload)))
+	if err != nil {
+		return -1
+	}
+	return C.longlong(savedStructure.AddObject(s))
+}
+
+//export FromSchem
+func FromSchem(payload *C.char) C.longlong {
+	s, err := structure.ReadSchem(bytes.NewBuffer(asGoBytes(payload)))
+	if err != nil {
+		return -1
+	}
+	return C.longlong(savedStructure.AddObject(s))
+}
+
+//export FromSchematic
+func FromSchematic(payload *C.char) C.longlong {
+	s, err := structure.ReadSchematic(bytes.NewBuffer(asGoBytes(payload)))
+	if err != nil {
+		return -1
+	}
+	return C.longlong(savedStructure.AddObject(s))
+}
+
+//export Structure_Block
+func Structure_Block(id C.longlong, x C.int, y C.int, z C.int, layer C.int) (blockRuntimeID C.longlong) {
+	// The block runtime ID is returned as C.longlong, so that
+	// structure.VoidRuntimeID could be told from not found (-1).
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return -1
+	}
+	return C.longlong((*s).Block(int32(x), int32(y), int32(z), uint8(layer)))
+}
+
+//export Structure_MCStructure
+func Structure_MCStructure(id C.longlong) *C.char {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return asCbytes(nil)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := (*s).WriteMCStructure(buf); err != nil {
+		return asCbytes(nil)
+	}
+
+	return asCbytes(buf.Bytes())
+}
+
+//export Structure_Mirror
+func Structure_Mirror(id C.longlong, mirror C.int) C.longlong {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return -1
+	}
+	return C.longlong(savedStructure.AddObject((*s).Mirror(define.Mirror(mirror))))
+}
+
+//export Structure_Origin
+func Structure_Origin(id C.longlong) *C.char {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return asCbytes(nil)
+	}
+
+	origin := (*s).Origin()
+	result := make([]byte, 0, 12)
+	for _, value := range origin {
+		result = binary.LittleEndian.AppendUint32(result, uint32(value))
+	}
+
+	return asCbytes(result)
+}
+
+//export Structure_Rotate
+func Structure_Rotate(id C.longlong, rotation C.int) C.longlong {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return -1
+	}
+	return C.longlong(savedStructure.AddObject((*s).Rotate(define.Rotation(rotation))))
+}
+
+//export Structure_SetBlock
+func Structure_SetBlock(id C.longlong, x C.int, y C.int, z C.int, layer C.int, block C.longlong) *C.char {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return C.CString("Structure_SetBlock: Structure not found")
+	}
+	if !(*s).Contains(int32(x), int32(y), int32(z)) {
+		return C.CString(fmt.Sprintf("Structure_SetBlock: Position (%d, %d, %d) is out of the structure", x, y, z))
+	}
+	(*s).SetBlock(int32(x), int32(y), int32(z), uint8(layer), uint32(block))
+	return C.CString("")
+}
+
+//export Structure_SetOrigin
+func Structure_SetOrigin(id C.longlong, x C.int, y C.int, z C.int) *C.char {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return C.CString("Structure_SetOrigin: Structure not found")
+	}
+	(*s).SetOrigin(define.BlockPos{int32(x), int32(y), int32(z)})
+	return C.CString("")
+}
+
+//export Structure_Size
+func Structure_Size(id C.longlong) *C.char {
+	s := savedStructure.LoadObject(int(id))
+	if s == nil {
+		return asCbytes(nil)
+	}
+
+	size := (*s).Size()
+	result := make([]byte, 0, 12)
+	for _, value := range size {
+		result = binary.LittleEndian.AppendUint32(result, uint32(value))
+	}
+
+	return asCbytes(result)
+}
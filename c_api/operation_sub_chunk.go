@@ -30,6 +30,15 @@ func SubChunk_Block(id C.longlong, x C.int, y C.int, z C.int, layer C.int) (bloc
 	return C.int((*subChunk).Block(byte(x), byte(y), byte(z), uint8(layer)))
 }
 
+//export SubChunk_BlockCounts
+func SubChunk_BlockCounts(id C.longlong, layer C.int) *C.char {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return asCbytes(nil)
+	}
+	return asCbytes(packBlockCounts((*subChunk).BlockCounts(uint8(layer))))
+}
+
 //export SubChunk_Blocks
 func SubChunk_Blocks(id C.longlong, layer C.int) (complexReturn *C.char) {
 	c := savedSubChunk.LoadObject(int(id))
@@ -51,6 +60,15 @@ func SubChunk_Blocks(id C.longlong, layer C.int) (complexReturn *C.char) {
 	return asCbytes(result)
 }
 
+//export SubChunk_Clone
+func SubChunk_Clone(id C.longlong) C.longlong {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return -1
+	}
+	return C.longlong(savedSubChunk.AddObject((*subChunk).Clone()))
+}
+
 //export SubChunk_Empty
 func SubChunk_Empty(id C.longlong) C.int {
 	subChunk := savedSubChunk.LoadObject(int(id))
@@ -70,6 +88,34 @@ func SubChunk_Equals(id C.longlong, anotherSubChunkId C.longlong) C.int {
 	return asCbool((*s1).Equals(*s2))
 }
 
+//export SubChunk_IsWaterlogged
+func SubChunk_IsWaterlogged(id C.longlong, x C.int, y C.int, z C.int) C.int {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return -1
+	}
+	return asCbool((*subChunk).IsWaterlogged(byte(x), byte(y), byte(z)))
+}
+
+//export SubChunk_PlaceBlock
+func SubChunk_PlaceBlock(id C.longlong, x C.int, y C.int, z C.int, block C.int) *C.char {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return C.CString("SubChunk_PlaceBlock: Sub chunk not found")
+	}
+	(*subChunk).PlaceBlock(byte(x), byte(y), byte(z), uint32(block))
+	return C.CString("")
+}
+
+//export SubChunk_ReplaceBlocks
+func SubChunk_ReplaceBlocks(id C.longlong, layer C.int, mapping *C.char) C.int {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return -1
+	}
+	return asCbool((*subChunk).ReplaceBlocks(uint8(layer), unpackBlockMapping(asGoBytes(mapping))))
+}
+
 //export SubChunk_SetBlock
 func SubChunk_SetBlock(id C.longlong, x C.int, y C.int, z C.int, layer C.int, block C.int) *C.char {
 	subChunk := savedSubChunk.LoadObject(int(id))
@@ -99,3 +145,12 @@ func SubChunk_SetBlocks(id C.longlong, layer C.int, payload *C.char) *C.char {
 	(*c).SetBlocks(uint8(layer), blocks)
 	return C.CString("")
 }
+
+//export SubChunk_SetWaterlogged
+func SubChunk_SetWaterlogged(id C.longlong, x C.int, y C.int, z C.int, waterlogged C.int) C.int {
+	subChunk := savedSubChunk.LoadObject(int(id))
+	if subChunk == nil {
+		return -1
+	}
+	return asCbool((*subChunk).SetWaterlogged(byte(x), byte(y), byte(z), asGoBool(waterlogged)))
+}
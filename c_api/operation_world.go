@@ -6,7 +6,11 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/TriM-Organization/bedrock-world-operator/anvil"
 	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/generator"
+	"github.com/TriM-Organization/bedrock-world-operator/mesh"
+	"github.com/TriM-Organization/bedrock-world-operator/render"
 	"github.com/TriM-Organization/bedrock-world-operator/world"
 	"github.com/TriM-Organization/bedrock-world-operator/world/leveldat"
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
@@ -359,6 +363,21 @@ func SaveDeltaUpdate(id C.longlong, dm C.int, posx C.int, posz C.int, payload *C
 	return C.CString("")
 }
 
+//export ApplyDeltaUpdates
+func ApplyDeltaUpdates(id C.longlong, dm C.int, posx C.int, posz C.int) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return C.CString("ApplyDeltaUpdates: World not found")
+	}
+
+	err := (*w).ApplyDeltaUpdates(define.Dimension(dm), define.ChunkPos{int32(posx), int32(posz)})
+	if err != nil {
+		return C.CString(fmt.Sprintf("ApplyDeltaUpdates: %v", err))
+	}
+
+	return C.CString("")
+}
+
 //export LoadTimeStamp
 func LoadTimeStamp(id C.longlong, dm C.int, posx C.int, posz C.int) C.longlong {
 	w := openedWorld.LoadObject(int(id))
@@ -493,3 +512,219 @@ func SaveSubChunkBlobHash(id C.longlong, dm C.int, posx C.int, posy C.int, posz
 
 	return C.CString("")
 }
+
+//export ChunkPositions
+func ChunkPositions(id C.longlong, dm C.int) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return asCbytes(nil)
+	}
+
+	positions, err := (*w).ChunkPositions(define.Dimension(dm))
+	if err != nil {
+		return asCbytes(nil)
+	}
+
+	result := make([]byte, 0, len(positions)*8)
+	for _, pos := range positions {
+		result = binary.LittleEndian.AppendUint32(result, uint32(pos[0]))
+		result = binary.LittleEndian.AppendUint32(result, uint32(pos[1]))
+	}
+
+	return asCbytes(result)
+}
+
+//export ReplaceBlocks
+func ReplaceBlocks(id C.longlong, dm C.int, layer C.int, mapping *C.char) C.longlong {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return -1
+	}
+
+	modified, err := (*w).ReplaceBlocks(define.Dimension(dm), uint8(layer), unpackBlockMapping(asGoBytes(mapping)))
+	if err != nil {
+		return -1
+	}
+
+	return C.longlong(modified)
+}
+
+//export BlockStatistics
+func BlockStatistics(id C.longlong, dm C.int, layer C.int, positions *C.char) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return asCbytes(nil)
+	}
+
+	// An empty positions payload means the whole dimension.
+	var chunkPositions []define.ChunkPos
+	if goBytes := asGoBytes(positions); len(goBytes) > 0 {
+		chunkPositions = make([]define.ChunkPos, 0, len(goBytes)/8)
+		for ptr := 0; ptr+8 <= len(goBytes); ptr += 8 {
+			chunkPositions = append(chunkPositions, define.ChunkPos{
+				int32(binary.LittleEndian.Uint32(goBytes[ptr : ptr+4])),
+				int32(binary.LittleEndian.Uint32(goBytes[ptr+4 : ptr+8])),
+			})
+		}
+	}
+
+	statistics, err := (*w).BlockStatistics(define.Dimension(dm), uint8(layer), chunkPositions)
+	if err != nil {
+		return asCbytes(nil)
+	}
+
+	result := binary.LittleEndian.AppendUint32(nil, uint32(statistics.Chunks))
+	for _, counts := range []map[uint32]uint64{statistics.Blocks, statistics.Biomes} {
+		result = binary.LittleEndian.AppendUint32(result, uint32(len(counts)))
+		for key, count := range counts {
+			result = binary.LittleEndian.AppendUint32(result, key)
+			result = binary.LittleEndian.AppendUint64(result, count)
+		}
+	}
+
+	return asCbytes(result)
+}
+
+//export CaptureStructure
+func CaptureStructure(id C.longlong, dm C.int, startX C.int, startY C.int, startZ C.int, endX C.int, endY C.int, endZ C.int) C.longlong {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return -1
+	}
+
+	s, err := (*w).CaptureStructure(
+		define.Dimension(dm),
+		define.BlockPos{int32(startX), int32(startY), int32(startZ)},
+		define.BlockPos{int32(endX), int32(endY), int32(endZ)},
+	)
+	if err != nil {
+		return -1
+	}
+
+	return C.longlong(savedStructure.AddObject(s))
+}
+
+//export PasteStructure
+func PasteStructure(id C.longlong, dm C.int, x C.int, y C.int, z C.int, structureID C.longlong) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return C.CString("PasteStructure: World not found")
+	}
+
+	s := savedStructure.LoadObject(int(structureID))
+	if s == nil {
+		return C.CString("PasteStructure: Structure not found")
+	}
+
+	err := (*w).PasteStructure(define.Dimension(dm), define.BlockPos{int32(x), int32(y), int32(z)}, *s)
+	if err != nil {
+		return C.CString(fmt.Sprintf("PasteStructure: %v", err))
+	}
+
+	return C.CString("")
+}
+
+//export ConvertJavaWorld
+func ConvertJavaWorld(id C.longlong, javaDir *C.char) (complexReturn *C.char) {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return asCbytes(nil)
+	}
+
+	chunks, skipped, err := anvil.ConvertWorld(C.GoString(javaDir), *w)
+	if err != nil {
+		return asCbytes(nil)
+	}
+
+	result := binary.LittleEndian.AppendUint64(nil, uint64(chunks))
+	result = binary.LittleEndian.AppendUint64(result, uint64(skipped))
+	return asCbytes(result)
+}
+
+//export RenderMapTiles
+func RenderMapTiles(id C.longlong, dm C.int, dir *C.char, levels C.int) C.longlong {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return -1
+	}
+
+	tiles, err := render.NewRenderer(*w, define.Dimension(dm)).WriteTiles(C.GoString(dir), int(levels))
+	if err != nil {
+		return -1
+	}
+
+	return C.longlong(tiles)
+}
+
+//export ExportMesh
+func ExportMesh(id C.longlong, dm C.int, startX C.int, startY C.int, startZ C.int, endX C.int, endY C.int, endZ C.int, fileName *C.char) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return C.CString("ExportMesh: World not found")
+	}
+
+	m, err := mesh.Build(
+		*w,
+		define.Dimension(dm),
+		define.BlockPos{int32(startX), int32(startY), int32(startZ)},
+		define.BlockPos{int32(endX), int32(endY), int32(endZ)},
+	)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ExportMesh: %v", err))
+	}
+	if err = m.WriteFile(C.GoString(fileName)); err != nil {
+		return C.CString(fmt.Sprintf("ExportMesh: %v", err))
+	}
+
+	return C.CString("")
+}
+
+//export ExportChunkJSON
+func ExportChunkJSON(id C.longlong, dm C.int, posx C.int, posz C.int) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return asCbytes(nil)
+	}
+
+	data, _, err := (*w).ExportChunkJSON(define.Dimension(dm), define.ChunkPos{int32(posx), int32(posz)})
+	if err != nil {
+		return asCbytes(nil)
+	}
+
+	return asCbytes(data)
+}
+
+//export ImportChunkJSON
+func ImportChunkJSON(id C.longlong, payload *C.char) *C.char {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return C.CString("ImportChunkJSON: World not found")
+	}
+
+	_, _, err := (*w).ImportChunkJSON(asGoBytes(payload))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ImportChunkJSON: %v", err))
+	}
+
+	return C.CString("")
+}
+
+//export PregenerateChunks
+func PregenerateChunks(id C.longlong, dm C.int, posx C.int, posz C.int, radius C.int) C.longlong {
+	w := openedWorld.LoadObject(int(id))
+	if w == nil {
+		return -1
+	}
+
+	g, err := generator.FromLevelDat((*w).LevelDat())
+	if err != nil {
+		return -1
+	}
+
+	generated, err := generator.Pregenerate(*w, g, define.Dimension(dm), define.ChunkPos{int32(posx), int32(posz)}, int32(radius))
+	if err != nil {
+		return -1
+	}
+
+	return C.longlong(generated)
+}
@@ -4,6 +4,7 @@ import "C"
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"unsafe"
 
 	"github.com/TriM-Organization/bedrock-world-operator/chunk"
@@ -82,6 +83,42 @@ func unpackDenseBlockMatrix(encodeBytes []byte, subLength int) (blockMatrix [][]
 	return
 }
 
+func unpackBlockMapping(encodeBytes []byte) (mapping map[uint32]uint32) {
+	mapping = make(map[uint32]uint32, len(encodeBytes)/8)
+	for ptr := 0; ptr+8 <= len(encodeBytes); ptr += 8 {
+		mapping[binary.LittleEndian.Uint32(encodeBytes[ptr:ptr+4])] = binary.LittleEndian.Uint32(encodeBytes[ptr+4 : ptr+8])
+	}
+	return
+}
+
+// unpackChunkNeighbours unpacks the neighbours passed to Chunk_ComputeLight, which are the X and Z
+// (int32) of the chunk positions followed by the IDs (int64) of the chunks.
+func unpackChunkNeighbours(encodeBytes []byte) (map[define.ChunkPos]*chunk.Chunk, error) {
+	neighbours := make(map[define.ChunkPos]*chunk.Chunk, len(encodeBytes)/16)
+	for ptr := 0; ptr+16 <= len(encodeBytes); ptr += 16 {
+		position := define.ChunkPos{
+			int32(binary.LittleEndian.Uint32(encodeBytes[ptr : ptr+4])),
+			int32(binary.LittleEndian.Uint32(encodeBytes[ptr+4 : ptr+8])),
+		}
+		id := int64(binary.LittleEndian.Uint64(encodeBytes[ptr+8 : ptr+16]))
+		c := savedChunk.LoadObject(int(id))
+		if c == nil {
+			return nil, fmt.Errorf("neighbour chunk %d at %v not found", id, position)
+		}
+		neighbours[position] = *c
+	}
+	return neighbours, nil
+}
+
+func packBlockCounts(counts map[uint32]uint32) (encodeBytes []byte) {
+	encodeBytes = make([]byte, 0, len(counts)*8)
+	for key, count := range counts {
+		encodeBytes = binary.LittleEndian.AppendUint32(encodeBytes, key)
+		encodeBytes = binary.LittleEndian.AppendUint32(encodeBytes, count)
+	}
+	return
+}
+
 func fromSubChunkPayload(rangeStart C.int, rangeEnd C.int, payload *C.char, e chunk.Encoding) (complexReturn *C.char) {
 	s, ind, err := chunk.DecodeSubChunk(
 		bytes.NewBuffer(asGoBytes(payload)),
@@ -0,0 +1,60 @@
+package chunk
+
+import (
+	"bytes"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// BlobHash computes the blob ID of the blob payload passed, which is the xxHash64 (with zero seed) of it
+// and is the same as the one that the client blob cache of Minecraft expects.
+func BlobHash(payload []byte) uint64 {
+	return xxhash.Sum64(payload)
+}
+
+// NetworkEncodeBlobs encodes the chunk passed to the blobs and the payload of a LevelChunk packet, which is
+// used when the client blob cache is enabled (the CacheEnabled field of the LevelChunk packet is true).
+//
+// blobs holds the network encoded sub chunks up to the highest filled one, and then the biomes of all sub
+// chunks as the last one. The hashes of blobs should be set as the BlobHashes field of the LevelChunk packet
+// in the same order, and the SubChunkCount field should be len(blobs)-1.
+//
+// payload only holds the border blocks (always empty) and the block entities passed, which are encoded in
+// network little endian NBT, and it should be set as the RawPayload field of the LevelChunk packet.
+func NetworkEncodeBlobs(c *Chunk, blockEntities []map[string]any) (payload []byte, blobs []protocol.CacheBlob) {
+	subChunkCount := int(c.HighestFilledSubChunk()) + 1
+	blobs = make([]protocol.CacheBlob, 0, subChunkCount+1)
+
+	for i := range subChunkCount {
+		subChunk := EncodeSubChunk(c.sub[i], c.r, i, NetworkEncoding)
+		blobs = append(blobs, protocol.CacheBlob{Hash: BlobHash(subChunk), Payload: subChunk})
+	}
+	biomes := EncodeBiomes(c, NetworkEncoding)
+	blobs = append(blobs, protocol.CacheBlob{Hash: BlobHash(biomes), Payload: biomes})
+
+	buf := bytes.NewBuffer(nil)
+	// Border blocks, which is only used by Education Edition.
+	_ = buf.WriteByte(0)
+	encodeNetworkBlockEntities(buf, blockEntities)
+
+	return buf.Bytes(), blobs
+}
+
+// NetworkEncodeSubChunkBlob is the same as NetworkEncodeSubChunk, but it is used when the client blob cache
+// is enabled. The network encoded sub chunk is returned as blob, and its hash is set as the BlobHash field of
+// entry. The RawPayload field of entry only holds the block entities in the sub chunk.
+//
+// found is false if there is no blob for the sub chunk, which means the result of entry is not
+// protocol.SubChunkResultSuccess.
+func NetworkEncodeSubChunkBlob(c *Chunk, index int, blockEntities []map[string]any) (entry protocol.SubChunkEntry, blob protocol.CacheBlob, found bool) {
+	entry, subChunk, blockEntityPayload := encodeSubChunkEntry(c, index, blockEntities)
+	if entry.Result != protocol.SubChunkResultSuccess {
+		return entry, protocol.CacheBlob{}, false
+	}
+
+	blob = protocol.CacheBlob{Hash: BlobHash(subChunk), Payload: subChunk}
+	entry.BlobHash, entry.RawPayload = blob.Hash, blockEntityPayload
+
+	return entry, blob, true
+}
@@ -1,6 +1,8 @@
 package chunk
 
 import (
+	"github.com/TriM-Organization/bedrock-world-operator/biome"
+	"github.com/TriM-Organization/bedrock-world-operator/block"
 	"github.com/TriM-Organization/bedrock-world-operator/define"
 )
 
@@ -35,7 +37,8 @@ func NewChunk(air uint32, r define.Range) *Chunk {
 	}
 }
 
-// Equals returns if the chunk passed is equal to the current one
+// Equals returns if the chunk passed is equal to the current one. The chunks are compared block by block
+// and biome by biome, so the ones with different palette orders could also be equal.
 func (chunk *Chunk) Equals(c *Chunk) bool {
 	if c.r != chunk.r || c.air != chunk.air || len(c.sub) != len(chunk.sub) {
 		return false
@@ -46,10 +49,33 @@ func (chunk *Chunk) Equals(c *Chunk) bool {
 			return false
 		}
 	}
+	for i, b := range c.biomes {
+		if !b.Equal(chunk.biomes[i]) {
+			return false
+		}
+	}
 
 	return true
 }
 
+// Clone returns a deep copy of the chunk. The sub chunks and biomes of the returned chunk do not share
+// any memory with the current one, so editing one of them does not affect the other.
+func (chunk *Chunk) Clone() *Chunk {
+	sub, biomes := make([]*SubChunk, len(chunk.sub)), make([]*PalettedStorage, len(chunk.biomes))
+	for i, s := range chunk.sub {
+		sub[i] = s.Clone()
+	}
+	for i, b := range chunk.biomes {
+		biomes[i] = b.Clone()
+	}
+	return &Chunk{
+		r:      chunk.r,
+		air:    chunk.air,
+		sub:    sub,
+		biomes: biomes,
+	}
+}
+
 // Range returns the define.Range of the Chunk as passed to NewChunk.
 func (chunk *Chunk) Range() define.Range {
 	return chunk.r
@@ -124,6 +150,48 @@ func (chunk *Chunk) SetBlocks(layer uint8, blocks [][]uint32) {
 	}
 }
 
+// BlockCounts returns the amount of every block (block runtime id) in layer of the whole chunk.
+// See SubChunk.BlockCounts for more information.
+func (chunk *Chunk) BlockCounts(layer uint8) map[uint32]uint32 {
+	result := make(map[uint32]uint32)
+	for _, sub := range chunk.sub {
+		for runtimeID, count := range sub.BlockCounts(layer) {
+			result[runtimeID] += count
+		}
+	}
+	return result
+}
+
+// BiomeCounts returns the amount of the blocks in every biome of the whole chunk.
+func (chunk *Chunk) BiomeCounts() map[uint32]uint32 {
+	result := make(map[uint32]uint32)
+	for _, biomes := range chunk.biomes {
+		for biome, count := range biomes.Counts() {
+			result[biome] += count
+		}
+	}
+	return result
+}
+
+// ReplaceBlocks replaces the blocks in layer of all sub chunks by mapping, whose keys are the runtime IDs of the
+// blocks to replace and values are the runtime IDs of the new blocks. See SubChunk.ReplaceBlocks for more
+// information. changed is true if any block is replaced.
+func (chunk *Chunk) ReplaceBlocks(layer uint8, mapping map[uint32]uint32) (changed bool) {
+	for _, sub := range chunk.sub {
+		changed = sub.ReplaceBlocks(layer, mapping) || changed
+	}
+	return
+}
+
+// ReplaceFunc replaces the blocks in layer of all sub chunks by f, which returns the runtime ID of the new block.
+// See SubChunk.ReplaceFunc for more information. changed is true if any block is replaced.
+func (chunk *Chunk) ReplaceFunc(layer uint8, f func(runtimeID uint32) uint32) (changed bool) {
+	for _, sub := range chunk.sub {
+		changed = sub.ReplaceFunc(layer, f) || changed
+	}
+	return
+}
+
 // Biome returns the biome ID at a specific column in the chunk.
 func (chunk *Chunk) Biome(x uint8, y int16, z uint8) uint32 {
 	return chunk.biomes[chunk.SubIndex(y)].At(x, uint8(y), z)
@@ -136,24 +204,9 @@ func (chunk *Chunk) Biome(x uint8, y int16, z uint8) uint32 {
 func (chunk *Chunk) Biomes() [][]uint32 {
 	n := (chunk.r.Height() >> 4) + 1
 	result := make([][]uint32, n)
-
 	for i := range n {
-		singleBiome := chunk.biomes[i]
-		singleBiomeIDs := make([]uint32, 4096)
-
-		ptr := 0
-		for x := range byte(16) {
-			for y := range byte(16) {
-				for z := range byte(16) {
-					singleBiomeIDs[ptr] = singleBiome.At(x, y, z)
-					ptr++
-				}
-			}
-		}
-
-		result[i] = singleBiomeIDs
+		result[i] = chunk.biomes[i].Values()
 	}
-
 	return result
 }
 
@@ -162,6 +215,23 @@ func (chunk *Chunk) SetBiome(x uint8, y int16, z uint8, biome uint32) {
 	chunk.biomes[chunk.SubIndex(y)].Set(x, uint8(y), z, biome)
 }
 
+// BiomeName returns the name of the biome at a specific column in the chunk, e.g. minecraft:plains.
+// found is false if the biome is not registered in the biome package, e.g. a custom biome.
+func (chunk *Chunk) BiomeName(x uint8, y int16, z uint8) (name string, found bool) {
+	return biome.Name(chunk.Biome(x, y, z))
+}
+
+// SetBiomeByName sets the biome at a specific column in the chunk by the name of the biome, which
+// could omit the "minecraft:" prefix. found is false if the biome is not registered in the biome
+// package, and the chunk is not modified.
+func (chunk *Chunk) SetBiomeByName(x uint8, y int16, z uint8, name string) (found bool) {
+	id, found := biome.ID(name)
+	if found {
+		chunk.SetBiome(x, y, z, id)
+	}
+	return found
+}
+
 // SetBiomes sets the biome IDs for each blocks in this chunk.
 // The length of biomes could less or bigger than the sub chunk counts of this
 // whole chunk.
@@ -173,23 +243,10 @@ func (chunk *Chunk) SetBiome(x uint8, y int16, z uint8, biome uint32) {
 func (chunk *Chunk) SetBiomes(biomes [][]uint32) {
 	n := (chunk.r.Height() >> 4) + 1
 	for i := range min(n, len(biomes)) {
-		singleBiomeIDs := biomes[i]
-		singleBiome := chunk.biomes[i]
-
-		ptr := 0
-		for x := range byte(16) {
-			for y := range byte(16) {
-				for z := range byte(16) {
-					singleBiome.Set(x, y, z, singleBiomeIDs[ptr])
-					ptr++
-				}
-			}
-		}
+		chunk.biomes[i] = storageFromValues(biomes[i])
 	}
 }
 
-// TODO: Expose this function to C API.
-//
 // HighestBlock iterates from the highest non-empty sub chunk downwards to find the Y value of the highest
 // non-air block at an x and z. If no blocks are present in the column, the minimum height is returned.
 func (chunk *Chunk) HighestBlock(x, z uint8) int16 {
@@ -205,6 +262,42 @@ func (chunk *Chunk) HighestBlock(x, z uint8) int16 {
 	return int16(chunk.r[0])
 }
 
+// HighestSolidBlock iterates from the highest non-empty sub chunk downwards to find the Y value of the
+// highest solid block at an x and z. Air-like blocks (e.g. short grass, flowers and torches) and liquids are
+// skipped, so the result is the ground that entities could stand on. If no solid blocks are present in the
+// column, the minimum height is returned.
+func (chunk *Chunk) HighestSolidBlock(x, z uint8) int16 {
+	for index := int16(len(chunk.sub) - 1); index >= 0; index-- {
+		if sub := chunk.sub[index]; !sub.Empty() {
+			for y := 15; y >= 0; y-- {
+				if c, _ := block.Classify(sub.storages[0].At(x, uint8(y), z)); c.Solid {
+					return int16(y) | chunk.SubY(index)
+				}
+			}
+		}
+	}
+	return int16(chunk.r[0])
+}
+
+// HighestLightBlocker iterates from the highest non-empty sub chunk downwards to find the Y value of the
+// highest block that filters light at an x and z, which is the value of the heightmap used by lighting.
+// Blocks of all layers are checked, so the water of a waterlogged block is also counted. If no such blocks
+// are present in the column, the minimum height is returned.
+func (chunk *Chunk) HighestLightBlocker(x, z uint8) int16 {
+	for index := int16(len(chunk.sub) - 1); index >= 0; index-- {
+		if sub := chunk.sub[index]; !sub.Empty() {
+			for y := 15; y >= 0; y-- {
+				for _, storage := range sub.storages {
+					if c, _ := block.Classify(storage.At(x, uint8(y), z)); c.LightFiltering > 0 {
+						return int16(y) | chunk.SubY(index)
+					}
+				}
+			}
+		}
+	}
+	return int16(chunk.r[0])
+}
+
 // Compact compacts the chunk as much as possible, getting rid of any sub chunks that are empty, and compacts
 // all storages in the sub chunks to occupy as little space as possible.
 // Compact should be called right before the chunk is saved in order to optimise the storage space.
@@ -37,7 +37,8 @@ func NetworkDecode(air uint32, data []byte, count int, r define.Range) (*Chunk,
 				// This should never happen and there is no way to handle this.
 				return nil, fmt.Errorf("first biome storage pointed to previous one")
 			}
-			b = last
+			// The storage is cloned, so that the sub chunks do not share the same storage.
+			b = last.Clone()
 		} else {
 			last = b
 		}
@@ -137,7 +138,8 @@ func DecodeBiomes(buf *bytes.Buffer, c *Chunk, e Encoding) error {
 			if b == nil {
 				// This means this paletted storage had the flag pointing to the previous one. It basically means we should
 				// inherit whatever palette we decoded last.
-				b = last
+				// The storage is cloned, so that the sub chunks do not share the same storage.
+				b = last.Clone()
 			} else {
 				last = b
 			}
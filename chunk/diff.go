@@ -0,0 +1,111 @@
+package chunk
+
+// BlockDiff is a block that differs between two chunks.
+type BlockDiff struct {
+	// X, Y and Z is the position of the block relative to the chunk.
+	X uint8
+	Y int16
+	Z uint8
+	// Layer is the layer of the block.
+	Layer uint8
+	// From and To are the block runtime IDs in the former
+	// and the latter chunk that passed to Diff.
+	From, To uint32
+}
+
+// BiomeDiff is a biome cell that differs between two chunks.
+type BiomeDiff struct {
+	// X, Y and Z is the position of the biome cell relative to the chunk.
+	X uint8
+	Y int16
+	Z uint8
+	// From and To are the biome IDs in the former and
+	// the latter chunk that passed to Diff.
+	From, To uint32
+}
+
+// ChunkDiff holds all the differences between two chunks.
+type ChunkDiff struct {
+	// Blocks holds the changed blocks, which are sorted by layer, and then by Y, X and Z.
+	Blocks []BlockDiff
+	// Biomes holds the changed biome cells, which are sorted by Y, X and Z.
+	Biomes []BiomeDiff
+}
+
+// Empty reports whether there are no differences.
+func (d ChunkDiff) Empty() bool {
+	return len(d.Blocks) == 0 && len(d.Biomes) == 0
+}
+
+// Layer returns the changed blocks in the given layer.
+func (d ChunkDiff) Layer(layer uint8) []BlockDiff {
+	result := make([]BlockDiff, 0)
+	for _, diff := range d.Blocks {
+		if diff.Layer == layer {
+			result = append(result, diff)
+		}
+	}
+	return result
+}
+
+// Diff compares a with b block by block (for every layer) and biome by biome, and returns all the
+// positions that differ. A layer that only exists in one of the chunks is compared as if it is filled
+// with air in the other one.
+//
+// a and b should have the same range. If not, only the positions in both of their ranges are compared.
+func Diff(a, b *Chunk) ChunkDiff {
+	var d ChunkDiff
+
+	layers := 0
+	for _, sub := range a.sub {
+		layers = max(layers, len(sub.storages))
+	}
+	for _, sub := range b.sub {
+		layers = max(layers, len(sub.storages))
+	}
+
+	minY, maxY := int16(max(a.r[0], b.r[0])), int16(min(a.r[1], b.r[1]))
+	for layer := range uint8(layers) {
+		for y := minY &^ 15; y <= maxY; y += 16 {
+			subA, subB := a.SubChunk(y), b.SubChunk(y)
+			if layer < uint8(len(subA.storages)) && layer < uint8(len(subB.storages)) && subA.storages[layer].Equal(subB.storages[layer]) {
+				continue
+			}
+			for offset := range int16(16) {
+				if y+offset < minY || y+offset > maxY {
+					continue
+				}
+				for x := range uint8(16) {
+					for z := range uint8(16) {
+						from, to := subA.Block(x, uint8(offset), z, layer), subB.Block(x, uint8(offset), z, layer)
+						if from != to {
+							d.Blocks = append(d.Blocks, BlockDiff{X: x, Y: y + offset, Z: z, Layer: layer, From: from, To: to})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for y := minY &^ 15; y <= maxY; y += 16 {
+		biomesA, biomesB := a.biomes[a.SubIndex(y)], b.biomes[b.SubIndex(y)]
+		if biomesA.Equal(biomesB) {
+			continue
+		}
+		for offset := range int16(16) {
+			if y+offset < minY || y+offset > maxY {
+				continue
+			}
+			for x := range uint8(16) {
+				for z := range uint8(16) {
+					from, to := biomesA.At(x, uint8(offset), z), biomesB.At(x, uint8(offset), z)
+					if from != to {
+						d.Biomes = append(d.Biomes, BiomeDiff{X: x, Y: y + offset, Z: z, From: from, To: to})
+					}
+				}
+			}
+		}
+	}
+
+	return d
+}
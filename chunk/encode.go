@@ -88,7 +88,7 @@ func EncodeBiomes(c *Chunk, e Encoding) []byte {
 // encodePalettedStorage encodes a PalettedStorage into a bytes.Buffer. The Encoding passed is used to write the Palette
 // of the PalettedStorage.
 func encodePalettedStorage(buf *bytes.Buffer, storage, previous *PalettedStorage, e Encoding, pe paletteEncoding) {
-	if storage.Equal(previous) {
+	if storage.sameLayout(previous) {
 		_, _ = buf.Write([]byte{0x7f<<1 | e.network()})
 		return
 	}
@@ -0,0 +1,153 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// testChunk returns a chunk that holds some blocks, whose biomes are all plains except the
+// two sub chunks at the top, which hold the same mixed biomes.
+func testChunk(t *testing.T) *Chunk {
+	stone, found := block.StateToRuntimeID("minecraft:stone", nil)
+	if !found {
+		t.Fatal("stone is not found")
+	}
+
+	c := NewChunk(block.AirRuntimeID, define.Dimension(define.DimensionIDOverworld).Range())
+	for x := range uint8(16) {
+		for z := range uint8(16) {
+			for y := int16(-64); y < 10; y++ {
+				c.SetBlock(x, y, z, 0, stone)
+			}
+		}
+	}
+
+	plains := make([]uint32, 4096)
+	for i := range plains {
+		plains[i] = 1
+	}
+	mixed := make([]uint32, 4096)
+	for i := range mixed {
+		mixed[i] = uint32(i % 3)
+	}
+	biomes := make([][]uint32, len(c.Sub()))
+	for i := range biomes {
+		biomes[i] = plains
+	}
+	biomes[len(biomes)-1], biomes[len(biomes)-2] = mixed, mixed
+	c.SetBiomes(biomes)
+	return c
+}
+
+// checkBiomesNotShared checks if setting a biome of c only changes the sub chunk it is in.
+func checkBiomesNotShared(t *testing.T, c *Chunk) {
+	r := c.Range()
+	for _, y := range []int16{int16(r[0]) + 4, int16(r[1]) - 4} {
+		before := c.Biomes()
+		c.SetBiome(0, y, 0, 190)
+		after := c.Biomes()
+		changed := 0
+		for i := range before {
+			for j := range before[i] {
+				if before[i][j] != after[i][j] {
+					changed++
+				}
+			}
+		}
+		if changed != 1 {
+			t.Fatalf("setting the biome at y %d changed %d biomes, expected 1", y, changed)
+		}
+	}
+}
+
+func TestDiskEncodingRoundTrip(t *testing.T) {
+	c := testChunk(t)
+	decoded, err := DiskDecode(Encode(c, DiskEncoding), c.Range())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(decoded) {
+		t.Fatal("decoded chunk is not equal to the encoded one")
+	}
+	checkBiomesNotShared(t, decoded)
+}
+
+func TestNetworkEncodingRoundTrip(t *testing.T) {
+	c := testChunk(t)
+	data := Encode(c, NetworkEncoding)
+
+	var buf bytes.Buffer
+	for _, sub := range data.SubChunks {
+		buf.Write(sub)
+	}
+	buf.Write(data.Biomes)
+
+	decoded, err := NetworkDecode(block.AirRuntimeID, buf.Bytes(), len(data.SubChunks), c.Range())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(decoded) {
+		t.Fatal("decoded chunk is not equal to the encoded one")
+	}
+	checkBiomesNotShared(t, decoded)
+}
+
+func TestEncodeBiomesReferencesPrevious(t *testing.T) {
+	c := testChunk(t)
+	decoded, err := DiskDecode(Encode(c, DiskEncoding), c.Range())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The two sub chunks at the top hold the same biomes, so the second one is written as a reference
+	// to the first one, but they must not share the same storage after decoded.
+	n := len(decoded.biomes)
+	if decoded.biomes[n-1] == decoded.biomes[n-2] {
+		t.Fatal("the biomes of two sub chunks share the same storage")
+	}
+	if !decoded.biomes[n-1].Equal(decoded.biomes[n-2]) {
+		t.Fatal("the biomes of the two sub chunks at the top are not equal")
+	}
+}
+
+func TestNewDiskEncodingDowngrade(t *testing.T) {
+	// 1.16.0, which is before tuff is added.
+	const version int32 = 1<<24 | 16<<16
+	stone, _ := block.StateToRuntimeID("minecraft:stone", nil)
+	tuff, found := block.StateToRuntimeID("minecraft:tuff", nil)
+	if !found {
+		t.Fatal("tuff is not found")
+	}
+
+	state := NewBlockPaletteEncoding(version, stone).EncodeBlockState(tuff)
+	if state.Version != version || state.Name == "minecraft:tuff" {
+		t.Fatalf("expected tuff to be replaced at version %d, but got %v", version, state)
+	}
+	// The default encoding must not be affected by the one with a target version.
+	if state = BlockPaletteEncoding.EncodeBlockState(tuff); state.Version != CurrentBlockVersion || state.Name != "minecraft:tuff" {
+		t.Fatalf("expected tuff of the current version from BlockPaletteEncoding, but got %v", state)
+	}
+
+	c := testChunk(t)
+	c.SetBlock(1, 20, 2, 0, tuff)
+	downgraded, err := DiskDecode(Encode(c, NewDiskEncoding(version, stone)), c.Range())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := downgraded.Block(1, 20, 2, 0); got != stone {
+		t.Fatalf("expected tuff to be replaced by stone, but got %d", got)
+	}
+	if got := downgraded.Block(1, 0, 2, 0); got != stone {
+		t.Fatalf("expected stone to be kept, but got %d", got)
+	}
+
+	current, err := DiskDecode(Encode(c, DiskEncoding), c.Range())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(current) {
+		t.Fatal("the chunk encoded by DiskEncoding is changed after encoding with a target version")
+	}
+}
@@ -41,6 +41,21 @@ var (
 	BlockPaletteEncoding blockPaletteEncoding
 )
 
+// NewDiskEncoding returns an Encoding for writing a Chunk to disk like DiskEncoding, but the block states are
+// encoded by NewBlockPaletteEncoding(targetVersion, replacement), so that the older game (e.g. the NetEase one)
+// could load them. It decodes in the same way as DiskEncoding.
+func NewDiskEncoding(targetVersion int32, replacement uint32) Encoding {
+	return downgradeEncoding{blocks: NewBlockPaletteEncoding(targetVersion, replacement)}
+}
+
+// NewBlockPaletteEncoding returns a paletteEncoding of block states like BlockPaletteEncoding, but EncodeBlockState
+// of it produces the block states of targetVersion. If targetVersion is lower than CurrentBlockVersion, the block
+// states are downgraded to the names and properties that valid at that version, and replacement is used for the
+// blocks that are not exist at that version. If the replacement block is also not exist, then air is used.
+func NewBlockPaletteEncoding(targetVersion int32, replacement uint32) blockPaletteEncoding {
+	return blockPaletteEncoding{targetVersion: targetVersion, replacement: replacement}
+}
+
 // biomePaletteEncoding implements the encoding of biome palettes to disk.
 type biomePaletteEncoding struct{}
 
@@ -53,7 +68,13 @@ func (biomePaletteEncoding) decode(buf *bytes.Buffer) (uint32, error) {
 }
 
 // blockPaletteEncoding implements the encoding of block palettes to disk.
-type blockPaletteEncoding struct{}
+type blockPaletteEncoding struct {
+	// targetVersion is the version of the block states to encode. The block states are not
+	// downgraded if it is 0 or not lower than CurrentBlockVersion.
+	targetVersion int32
+	// replacement is the runtime ID of the block to use when a block is not exist at targetVersion.
+	replacement uint32
+}
 
 func (bpe blockPaletteEncoding) encode(buf *bytes.Buffer, v uint32) {
 	_ = nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian).Encode(bpe.EncodeBlockState(v))
@@ -66,7 +87,7 @@ func (bpe blockPaletteEncoding) decode(buf *bytes.Buffer) (uint32, error) {
 	return bpe.DecodeBlockState(m)
 }
 
-func (blockPaletteEncoding) EncodeBlockState(v uint32) define.BlockState {
+func (bpe blockPaletteEncoding) EncodeBlockState(v uint32) define.BlockState {
 	// Get the block state registered with the runtime IDs we have in the palette of the block storage
 	// as we need the name and data value to store.
 	name, props, found := block.RuntimeIDToState(v)
@@ -81,7 +102,20 @@ func (blockPaletteEncoding) EncodeBlockState(v uint32) define.BlockState {
 			),
 		)
 	}
-	return define.BlockState{Name: name, Properties: props, Version: CurrentBlockVersion}
+	if bpe.targetVersion == 0 || bpe.targetVersion >= CurrentBlockVersion {
+		return define.BlockState{Name: name, Properties: props, Version: CurrentBlockVersion}
+	}
+
+	// Downgrade the block state for the older game, and use
+	// the replacement block if the block is not exist there.
+	name, props, found = block.Downgrade(v, bpe.targetVersion)
+	if !found {
+		name, props, found = block.Downgrade(bpe.replacement, bpe.targetVersion)
+	}
+	if !found {
+		name, props = "minecraft:air", map[string]any{}
+	}
+	return define.BlockState{Name: name, Properties: props, Version: bpe.targetVersion}
 }
 
 func (blockPaletteEncoding) DecodeBlockState(m map[string]any) (uint32, error) {
@@ -119,7 +153,7 @@ func (blockPaletteEncoding) DecodeBlockState(m map[string]any) (uint32, error) {
 		// Target block is a block we don't know, and we don't want return error
 		// because this is not a big problem. Just redirect it as a unknown block,
 		// and print error information to the user so that they can solve.
-		v = block.ComputeBlockHash("minecraft:unknown", map[string]any{})
+		v = block.UnknownRuntimeID
 		// For some reason, if the user use the wrong version of bedrock world operator
 		// which lower than 1.2.1, the mcworld can save the block that have no name.
 		// It happened so many times, so here we only output messages for those blocks that
@@ -168,6 +202,20 @@ func (diskEncoding) decodePalette(buf *bytes.Buffer, blockSize paletteSize, e pa
 	return palette, nil
 }
 
+// downgradeEncoding implements the Chunk encoding for writing to disk, whose block palettes are
+// encoded by blocks instead of BlockPaletteEncoding.
+type downgradeEncoding struct {
+	diskEncoding
+	blocks blockPaletteEncoding
+}
+
+func (d downgradeEncoding) encodePalette(buf *bytes.Buffer, p *Palette, e paletteEncoding) {
+	if _, ok := e.(blockPaletteEncoding); ok {
+		e = d.blocks
+	}
+	d.diskEncoding.encodePalette(buf, p, e)
+}
+
 // networkEncoding implements the Chunk encoding for sending over network.
 type networkEncoding struct{}
 
@@ -0,0 +1,285 @@
+package chunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/TriM-Organization/bedrock-world-operator/biome"
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	block_general "github.com/TriM-Organization/bedrock-world-operator/block/general"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// The JSON representation of a Chunk, see Chunk.MarshalJSON for more information.
+type (
+	chunkJSON struct {
+		Range     [2]int         `json:"range"`
+		SubChunks []subChunkJSON `json:"sub_chunks"`
+		Biomes    []biomesJSON   `json:"biomes"`
+	}
+	subChunkJSON struct {
+		Y      int16       `json:"y"`
+		Layers []layerJSON `json:"layers"`
+	}
+	layerJSON struct {
+		Palette []blockStateJSON `json:"palette"`
+		Indices []uint16         `json:"indices,omitempty"`
+	}
+	blockStateJSON struct {
+		Name   string         `json:"name"`
+		States map[string]any `json:"states"`
+	}
+	biomesJSON struct {
+		Y       int16    `json:"y"`
+		Palette []string `json:"palette"`
+		Indices []uint16 `json:"indices,omitempty"`
+	}
+)
+
+// MarshalJSON encodes the chunk as JSON, which holds the blocks and the biomes of the chunk by their names
+// instead of their runtime IDs and numeric IDs, so that it is stable between versions and could be read by
+// people. The result is the same for the chunks that are equal, so it could be used for golden tests and
+// diffing. The format is as follows:
+//
+//	{
+//	  "range": [-64, 319],
+//	  "sub_chunks": [
+//	    {
+//	      "y": -4,
+//	      "layers": [
+//	        {
+//	          "palette": [{"name": "minecraft:stone", "states": {}}, ...],
+//	          "indices": [0, 1, ...]
+//	        }
+//	      ]
+//	    }
+//	  ],
+//	  "biomes": [{"y": -4, "palette": ["minecraft:plains", ...], "indices": [0, 1, ...]}]
+//	}
+//
+// range is the vertical range of the chunk. sub_chunks only holds the sub chunks that are not empty, where
+// y is the Y of the sub chunk (the Y of its lowest blocks divided by 16), and layers holds the layers from
+// layer 0. Each layer holds the block states as a palette, and the palette index of every block, which is
+// the palette index of the block at x, y and z is indices[(x<<8)|(z<<4)|y], where x, y and z are relative
+// to the sub chunk. The palette holds the blocks in the order they first appear in indices, and indices is
+// omitted if the palette only holds one block. biomes holds the biomes of every sub chunk in the same way,
+// where the biomes are their names, or the numeric IDs in decimal if they are unknown, e.g. the custom ones.
+func (chunk *Chunk) MarshalJSON() ([]byte, error) {
+	result := chunkJSON{
+		Range:     [2]int{chunk.r[0], chunk.r[1]},
+		SubChunks: make([]subChunkJSON, 0),
+		Biomes:    make([]biomesJSON, 0, len(chunk.biomes)),
+	}
+
+	for index, sub := range chunk.sub {
+		if sub.Empty() {
+			continue
+		}
+		s := subChunkJSON{Y: chunk.SubY(int16(index)) >> 4, Layers: make([]layerJSON, 0, len(sub.storages))}
+		for _, storage := range sub.storages {
+			palette, indices := canonicalIndices(storage)
+			layer := layerJSON{Palette: make([]blockStateJSON, len(palette)), Indices: indices}
+			for i, runtimeID := range palette {
+				name, properties, found := block.RuntimeIDToState(runtimeID)
+				if !found {
+					return nil, fmt.Errorf("MarshalJSON: unknown block runtime ID %d in sub chunk %d", runtimeID, s.Y)
+				}
+				layer.Palette[i] = blockStateJSON{Name: name, States: properties}
+				if properties == nil {
+					layer.Palette[i].States = make(map[string]any)
+				}
+			}
+			s.Layers = append(s.Layers, layer)
+		}
+		result.SubChunks = append(result.SubChunks, s)
+	}
+
+	for index, storage := range chunk.biomes {
+		palette, indices := canonicalIndices(storage)
+		b := biomesJSON{Y: chunk.SubY(int16(index)) >> 4, Palette: make([]string, len(palette)), Indices: indices}
+		for i, id := range palette {
+			name, found := biome.Name(id)
+			if !found {
+				name = strconv.FormatUint(uint64(id), 10)
+			}
+			b.Palette[i] = name
+		}
+		result.Biomes = append(result.Biomes, b)
+	}
+
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON decodes the JSON made by MarshalJSON into the chunk, which replaces all the sub chunks and
+// the biomes of the chunk, as well as its range. The types of the block states are restored from the
+// properties of the blocks, so the numbers of the states could be either the byte or int ones.
+//
+// An error is returned if any block or biome is unknown, or the indices are not valid. The states of a
+// block that are not valid are replaced by the default ones.
+func (chunk *Chunk) UnmarshalJSON(data []byte) error {
+	var src chunkJSON
+	if err := json.Unmarshal(data, &src); err != nil {
+		return fmt.Errorf("UnmarshalJSON: %v", err)
+	}
+	if src.Range[0] > src.Range[1] || (src.Range[1]-src.Range[0]+1)%16 != 0 {
+		return fmt.Errorf("UnmarshalJSON: invalid range %v", src.Range)
+	}
+
+	if chunk.sub == nil {
+		// The chunk is the zero value, e.g. new(Chunk).
+		chunk.air = block.AirRuntimeID
+	}
+	result := NewChunk(chunk.air, define.Range{src.Range[0], src.Range[1]})
+
+	for _, s := range src.SubChunks {
+		index := result.SubIndex(s.Y << 4)
+		if index < 0 || int(index) >= len(result.sub) {
+			return fmt.Errorf("UnmarshalJSON: sub chunk %d is out of range %v", s.Y, src.Range)
+		}
+		if len(s.Layers) > math.MaxUint8+1 {
+			return fmt.Errorf("UnmarshalJSON: sub chunk %d has %d layers, which is more than %d", s.Y, len(s.Layers), math.MaxUint8+1)
+		}
+		sub := NewSubChunk(chunk.air)
+		for layer, l := range s.Layers {
+			palette := make([]uint32, len(l.Palette))
+			for i, state := range l.Palette {
+				runtimeID, err := stateToRuntimeID(state)
+				if err != nil {
+					return fmt.Errorf("UnmarshalJSON: sub chunk %d: %v", s.Y, err)
+				}
+				palette[i] = runtimeID
+			}
+			indices, err := checkIndices(l.Indices, len(palette))
+			if err != nil {
+				return fmt.Errorf("UnmarshalJSON: sub chunk %d: %v", s.Y, err)
+			}
+			if err = sub.SetLayerIndices(uint8(layer), palette, indices); err != nil {
+				return fmt.Errorf("UnmarshalJSON: sub chunk %d: %v", s.Y, err)
+			}
+		}
+		result.sub[index] = sub
+	}
+
+	for _, b := range src.Biomes {
+		index := result.SubIndex(b.Y << 4)
+		if index < 0 || int(index) >= len(result.biomes) {
+			return fmt.Errorf("UnmarshalJSON: biomes of sub chunk %d is out of range %v", b.Y, src.Range)
+		}
+		palette := make([]uint32, len(b.Palette))
+		for i, name := range b.Palette {
+			id, found := biome.ID(name)
+			if !found {
+				v, err := strconv.ParseUint(name, 10, 32)
+				if err != nil {
+					return fmt.Errorf("UnmarshalJSON: unknown biome %#v", name)
+				}
+				id = uint32(v)
+			}
+			palette[i] = id
+		}
+		indices, err := checkIndices(b.Indices, len(palette))
+		if err != nil {
+			return fmt.Errorf("UnmarshalJSON: biomes of sub chunk %d: %v", b.Y, err)
+		}
+		if result.biomes[index], err = NewPalettedStorageFromIndices(palette, indices); err != nil {
+			return fmt.Errorf("UnmarshalJSON: biomes of sub chunk %d: %v", b.Y, err)
+		}
+	}
+
+	*chunk = *result
+	return nil
+}
+
+// canonicalIndices returns the palette and the indices of storage, where the palette only holds the values
+// that are used in the order they first appear. indices is nil if the palette only holds one value.
+func canonicalIndices(storage *PalettedStorage) (palette []uint32, indices []uint16) {
+	oldPalette, oldIndices := storage.Indices()
+	lookup := make(map[uint32]uint16, len(oldPalette))
+	for i, index := range oldIndices {
+		v := oldPalette[index]
+		newIndex, ok := lookup[v]
+		if !ok {
+			newIndex = uint16(len(palette))
+			palette, lookup[v] = append(palette, v), newIndex
+		}
+		oldIndices[i] = newIndex
+	}
+	if len(palette) == 1 {
+		return palette, nil
+	}
+	return palette, oldIndices
+}
+
+// checkIndices checks if indices are valid for a palette that holds paletteSize values,
+// and returns indices of all 0 if indices is empty.
+func checkIndices(indices []uint16, paletteSize int) ([]uint16, error) {
+	if paletteSize == 0 {
+		return nil, fmt.Errorf("palette is empty")
+	}
+	if len(indices) == 0 {
+		if paletteSize > 1 {
+			return nil, fmt.Errorf("indices is missing for a palette of %d values", paletteSize)
+		}
+		return make([]uint16, 4096), nil
+	}
+	if len(indices) != 4096 {
+		return nil, fmt.Errorf("expected 4096 indices, but got %d", len(indices))
+	}
+	for _, index := range indices {
+		if int(index) >= paletteSize {
+			return nil, fmt.Errorf("index %d is out of the palette of %d values", index, paletteSize)
+		}
+	}
+	return indices, nil
+}
+
+// stateToRuntimeID returns the runtime ID of state, where the types of the values of the states
+// are converted to the ones of the properties of the block.
+func stateToRuntimeID(state blockStateJSON) (uint32, error) {
+	properties, found := block.Properties(state.Name)
+	if !found {
+		return 0, fmt.Errorf("unknown block %#v", state.Name)
+	}
+
+	states := make(map[string]any, len(state.States))
+	for _, property := range properties {
+		v, ok := state.States[property.Name]
+		if !ok {
+			continue
+		}
+		switch value := v.(type) {
+		case float64:
+			switch property.Type {
+			case block_general.StateKeyTypeByte:
+				states[property.Name] = byte(value)
+			case block_general.StateKeyTypeInt32:
+				states[property.Name] = int32(value)
+			default:
+				return 0, fmt.Errorf("state %#v of %#v must be a string", property.Name, state.Name)
+			}
+		case bool:
+			if property.Type != block_general.StateKeyTypeByte {
+				return 0, fmt.Errorf("state %#v of %#v must not be a boolean", property.Name, state.Name)
+			}
+			states[property.Name] = byte(0)
+			if value {
+				states[property.Name] = byte(1)
+			}
+		case string:
+			if property.Type != block_general.StateKeyTypeString {
+				return 0, fmt.Errorf("state %#v of %#v must be a number", property.Name, state.Name)
+			}
+			states[property.Name] = value
+		default:
+			return 0, fmt.Errorf("state %#v of %#v has invalid value %v", property.Name, state.Name, v)
+		}
+	}
+
+	runtimeID, found := block.StateToRuntimeID(state.Name, states)
+	if !found {
+		return 0, fmt.Errorf("unknown block %#v", state.Name)
+	}
+	return runtimeID, nil
+}
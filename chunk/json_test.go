@@ -0,0 +1,72 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	stairs, found := block.StateToRuntimeID("minecraft:oak_stairs", map[string]any{"upside_down_bit": byte(1), "weirdo_direction": int32(2)})
+	if !found {
+		t.Fatal("oak stairs is not found")
+	}
+
+	c := testChunk(t)
+	c.SetBlock(3, 20, 5, 0, stairs)
+	c.SetBlock(3, 20, 5, 1, block.WaterRuntimeID)
+	// 250 is not a known biome, so it is written as its numeric ID.
+	c.SetBiome(7, 100, 9, 250)
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(Chunk)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(decoded) {
+		t.Fatal("decoded chunk is not equal to the encoded one")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Fatal("the JSON of the decoded chunk is not the same as the original one")
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"range":[0,10],"sub_chunks":[],"biomes":[]}`,
+		`{"range":[0,15],"sub_chunks":[{"y":0,"layers":[{"palette":[{"name":"minecraft:not_a_block","states":{}}]}]}],"biomes":[]}`,
+		`{"range":[0,15],"sub_chunks":[{"y":0,"layers":[{"palette":[{"name":"minecraft:stone","states":{}}],"indices":[1]}]}],"biomes":[]}`,
+	} {
+		if err := json.Unmarshal([]byte(data), new(Chunk)); err == nil {
+			t.Fatalf("expected an error for %s", data)
+		}
+	}
+
+	// A palette of more than 65536 values can not be held by a PalettedStorage, even if every index is valid.
+	palette := make([]string, 1<<16+1)
+	for i := range palette {
+		palette[i] = strconv.Itoa(i)
+	}
+	data, err := json.Marshal(map[string]any{
+		"range":      []int{0, 15},
+		"sub_chunks": []any{},
+		"biomes":     []any{map[string]any{"y": 0, "palette": palette, "indices": make([]int, 4096)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, new(Chunk)); err == nil {
+		t.Fatal("expected an error for biomes with a palette of 65537 values")
+	}
+}
@@ -0,0 +1,79 @@
+package chunk
+
+import "github.com/TriM-Organization/bedrock-world-operator/block"
+
+const (
+	// BlockLayer is the layer that holds the blocks, which is the only layer for most blocks.
+	BlockLayer uint8 = 0
+	// LiquidLayer is the layer that holds the liquid in the same position with the block in BlockLayer,
+	// which is water for a waterlogged block.
+	//
+	// Edit operations that take a layer (e.g. SetBlock, SetBlocks and ReplaceBlocks) only touch the layer
+	// that passed, and the other layers are kept as is. Use PlaceBlock to set the block in BlockLayer while
+	// keeping LiquidLayer coherent with it.
+	LiquidLayer uint8 = 1
+)
+
+// IsWaterlogged reports whether the block at the given X, Y and Z is waterlogged, which means there is a
+// liquid in LiquidLayer. X, Y and Z must be in a range of 0-15.
+func (sub *SubChunk) IsWaterlogged(x, y, z byte) bool {
+	if uint8(len(sub.storages)) <= LiquidLayer {
+		return false
+	}
+	c, _ := block.Classify(sub.storages[LiquidLayer].At(x, y, z))
+	return c.Liquid
+}
+
+// SetWaterlogged sets water to LiquidLayer at the given X, Y and Z if waterlogged is true, or clears
+// LiquidLayer at there if not. X, Y and Z must be in a range of 0-15.
+//
+// ok is false if waterlogged is true but the block in BlockLayer could not be waterlogged, and nothing
+// is changed in this case.
+func (sub *SubChunk) SetWaterlogged(x, y, z byte, waterlogged bool) (ok bool) {
+	if !waterlogged {
+		if uint8(len(sub.storages)) > LiquidLayer {
+			sub.storages[LiquidLayer].Set(x, y, z, sub.air)
+		}
+		return true
+	}
+
+	c, _ := block.Classify(sub.Block(x, y, z, BlockLayer))
+	if !c.Waterloggable {
+		return false
+	}
+	sub.Layer(LiquidLayer).Set(x, y, z, block.WaterRuntimeID)
+	return true
+}
+
+// PlaceBlock sets the block in BlockLayer at the given X, Y and Z to the block whose runtime ID is
+// runtimeID, in the same way as the game places a block. X, Y and Z must be in a range of 0-15.
+//
+// If the new block could be waterlogged, then it is waterlogged when the old position is waterlogged
+// or the old block is a water source. Otherwise, LiquidLayer at there is cleared.
+func (sub *SubChunk) PlaceBlock(x, y, z byte, runtimeID uint32) {
+	waterlogged := sub.IsWaterlogged(x, y, z) || sub.Block(x, y, z, BlockLayer) == block.WaterRuntimeID
+
+	sub.SetBlock(x, y, z, BlockLayer, runtimeID)
+	if c, _ := block.Classify(runtimeID); !c.Waterloggable {
+		waterlogged = false
+	}
+	sub.SetWaterlogged(x, y, z, waterlogged)
+}
+
+// IsWaterlogged reports whether the block at the given X, Y and Z is waterlogged.
+// See SubChunk.IsWaterlogged for more information.
+func (chunk *Chunk) IsWaterlogged(x uint8, y int16, z uint8) bool {
+	return chunk.sub[chunk.SubIndex(y)].IsWaterlogged(x, uint8(y), z)
+}
+
+// SetWaterlogged sets or clears water in LiquidLayer at the given X, Y and Z.
+// See SubChunk.SetWaterlogged for more information.
+func (chunk *Chunk) SetWaterlogged(x uint8, y int16, z uint8, waterlogged bool) (ok bool) {
+	return chunk.sub[chunk.SubIndex(y)].SetWaterlogged(x, uint8(y), z, waterlogged)
+}
+
+// PlaceBlock sets the block in BlockLayer at the given X, Y and Z and keeps LiquidLayer coherent
+// with it. See SubChunk.PlaceBlock for more information.
+func (chunk *Chunk) PlaceBlock(x uint8, y int16, z uint8, runtimeID uint32) {
+	chunk.sub[chunk.SubIndex(y)].PlaceBlock(x, uint8(y), z, runtimeID)
+}
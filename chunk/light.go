@@ -0,0 +1,277 @@
+package chunk
+
+import (
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+const (
+	// maxLightLevel is the highest level of both sky light and block light.
+	maxLightLevel = 15
+	// lightAreaWidth is the width (in chunks) of the area that used to compute the
+	// light of a chunk, which holds the chunk itself and its 8 neighbours.
+	lightAreaWidth = 3
+	// lightAreaBlocks is the width (in blocks) of the area.
+	lightAreaBlocks = lightAreaWidth * 16
+	// lightMissing is the filtering of the positions whose chunk is not provided,
+	// and light never spreads into such positions.
+	lightMissing = 0xFF
+)
+
+// SkyLight returns the sky light level (0 to 15) at the given X, Y and Z. X, Y and Z must be in a range
+// of 0-15. 0 is returned if the light of the sub chunk has not been computed.
+func (sub *SubChunk) SkyLight(x, y, z byte) uint8 {
+	return nibbleAt(sub.skyLight, x, y, z)
+}
+
+// SetSkyLight sets the sky light level (0 to 15) at the given X, Y and Z. X, Y and Z must be in a range of 0-15.
+func (sub *SubChunk) SetSkyLight(x, y, z byte, level uint8) {
+	if sub.skyLight == nil {
+		sub.skyLight = make([]uint8, 2048)
+	}
+	setNibble(sub.skyLight, x, y, z, level)
+}
+
+// BlockLight returns the block light level (0 to 15) at the given X, Y and Z. X, Y and Z must be in a range
+// of 0-15. 0 is returned if the light of the sub chunk has not been computed.
+func (sub *SubChunk) BlockLight(x, y, z byte) uint8 {
+	return nibbleAt(sub.blockLight, x, y, z)
+}
+
+// SetBlockLight sets the block light level (0 to 15) at the given X, Y and Z. X, Y and Z must be in a range of 0-15.
+func (sub *SubChunk) SetBlockLight(x, y, z byte, level uint8) {
+	if sub.blockLight == nil {
+		sub.blockLight = make([]uint8, 2048)
+	}
+	setNibble(sub.blockLight, x, y, z, level)
+}
+
+// SkyLight returns the sky light level (0 to 15) at a given x, y and z in a chunk.
+// 0 is returned if the light of the chunk has not been computed.
+func (chunk *Chunk) SkyLight(x uint8, y int16, z uint8) uint8 {
+	return chunk.SubChunk(y).SkyLight(x, uint8(y), z)
+}
+
+// BlockLight returns the block light level (0 to 15) at a given x, y and z in a chunk.
+// 0 is returned if the light of the chunk has not been computed.
+func (chunk *Chunk) BlockLight(x uint8, y int16, z uint8) uint8 {
+	return chunk.SubChunk(y).BlockLight(x, uint8(y), z)
+}
+
+// Light returns the light level (0 to 15) at a given x, y and z in a chunk,
+// which is the highest one of the sky light and the block light.
+func (chunk *Chunk) Light(x uint8, y int16, z uint8) uint8 {
+	return max(chunk.SkyLight(x, y, z), chunk.BlockLight(x, y, z))
+}
+
+// ComputeLight computes the sky light and block light of the chunk c whose position is pos, and stores
+// the result in each of its sub chunks.
+//
+// neighbours holds the chunks around c, which is indexed by their positions. Light spreads from (and
+// through) these chunks into c, so the light at the border of c is the same as the one computed by
+// Minecraft. Chunks not in neighbours are treated as not existing, and neighbours could be nil if c is
+// computed alone. Only c is modified, and the chunks in neighbours must have the same range as c.
+//
+// The opacity and emission of blocks come from block.Classify, and blocks of all layers are counted, so
+// the water of a waterlogged block filters light as well.
+func ComputeLight(pos define.ChunkPos, c *Chunk, neighbours map[define.ChunkPos]*Chunk) {
+	area := newLightArea(c.r)
+	for dx := range int32(lightAreaWidth) {
+		for dz := range int32(lightAreaWidth) {
+			if dx == 1 && dz == 1 {
+				area.load(c, dx, dz)
+				continue
+			}
+			neighbour, ok := neighbours[define.ChunkPos{pos[0] + dx - 1, pos[1] + dz - 1}]
+			if ok && neighbour != nil && neighbour.r == c.r {
+				area.load(neighbour, dx, dz)
+			}
+		}
+	}
+	area.computeSkyLight()
+	area.computeBlockLight()
+	area.store(c, 1, 1)
+}
+
+// lightArea is a square area of chunks that light is computed in.
+// Each position of the area is indexed by (x*lightAreaBlocks+z)*height+y.
+type lightArea struct {
+	// height is the height (in blocks) of the area.
+	height int
+	// filtering and emission hold the light filtering and
+	// light emission of the blocks in each position.
+	filtering []uint8
+	emission  []uint8
+	// skyLight and blockLight hold the light computed.
+	skyLight   []uint8
+	blockLight []uint8
+}
+
+// newLightArea creates a new light area whose vertical range is r.
+// All the positions are considered missing until a chunk is loaded.
+func newLightArea(r define.Range) *lightArea {
+	height := (r.Height()>>4 + 1) << 4
+	n := lightAreaBlocks * lightAreaBlocks * height
+	area := &lightArea{
+		height:     height,
+		filtering:  make([]uint8, n),
+		emission:   make([]uint8, n),
+		skyLight:   make([]uint8, n),
+		blockLight: make([]uint8, n),
+	}
+	for i := range area.filtering {
+		area.filtering[i] = lightMissing
+	}
+	return area
+}
+
+// index returns the index of the position x, y and z in the area.
+func (a *lightArea) index(x, y, z int) int {
+	return (x*lightAreaBlocks+z)*a.height + y
+}
+
+// load loads the light filtering and light emission of all blocks in c, whose
+// offset (in chunks) in the area is dx and dz.
+func (a *lightArea) load(c *Chunk, dx, dz int32) {
+	baseX, baseZ := int(dx)<<4, int(dz)<<4
+	for i, sub := range c.sub {
+		baseY := i << 4
+
+		for x := range 16 {
+			for z := range 16 {
+				for y := range 16 {
+					index := a.index(baseX+x, baseY+y, baseZ+z)
+					a.filtering[index], a.emission[index] = 0, 0
+				}
+			}
+		}
+
+		for _, storage := range sub.storages {
+			classifications := make([]block.Classification, len(storage.palette.values))
+			for j, runtimeID := range storage.palette.values {
+				classifications[j], _ = block.Classify(runtimeID)
+			}
+
+			for x := range byte(16) {
+				for z := range byte(16) {
+					for y := range byte(16) {
+						cl := classifications[storage.paletteIndex(x, y, z)]
+						index := a.index(baseX+int(x), baseY+int(y), baseZ+int(z))
+						a.filtering[index] = max(a.filtering[index], cl.LightFiltering)
+						a.emission[index] = max(a.emission[index], cl.LightEmission)
+					}
+				}
+			}
+		}
+	}
+}
+
+// store stores the light computed into the sub chunks of c,
+// whose offset (in chunks) in the area is dx and dz.
+func (a *lightArea) store(c *Chunk, dx, dz int32) {
+	baseX, baseZ := int(dx)<<4, int(dz)<<4
+	for i, sub := range c.sub {
+		baseY := i << 4
+		sub.skyLight, sub.blockLight = make([]uint8, 2048), make([]uint8, 2048)
+
+		for x := range byte(16) {
+			for z := range byte(16) {
+				for y := range byte(16) {
+					index := a.index(baseX+int(x), baseY+int(y), baseZ+int(z))
+					setNibble(sub.skyLight, x, y, z, a.skyLight[index])
+					setNibble(sub.blockLight, x, y, z, a.blockLight[index])
+				}
+			}
+		}
+	}
+}
+
+// computeSkyLight computes the sky light of the area. The sky light goes straight down from the top
+// of each column until it is fully filtered, and then spreads to all directions.
+func (a *lightArea) computeSkyLight() {
+	queue := make([]int, 0, len(a.skyLight)/4)
+	for x := range lightAreaBlocks {
+		for z := range lightAreaBlocks {
+			level := uint8(maxLightLevel)
+			for y := a.height - 1; y >= 0 && level > 0; y-- {
+				index := a.index(x, y, z)
+				if a.filtering[index] == lightMissing {
+					break
+				}
+				level -= min(level, a.filtering[index])
+				a.skyLight[index] = level
+				if level > 0 {
+					queue = append(queue, index)
+				}
+			}
+		}
+	}
+	a.spread(a.skyLight, queue)
+}
+
+// computeBlockLight computes the block light of the area, which
+// is emitted by blocks and then spreads to all directions.
+func (a *lightArea) computeBlockLight() {
+	queue := make([]int, 0)
+	for index, emission := range a.emission {
+		if emission > 0 && a.filtering[index] != lightMissing {
+			a.blockLight[index] = emission
+			queue = append(queue, index)
+		}
+	}
+	a.spread(a.blockLight, queue)
+}
+
+// spread spreads the light in levels from the positions in queue by breadth-first
+// search. Every step takes at least 1 level, or the light filtering of the target
+// block if it is higher.
+func (a *lightArea) spread(levels []uint8, queue []int) {
+	stride := [3]int{lightAreaBlocks * a.height, a.height, 1}
+	bound := [3]int{lightAreaBlocks, lightAreaBlocks, a.height}
+
+	for len(queue) > 0 {
+		index := queue[0]
+		queue = queue[1:]
+
+		level := levels[index]
+		if level <= 1 {
+			continue
+		}
+		position := [3]int{index / stride[0], index / stride[1] % lightAreaBlocks, index % a.height}
+
+		for axis := range 3 {
+			for _, delta := range [2]int{-1, 1} {
+				if p := position[axis] + delta; p < 0 || p >= bound[axis] {
+					continue
+				}
+				next := index + delta*stride[axis]
+				filtering := a.filtering[next]
+				if filtering == lightMissing {
+					continue
+				}
+				newLevel := level - min(level, max(1, filtering))
+				if newLevel > levels[next] {
+					levels[next] = newLevel
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+}
+
+// nibbleAt returns the 4 bits value at the given X, Y and Z in
+// nibbles. 0 is returned if nibbles is nil.
+func nibbleAt(nibbles []uint8, x, y, z byte) uint8 {
+	if nibbles == nil {
+		return 0
+	}
+	index := uint16(x&15)<<8 | uint16(z&15)<<4 | uint16(y&15)
+	return nibbles[index>>1] >> ((index & 1) << 2) & 0xF
+}
+
+// setNibble sets the 4 bits value at the given X, Y and Z in nibbles.
+func setNibble(nibbles []uint8, x, y, z byte, value uint8) {
+	index := uint16(x&15)<<8 | uint16(z&15)<<4 | uint16(y&15)
+	shift := (index & 1) << 2
+	nibbles[index>>1] = nibbles[index>>1]&^(0xF<<shift) | (value&0xF)<<shift
+}
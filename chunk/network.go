@@ -0,0 +1,175 @@
+package chunk
+
+import (
+	"bytes"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// NetworkEncode encodes the chunk passed to the payload of a LevelChunk packet, which is used when the
+// sub chunk request system is not used. The payload holds the sub chunks up to the highest filled one,
+// the biomes of all sub chunks, the border blocks (always empty) and the block entities passed, which
+// are encoded in network little endian NBT.
+//
+// subChunkCount is the count of the sub chunks in payload, and it must be set as the SubChunkCount field
+// of the LevelChunk packet. NetworkDecode could decode the payload with this count.
+func NetworkEncode(c *Chunk, blockEntities []map[string]any) (payload []byte, subChunkCount uint32) {
+	buf := bytes.NewBuffer(nil)
+
+	subChunkCount = uint32(c.HighestFilledSubChunk()) + 1
+	for i := range subChunkCount {
+		_, _ = buf.Write(EncodeSubChunk(c.sub[i], c.r, int(i), NetworkEncoding))
+	}
+	_, _ = buf.Write(EncodeBiomes(c, NetworkEncoding))
+	// Border blocks, which is only used by Education Edition.
+	_ = buf.WriteByte(0)
+	encodeNetworkBlockEntities(buf, blockEntities)
+
+	return buf.Bytes(), subChunkCount
+}
+
+// NetworkEncodeRequestMode encodes the chunk passed to the payload of a LevelChunk packet, which is used
+// when the sub chunk request system is used (the SubChunkCount field of the LevelChunk packet is set to
+// protocol.SubChunkRequestModeLimited or protocol.SubChunkRequestModeLimitless).
+//
+// The payload only holds the biomes and the border blocks, and the sub chunks (with their block entities)
+// are sent later by the SubChunk packet, which could be encoded by NetworkEncodeSubChunk.
+func NetworkEncodeRequestMode(c *Chunk) []byte {
+	biomes := EncodeBiomes(c, NetworkEncoding)
+	// Border blocks, which is only used by Education Edition.
+	return append(biomes, 0)
+}
+
+// NetworkEncodeSubChunk encodes the sub chunk of c whose Y index is index to an entry of the SubChunk
+// packet, which is used to answer the SubChunkRequest packet sent by the client.
+//
+// blockEntities holds the block entities of the whole chunk, and only the ones whose Y position is in
+// the sub chunk are encoded into the payload of the entry. The heightmaps of the entry are computed from
+// the blocks of c. The Offset and BlobHash fields of the entry are not set, and they should be set by the
+// caller.
+func NetworkEncodeSubChunk(c *Chunk, index int, blockEntities []map[string]any) protocol.SubChunkEntry {
+	entry, subChunk, blockEntityPayload := encodeSubChunkEntry(c, index, blockEntities)
+	if entry.Result == protocol.SubChunkResultSuccess {
+		entry.RawPayload = append(subChunk, blockEntityPayload...)
+	}
+	return entry
+}
+
+// encodeSubChunkEntry encodes the sub chunk of c whose Y index is index to an entry of the SubChunk packet,
+// but the network encoded sub chunk and its block entities are returned separately, and the RawPayload field
+// of the entry is not set. They are both nil if the result of the entry is not protocol.SubChunkResultSuccess.
+func encodeSubChunkEntry(c *Chunk, index int, blockEntities []map[string]any) (entry protocol.SubChunkEntry, subChunk []byte, blockEntityPayload []byte) {
+	if index < 0 || index >= len(c.sub) {
+		entry = protocol.SubChunkEntry{
+			Result:              protocol.SubChunkResultIndexOutOfBounds,
+			HeightMapType:       protocol.HeightMapDataNone,
+			RenderHeightMapType: protocol.HeightMapDataNone,
+		}
+		return entry, nil, nil
+	}
+
+	entry = protocol.SubChunkEntry{Result: protocol.SubChunkResultSuccess}
+	// The heightmap is used for lighting, and the render heightmap holds the highest non-air blocks.
+	entry.HeightMapType, entry.HeightMapData = subChunkHeightMap(c, index, func(runtimeID uint32) bool {
+		cl, _ := block.Classify(runtimeID)
+		return cl.LightFiltering > 0
+	})
+	entry.RenderHeightMapType, entry.RenderHeightMapData = subChunkHeightMap(c, index, func(runtimeID uint32) bool {
+		return runtimeID != c.air
+	})
+
+	minY, maxY := int32(c.SubY(int16(index))), int32(c.SubY(int16(index)))+15
+	subBlockEntities := make([]map[string]any, 0)
+	for _, blockEntity := range blockEntities {
+		if y, ok := blockEntityY(blockEntity); ok && y >= minY && y <= maxY {
+			subBlockEntities = append(subBlockEntities, blockEntity)
+		}
+	}
+
+	if c.sub[index].Empty() && len(subBlockEntities) == 0 {
+		entry.Result = protocol.SubChunkResultSuccessAllAir
+		return entry, nil, nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	encodeNetworkBlockEntities(buf, subBlockEntities)
+
+	return entry, EncodeSubChunk(c.sub[index], c.r, index, NetworkEncoding), buf.Bytes()
+}
+
+// subChunkHeightMap computes the heightmap of the sub chunk of c whose Y index is index. The heightmap holds
+// the Y position (relative to the sub chunk) of the highest block in each column that matches counted, which
+// is indexed by (z<<4)|x. It is -1 or 16 if the highest block is below or above the sub chunk.
+func subChunkHeightMap(c *Chunk, index int, counted func(runtimeID uint32) bool) (heightMapType byte, heightMap []int8) {
+	var higher, lower int
+	heightMap = make([]int8, 256)
+	minY := c.SubY(int16(index))
+
+	for x := range uint8(16) {
+		for z := range uint8(16) {
+			y, found := highestCountedBlock(c, x, z, counted)
+			i := uint16(z)<<4 | uint16(x)
+			switch {
+			case !found || y < minY:
+				heightMap[i] = -1
+				lower++
+			case y > minY+15:
+				heightMap[i] = 16
+				higher++
+			default:
+				heightMap[i] = int8(y - minY)
+			}
+		}
+	}
+
+	switch {
+	case lower == 256:
+		return protocol.HeightMapDataTooLow, nil
+	case higher == 256:
+		return protocol.HeightMapDataTooHigh, nil
+	}
+	return protocol.HeightMapDataHasData, heightMap
+}
+
+// highestCountedBlock finds the Y value of the highest block at an x and z that matches counted,
+// and blocks of all layers are checked. found is false if no such blocks are present in the column.
+func highestCountedBlock(c *Chunk, x, z uint8, counted func(runtimeID uint32) bool) (y int16, found bool) {
+	for index := int16(len(c.sub) - 1); index >= 0; index-- {
+		if sub := c.sub[index]; !sub.Empty() {
+			for y := 15; y >= 0; y-- {
+				for _, storage := range sub.storages {
+					if counted(storage.At(x, uint8(y), z)) {
+						return int16(y) | c.SubY(index), true
+					}
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// blockEntityY returns the Y position of the block entity passed.
+func blockEntityY(blockEntity map[string]any) (y int32, ok bool) {
+	switch v := blockEntity["y"].(type) {
+	case int32:
+		return v, true
+	case int16:
+		return int32(v), true
+	case int64:
+		return int32(v), true
+	case uint8:
+		return int32(v), true
+	}
+	return 0, false
+}
+
+// encodeNetworkBlockEntities encodes the block entities passed
+// into buf in network little endian NBT one after another.
+func encodeNetworkBlockEntities(buf *bytes.Buffer, blockEntities []map[string]any) {
+	enc := nbt.NewEncoderWithEncoding(buf, nbt.NetworkLittleEndian)
+	for _, blockEntity := range blockEntities {
+		_ = enc.Encode(blockEntity)
+	}
+}
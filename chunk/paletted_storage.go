@@ -1,7 +1,9 @@
 package chunk
 
 import (
-	"bytes"
+	"fmt"
+	"math"
+	"slices"
 	"unsafe"
 )
 
@@ -54,11 +56,122 @@ func newPalettedStorage(indices []uint32, palette *Palette) *PalettedStorage {
 	return &PalettedStorage{filledBitsPerIndex: filledBitsPerIndex, indexMask: indexMask, indicesStart: indicesStart, bitsPerIndex: bitsPerIndex, indices: indices, palette: palette}
 }
 
+// NewPalettedStorageFromIndices creates a PalettedStorage from the values of its Palette and the palette index of
+// every position, which is the reverse of PalettedStorage.Indices. The indices are packed directly instead of setting
+// the values one by one, and palette is used as the values of the Palette without being copied.
+//
+// An error is returned if palette is empty or holds more than 65536 values, if len(indices) is not 4096 or if any
+// index is not lower than len(palette).
+func NewPalettedStorageFromIndices(palette []uint32, indices []uint16) (*PalettedStorage, error) {
+	if len(palette) == 0 || len(palette) > 1<<16 {
+		return nil, fmt.Errorf("NewPalettedStorageFromIndices: palette must hold 1 to 65536 values, but got %d", len(palette))
+	}
+	if len(indices) != 4096 {
+		return nil, fmt.Errorf("NewPalettedStorageFromIndices: expected 4096 indices, but got %d", len(indices))
+	}
+
+	size := paletteSizeFor(len(palette))
+	storage := newPalettedStorage(make([]uint32, size.uint32s()), NewPalette(size, palette))
+	indicesPerUint32 := 32 / max(int(size), 1)
+	for offset, index := range indices {
+		if int(index) >= len(palette) {
+			return nil, fmt.Errorf("NewPalettedStorageFromIndices: index %d at offset %d is out of the palette of %d values", index, offset, len(palette))
+		}
+		if size != 0 {
+			storage.indices[offset/indicesPerUint32] |= uint32(index) << (uint32(offset%indicesPerUint32) * uint32(size))
+		}
+	}
+	return storage, nil
+}
+
+// storageFromValues creates a PalettedStorage holding values, whose length must be 4096 and whose order is
+// values[(x<<8)|(y<<4)|z]. The Palette holds the values in the order they first appear.
+func storageFromValues(values []uint32) *PalettedStorage {
+	var (
+		palette = make([]uint32, 0, 16)
+		lookup  = make(map[uint32]uint16, 16)
+		indices = make([]uint16, 4096)
+		last    = values[0]
+		index   = uint16(0)
+	)
+	palette, lookup[last] = append(palette, last), 0
+
+	for i, v := range values[:4096] {
+		if v != last {
+			var ok bool
+			if index, ok = lookup[v]; !ok {
+				index = uint16(len(palette))
+				palette, lookup[v] = append(palette, v), index
+			}
+			last = v
+		}
+		indices[transposeYZ(i)] = index
+	}
+	// The palette holds at most 4096 values and every index points into it, so this never fails.
+	storage, _ := NewPalettedStorageFromIndices(palette, indices)
+	return storage
+}
+
+// transposeYZ swaps the Y and Z of the offset (x<<8)|(y<<4)|z, or the other way round. It converts an offset
+// in the order of the values that Blocks and Biomes return to the order of the indices in a PalettedStorage.
+func transposeYZ(offset int) int {
+	return offset&0xf00 | (offset&0xf)<<4 | (offset>>4)&0xf
+}
+
 // emptyStorage creates a PalettedStorage filled completely with a value v.
 func emptyStorage(v uint32) *PalettedStorage {
 	return newPalettedStorage([]uint32{}, NewPalette(0, []uint32{v}))
 }
 
+// Clone returns a deep copy of the PalettedStorage, which holds a copy of the indices and the Palette.
+func (storage *PalettedStorage) Clone() *PalettedStorage {
+	return newPalettedStorage(slices.Clone(storage.indices), storage.palette.Clone())
+}
+
+// Indices returns the values in the Palette of the PalettedStorage and the palette index of every position, so
+// that the value at x, y and z is palette[indices[(x<<8)|(z<<4)|y]]. The indices are unpacked word by word
+// instead of calling At for every position.
+//
+// palette is not a copy of the values in the Palette, so it must not be modified while the PalettedStorage is
+// still in use.
+func (storage *PalettedStorage) Indices() (palette []uint32, indices []uint16) {
+	indices = make([]uint16, 4096)
+	if storage.bitsPerIndex == 0 {
+		return storage.palette.values, indices
+	}
+
+	offset := 0
+	for _, w := range storage.indices {
+		for bitOffset := uint16(0); bitOffset < storage.filledBitsPerIndex && offset < 4096; bitOffset += storage.bitsPerIndex {
+			indices[offset] = uint16((w >> bitOffset) & storage.indexMask)
+			offset++
+		}
+	}
+	return storage.palette.values, indices
+}
+
+// Values returns the value at every position, whose order is values[(x<<8)|(y<<4)|z]. The values are looked up
+// while unpacking the indices word by word, instead of calling At for every position.
+func (storage *PalettedStorage) Values() (values []uint32) {
+	values = make([]uint32, 4096)
+	if storage.bitsPerIndex == 0 {
+		v := storage.palette.values[0]
+		for i := range values {
+			values[i] = v
+		}
+		return values
+	}
+
+	palette, offset := storage.palette.values, 0
+	for _, w := range storage.indices {
+		for bitOffset := uint16(0); bitOffset < storage.filledBitsPerIndex && offset < 4096; bitOffset += storage.bitsPerIndex {
+			values[transposeYZ(offset)] = palette[(w>>bitOffset)&storage.indexMask]
+			offset++
+		}
+	}
+	return values
+}
+
 // Palette returns the Palette of the PalettedStorage.
 func (storage *PalettedStorage) Palette() *Palette {
 	return storage.palette
@@ -81,23 +194,88 @@ func (storage *PalettedStorage) Set(x, y, z byte, v uint32) {
 	storage.setPaletteIndex(x&15, y&15, z&15, uint16(index))
 }
 
-// Equal checks if two PalettedStorages are equal value wise. False is returned
-// if either of the storages are nil.
+// Equal checks if two PalettedStorages are equal value wise, which means the values at every position are
+// the same, even if the palettes hold them in different orders. False is returned if either of the storages
+// are nil.
 func (storage *PalettedStorage) Equal(other *PalettedStorage) bool {
+	if storage == nil || other == nil {
+		return false
+	}
+	if storage.bitsPerIndex == other.bitsPerIndex && slices.Equal(storage.indices, other.indices) && slices.Equal(storage.palette.values, other.palette.values) {
+		// Fast path for the storages with exactly the same layout.
+		return true
+	}
+	for x := range byte(16) {
+		for y := range byte(16) {
+			for z := range byte(16) {
+				if storage.At(x, y, z) != other.At(x, y, z) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// sameLayout checks if two PalettedStorages are encoded into the same bytes, which means they hold the same
+// indices and the same palette. Unlike Equal, false is returned for the storages that are empty or filled
+// with a single value, as well as the ones whose palette starts with 0. It is used by the encoder to decide
+// if a storage could be written as a reference to the previous one.
+func (storage *PalettedStorage) sameLayout(other *PalettedStorage) bool {
 	if storage == nil || other == nil {
 		return false
 	}
 	if len(storage.indices) == 0 || len(other.indices) == 0 || storage.palette.values[0] == 0 || other.palette.values[0] == 0 {
 		return false
 	}
-	indicesA := unsafe.Slice((*byte)(unsafe.Pointer(&storage.indices[0])), len(storage.indices)*4)
-	indicesB := unsafe.Slice((*byte)(unsafe.Pointer(&other.indices[0])), len(other.indices)*4)
-	if !bytes.Equal(indicesA, indicesB) {
+	return storage.bitsPerIndex == other.bitsPerIndex && slices.Equal(storage.indices, other.indices) && slices.Equal(storage.palette.values, other.palette.values)
+}
+
+// filledWith checks if all the values of the PalettedStorage are v.
+func (storage *PalettedStorage) filledWith(v uint32) bool {
+	if !slices.Contains(storage.palette.values, v) {
 		return false
 	}
-	paletteA := unsafe.Slice((*byte)(unsafe.Pointer(&storage.palette.values[0])), len(storage.palette.values)*4)
-	paletteB := unsafe.Slice((*byte)(unsafe.Pointer(&other.palette.values[0])), len(other.palette.values)*4)
-	return bytes.Equal(paletteA, paletteB)
+	if len(storage.palette.values) == 1 {
+		return true
+	}
+	for x := range byte(16) {
+		for y := range byte(16) {
+			for z := range byte(16) {
+				if storage.At(x, y, z) != v {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Counts returns the amount of every value in the PalettedStorage. It walks over the indices and
+// counts the palette indices directly, so the 4096 values are never looked up one by one.
+func (storage *PalettedStorage) Counts() map[uint32]uint32 {
+	indexCounts := make([]uint32, max(len(storage.palette.values), 1<<storage.bitsPerIndex))
+	if storage.bitsPerIndex == 0 {
+		indexCounts[0] = 4096
+	} else {
+		// The padded storages have an additional uint32 at the end, which
+		// is not fully used, so we stop after 4096 indices are counted.
+		remaining := 4096
+		for _, w := range storage.indices {
+			for bitOffset := uint16(0); bitOffset < storage.filledBitsPerIndex && remaining > 0; bitOffset += storage.bitsPerIndex {
+				indexCounts[(w>>bitOffset)&storage.indexMask]++
+				remaining--
+			}
+		}
+	}
+
+	result := make(map[uint32]uint32, len(storage.palette.values))
+	for index, v := range storage.palette.values {
+		if indexCounts[index] > 0 {
+			result[v] += indexCounts[index]
+		}
+	}
+	return result
 }
 
 // addNew adds a new value to the PalettedStorage's Palette and returns its index. If needed, the storage is resized.
@@ -139,6 +317,26 @@ func (storage *PalettedStorage) setPaletteIndex(x, y, z byte, i uint16) {
 	*ptr = (*ptr &^ (storage.indexMask << bitOffset)) | (uint32(i) << bitOffset)
 }
 
+// replace replaces the values of the PalettedStorage by the function passed, which is called once for each value
+// in the Palette. The PalettedStorage is compacted if any value is changed, and changed is true in this case.
+func (storage *PalettedStorage) replace(f func(v uint32) uint32) (changed bool) {
+	newValues := make([]uint32, len(storage.palette.values))
+	for index, v := range storage.palette.values {
+		newValues[index] = f(v)
+		changed = changed || newValues[index] != v
+	}
+	if !changed {
+		return false
+	}
+
+	// Reset last runtime ID as it now has a different offset.
+	storage.palette.last = math.MaxUint32
+	storage.palette.values = newValues
+	storage.compact()
+
+	return true
+}
+
 // resize changes the size of a PalettedStorage to newPaletteSize. A new PalettedStorage is constructed,
 // and all values available in the current storage are set in their appropriate locations in the
 // new storage.
@@ -162,7 +360,7 @@ func (storage *PalettedStorage) resize(newPaletteSize paletteSize) {
 
 // compact clears unused indexes in the palette by scanning for usages in the PalettedStorage. This is a
 // relatively heavy task which should only happen right before the sub chunk holding this PalettedStorage is
-// saved to disk. compact also shrinks the palette size if possible.
+// saved to disk. compact also merges the duplicate values in the palette and shrinks the palette size if possible.
 func (storage *PalettedStorage) compact() {
 	usedIndices := make([]bool, storage.palette.Len())
 	for x := byte(0); x < 16; x++ {
@@ -173,13 +371,23 @@ func (storage *PalettedStorage) compact() {
 		}
 	}
 	newRuntimeIDs := make([]uint32, 0, len(usedIndices))
+	newIndices := make(map[uint32]uint16, len(usedIndices))
 	conversion := make([]uint16, len(usedIndices))
 
 	for index, set := range usedIndices {
-		if set {
-			conversion[index] = uint16(len(newRuntimeIDs))
-			newRuntimeIDs = append(newRuntimeIDs, storage.palette.values[index])
+		if !set {
+			continue
+		}
+		// The palette could hold the same value more than once (e.g. after replace),
+		// and all of them are pointed to the same new index.
+		v := storage.palette.values[index]
+		newIndex, ok := newIndices[v]
+		if !ok {
+			newIndex = uint16(len(newRuntimeIDs))
+			newIndices[v] = newIndex
+			newRuntimeIDs = append(newRuntimeIDs, v)
 		}
+		conversion[index] = newIndex
 	}
 	// Construct a new storage and set all values in there manually. We can't easily do this in a better
 	// way, because all values will be at a different index with a different length.
@@ -0,0 +1,166 @@
+package chunk
+
+import (
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// testValues returns 4096 values in the order of (x<<8)|(y<<4)|z, which hold n different values
+// in a pattern that does not follow either order of the indices.
+func testValues(n int) []uint32 {
+	values := make([]uint32, 4096)
+	for i := range values {
+		values[i] = uint32(i*7%n) + 1000
+	}
+	return values
+}
+
+func TestNewPalettedStorageFromIndices(t *testing.T) {
+	// The sizes cover every bit size of a PalettedStorage, including the ones with padding.
+	for _, n := range []int{1, 2, 3, 5, 9, 17, 33, 65, 300, 4096} {
+		palette, indices := make([]uint32, n), make([]uint16, 4096)
+		for i := range palette {
+			palette[i] = uint32(i) * 3
+		}
+		for i := range indices {
+			indices[i] = uint16(i * 7 % n)
+		}
+
+		storage, err := NewPalettedStorageFromIndices(palette, indices)
+		if err != nil {
+			t.Fatalf("palette of %d values: %v", n, err)
+		}
+		gotPalette, gotIndices := storage.Indices()
+		for i, index := range indices {
+			if gotPalette[gotIndices[i]] != palette[index] {
+				t.Fatalf("palette of %d values: expected %d at offset %d, but got %d", n, palette[index], i, gotPalette[gotIndices[i]])
+			}
+			if v := storage.At(byte(i>>8), byte(i&15), byte(i>>4&15)); v != palette[index] {
+				t.Fatalf("palette of %d values: expected %d at offset %d from At, but got %d", n, palette[index], i, v)
+			}
+		}
+	}
+}
+
+func TestNewPalettedStorageFromIndicesInvalid(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		palette  []uint32
+		indices  []uint16
+		setIndex int
+	}{
+		{"empty palette", nil, make([]uint16, 4096), -1},
+		{"palette of 65537 values", make([]uint32, 1<<16+1), make([]uint16, 4096), -1},
+		{"too few indices", []uint32{1, 2}, make([]uint16, 4095), -1},
+		{"too many indices", []uint32{1, 2}, make([]uint16, 4097), -1},
+		{"index out of the palette", []uint32{1, 2}, make([]uint16, 4096), 4095},
+		{"non-zero index of a single value palette", []uint32{1}, make([]uint16, 4096), 0},
+	} {
+		if test.setIndex >= 0 {
+			test.indices[test.setIndex] = uint16(len(test.palette))
+		}
+		if _, err := NewPalettedStorageFromIndices(test.palette, test.indices); err == nil {
+			t.Fatalf("%s: expected an error", test.name)
+		}
+	}
+
+	sub := NewSubChunk(block.AirRuntimeID)
+	if err := sub.SetLayerIndices(1, []uint32{1}, make([]uint16, 10)); err == nil {
+		t.Fatal("expected an error from SetLayerIndices")
+	}
+	if len(sub.Layers()) != 0 {
+		t.Fatalf("expected the sub chunk to be unchanged, but it has %d layers", len(sub.Layers()))
+	}
+}
+
+func TestSubChunkBlocksMatchesBlock(t *testing.T) {
+	for _, n := range []int{1, 5, 40, 300} {
+		values := testValues(n)
+		sub := NewSubChunk(block.AirRuntimeID)
+		sub.SetBlocks(0, values)
+		for i, v := range values {
+			if got := sub.Block(byte(i>>8), byte(i>>4&15), byte(i&15), 0); got != v {
+				t.Fatalf("%d values: expected %d at offset %d, but got %d", n, v, i, got)
+			}
+		}
+		got := sub.Blocks(0)
+		for i, v := range values {
+			if got[i] != v {
+				t.Fatalf("%d values: expected %d at offset %d from Blocks, but got %d", n, v, i, got[i])
+			}
+		}
+	}
+}
+
+// The benchmarks below compare the bulk accessors with accessing the blocks and biomes one by one,
+// which is how Blocks, SetBlocks and Biomes were implemented before.
+
+func BenchmarkSubChunkBlocks(b *testing.B) {
+	sub := NewSubChunk(block.AirRuntimeID)
+	sub.SetBlocks(0, testValues(40))
+	for b.Loop() {
+		sub.Blocks(0)
+	}
+}
+
+func BenchmarkSubChunkBlocksOneByOne(b *testing.B) {
+	sub := NewSubChunk(block.AirRuntimeID)
+	sub.SetBlocks(0, testValues(40))
+	for b.Loop() {
+		result := make([]uint32, 4096)
+		for i := range result {
+			result[i] = sub.Block(byte(i>>8), byte(i>>4&15), byte(i&15), 0)
+		}
+	}
+}
+
+func BenchmarkSubChunkSetBlocks(b *testing.B) {
+	values := testValues(40)
+	for b.Loop() {
+		NewSubChunk(block.AirRuntimeID).SetBlocks(0, values)
+	}
+}
+
+func BenchmarkSubChunkSetBlocksOneByOne(b *testing.B) {
+	values := testValues(40)
+	for b.Loop() {
+		sub := NewSubChunk(block.AirRuntimeID)
+		for i, v := range values {
+			sub.SetBlock(byte(i>>8), byte(i>>4&15), byte(i&15), 0, v)
+		}
+	}
+}
+
+// benchmarkChunk returns an overworld chunk, whose biomes of every sub chunk hold 5 different values.
+func benchmarkChunk() *Chunk {
+	c := NewChunk(block.AirRuntimeID, define.Dimension(define.DimensionIDOverworld).Range())
+	biomes := make([][]uint32, len(c.Sub()))
+	for i := range biomes {
+		biomes[i] = testValues(5)
+	}
+	c.SetBiomes(biomes)
+	return c
+}
+
+func BenchmarkChunkBiomes(b *testing.B) {
+	c := benchmarkChunk()
+	for b.Loop() {
+		c.Biomes()
+	}
+}
+
+func BenchmarkChunkBiomesOneByOne(b *testing.B) {
+	c := benchmarkChunk()
+	r := c.Range()
+	for b.Loop() {
+		result := make([][]uint32, len(c.Sub()))
+		for i := range result {
+			result[i] = make([]uint32, 4096)
+			for j := range result[i] {
+				result[i][j] = c.Biome(uint8(j>>8), int16(r[0]+i<<4+j>>4&15), uint8(j&15))
+			}
+		}
+	}
+}
@@ -1,10 +1,21 @@
 package chunk
 
+import (
+	"fmt"
+	"slices"
+)
+
 // SubChunk is a cube of blocks located in a chunk. It has a size of 16x16x16 blocks and forms part of a stack
 // that forms a Chunk.
 type SubChunk struct {
 	air      uint32
 	storages []*PalettedStorage
+
+	// skyLight and blockLight hold the light levels of each block, which are 4 bits
+	// per block and in the same order as the blocks. They are nil until the light is
+	// computed by ComputeLight, and they are not updated when the blocks are changed.
+	skyLight   []uint8
+	blockLight []uint8
 }
 
 // NewSubChunk creates a new sub chunk. All sub chunks should be created through this function
@@ -12,14 +23,24 @@ func NewSubChunk(air uint32) *SubChunk {
 	return &SubChunk{air: air}
 }
 
-// Equals returns if the sub chunk passed is equal to the current one.
+// Equals returns if the sub chunk passed is equal to the current one. The sub chunks are compared block by
+// block, so the ones with different palette orders or different counts of air layers could also be equal.
 func (sub *SubChunk) Equals(s *SubChunk) bool {
-	if s.air != sub.air || len(s.storages) != len(sub.storages) {
+	if s.air != sub.air {
 		return false
 	}
 
-	for i, st := range s.storages {
-		if !st.Equal(sub.storages[i]) {
+	for i := range max(len(s.storages), len(sub.storages)) {
+		switch {
+		case i >= len(s.storages):
+			if !sub.storages[i].filledWith(sub.air) {
+				return false
+			}
+		case i >= len(sub.storages):
+			if !s.storages[i].filledWith(s.air) {
+				return false
+			}
+		case !s.storages[i].Equal(sub.storages[i]):
 			return false
 		}
 	}
@@ -27,6 +48,20 @@ func (sub *SubChunk) Equals(s *SubChunk) bool {
 	return true
 }
 
+// Clone returns a deep copy of the sub chunk, including the block storages and the light computed.
+func (sub *SubChunk) Clone() *SubChunk {
+	storages := make([]*PalettedStorage, len(sub.storages))
+	for i, storage := range sub.storages {
+		storages[i] = storage.Clone()
+	}
+	return &SubChunk{
+		air:        sub.air,
+		storages:   storages,
+		skyLight:   slices.Clone(sub.skyLight),
+		blockLight: slices.Clone(sub.blockLight),
+	}
+}
+
 // Empty checks if the SubChunk is considered empty. This is the case if the SubChunk has 0 block storages or if it has
 // a single one that is completely filled with air.
 func (sub *SubChunk) Empty() bool {
@@ -60,30 +95,47 @@ func (sub *SubChunk) Block(x, y, z byte, layer uint8) uint32 {
 
 // Blocks returns all blocks (block runtime ids) whose in layer.
 // If layer is not exist, then return blocks with all air.
-// Note that the length of returned slice is 4096 (16*16*16).
+// Note that the length of returned slice is 4096 (16*16*16),
+// and the order of it is blocks[(x<<8)|(y<<4)|z].
 func (sub *SubChunk) Blocks(layer uint8) []uint32 {
-	result := make([]uint32, 16*16*16)
-
 	if uint8(len(sub.storages)) <= layer {
+		result := make([]uint32, 16*16*16)
 		for i := range result {
 			result[i] = sub.air
 		}
 		return result
 	}
+	return sub.storages[layer].Values()
+}
 
-	currentLayer := sub.Layer(layer)
+// LayerIndices returns the palette and the palette indices of layer. See PalettedStorage.Indices for
+// more information. If layer is not exist, then palette only holds air and all the indices are 0.
+func (sub *SubChunk) LayerIndices(layer uint8) (palette []uint32, indices []uint16) {
+	if uint8(len(sub.storages)) <= layer {
+		return []uint32{sub.air}, make([]uint16, 16*16*16)
+	}
+	return sub.storages[layer].Indices()
+}
 
-	i := 0
-	for x := range byte(16) {
-		for y := range byte(16) {
-			for z := range byte(16) {
-				result[i] = currentLayer.At(x, y, z)
-				i++
-			}
-		}
+// SetLayerIndices replaces layer by the storage built from palette and indices directly. See
+// NewPalettedStorageFromIndices for more information. The sub chunk is not changed if an error is returned.
+func (sub *SubChunk) SetLayerIndices(layer uint8, palette []uint32, indices []uint16) error {
+	storage, err := NewPalettedStorageFromIndices(palette, indices)
+	if err != nil {
+		return fmt.Errorf("SetLayerIndices: %v", err)
 	}
+	sub.Layer(layer)
+	sub.storages[layer] = storage
+	return nil
+}
 
-	return result
+// BlockCounts returns the amount of every block (block runtime id) in layer. If layer is not exist,
+// then all the 4096 blocks are counted as air. See PalettedStorage.Counts for more information.
+func (sub *SubChunk) BlockCounts(layer uint8) map[uint32]uint32 {
+	if uint8(len(sub.storages)) <= layer {
+		return map[uint32]uint32{sub.air: 4096}
+	}
+	return sub.storages[layer].Counts()
 }
 
 // SetBlock sets the given block runtime ID at the given X, Y and Z. X, Y and Z must be in a range of 0-15.
@@ -92,32 +144,52 @@ func (sub *SubChunk) SetBlock(x, y, z byte, layer uint8, block uint32) {
 }
 
 // SetBlock sets the whole sub chunk in layer by given block runtime ids.
-// len(blocks) must equal to 4096 (16*16*16).
+// len(blocks) must equal to 4096 (16*16*16), and the order of it is
+// the same as the one that Blocks returns. The layer is rebuilt as a
+// whole instead of setting the blocks one by one.
 func (sub *SubChunk) SetBlocks(layer uint8, blocks []uint32) {
-	currentLayer := sub.Layer(layer)
-
-	i := 0
-	for x := range byte(16) {
-		for y := range byte(16) {
-			for z := range byte(16) {
-				currentLayer.Set(x, y, z, blocks[i])
-				i++
-			}
+	sub.Layer(layer)
+	sub.storages[layer] = storageFromValues(blocks)
+}
+
+// ReplaceBlocks replaces the blocks in layer by mapping, whose keys are the runtime IDs of the blocks to replace
+// and values are the runtime IDs of the new blocks. It operates on the palette directly instead of setting every
+// block, and the layer is compacted afterwards. changed is true if any block is replaced.
+func (sub *SubChunk) ReplaceBlocks(layer uint8, mapping map[uint32]uint32) (changed bool) {
+	return sub.ReplaceFunc(layer, func(runtimeID uint32) uint32 {
+		if newRuntimeID, ok := mapping[runtimeID]; ok {
+			return newRuntimeID
 		}
+		return runtimeID
+	})
+}
+
+// ReplaceFunc replaces the blocks in layer by f, which is called once for each different block in the layer and
+// returns the runtime ID of the new block. It operates on the palette directly instead of setting every block, and
+// the layer is compacted afterwards. If the layer is not exist, it is only created when f replaces air.
+// changed is true if any block is replaced.
+func (sub *SubChunk) ReplaceFunc(layer uint8, f func(runtimeID uint32) uint32) (changed bool) {
+	if uint8(len(sub.storages)) <= layer && f(sub.air) == sub.air {
+		return false
 	}
+	return sub.Layer(layer).replace(f)
 }
 
 // Compact cleans the garbage from all block storages that sub chunk contains, so that they may be
-// cleanly written to a database.
+// cleanly written to a database. Only the empty storages at the end are removed, so the index of every
+// layer is kept, e.g. the water of waterlogged blocks in LiquidLayer is never shifted to BlockLayer.
 func (sub *SubChunk) compact() {
-	newStorages := make([]*PalettedStorage, 0, len(sub.storages))
 	for _, storage := range sub.storages {
 		storage.compact()
-		if len(storage.palette.values) == 1 && storage.palette.values[0] == sub.air {
-			// If the palette has only air in it, it means the storage is empty, so we can ignore it.
-			continue
+	}
+
+	n := len(sub.storages)
+	for ; n > 0; n-- {
+		// If the palette has only air in it, it means the storage is empty, so we can ignore it.
+		storage := sub.storages[n-1]
+		if len(storage.palette.values) != 1 || storage.palette.values[0] != sub.air {
+			break
 		}
-		newStorages = append(newStorages, storage)
 	}
-	sub.storages = newStorages
+	sub.storages = sub.storages[:n]
 }
@@ -0,0 +1,35 @@
+package define
+
+// BlockChange is a change of a single block in a chunk, which is
+// usually recorded from the UpdateBlock packet.
+type BlockChange struct {
+	// X, Y and Z is the position of the block relative to the chunk.
+	// X and Z must be in a range of 0-15, and Y is the block Y position.
+	X uint8
+	Y int16
+	Z uint8
+	// Layer is the layer of the block, which is 0 or 1 in most cases.
+	Layer uint8
+	// BlockRuntimeID is the runtime ID of the new block.
+	BlockRuntimeID uint32
+}
+
+// NBTChange is a change of the block entity (NBT) of a block in a chunk.
+type NBTChange struct {
+	// X, Y and Z is the position of the block relative to the chunk.
+	// X and Z must be in a range of 0-15, and Y is the block Y position.
+	X uint8
+	Y int16
+	Z uint8
+	// NBT is the new block entity data of the block.
+	// If it is nil, then the block entity is removed.
+	NBT map[string]any
+}
+
+// DeltaUpdate holds a list of changes of a chunk that not yet applied to
+// it. The changes are applied in order, so the latter one wins when there
+// are multiple changes in the same position.
+type DeltaUpdate struct {
+	BlockChanges []BlockChange
+	NBTChanges   []NBTChange
+}
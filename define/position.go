@@ -25,6 +25,21 @@ func (p ChunkPos) Z() int32 {
 	return p[1]
 }
 
+// ChunkPosArea returns the positions of all the chunks in the area from start to end (both inclusive).
+// The positions are sorted by X, and then by Z.
+func ChunkPosArea(start, end ChunkPos) []ChunkPos {
+	minX, maxX := min(start[0], end[0]), max(start[0], end[0])
+	minZ, maxZ := min(start[1], end[1]), max(start[1], end[1])
+
+	result := make([]ChunkPos, 0, int(maxX-minX+1)*int(maxZ-minZ+1))
+	for x := minX; x <= maxX; x++ {
+		for z := minZ; z <= maxZ; z++ {
+			result = append(result, ChunkPos{x, z})
+		}
+	}
+	return result
+}
+
 // SubChunkPos holds the position of a sub-chunk. The type is provided as a utility struct for keeping track of a
 // sub-chunk's position. Sub-chunks do not themselves keep track of that. Sub-chunk positions are different from
 // block positions in the way that increasing the X/Y/Z by one means increasing the absolute value on the X/Y/Z axis in
@@ -50,3 +65,41 @@ func (p SubChunkPos) Y() int32 {
 func (p SubChunkPos) Z() int32 {
 	return p[2]
 }
+
+// BlockPos holds the position of a block, which is an array with an x, y and z value.
+type BlockPos [3]int32
+
+// String implements fmt.Stringer and returns (x, y, z).
+func (p BlockPos) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", p[0], p[1], p[2])
+}
+
+// X returns the X coordinate of the block position.
+func (p BlockPos) X() int32 {
+	return p[0]
+}
+
+// Y returns the Y coordinate of the block position.
+func (p BlockPos) Y() int32 {
+	return p[1]
+}
+
+// Z returns the Z coordinate of the block position.
+func (p BlockPos) Z() int32 {
+	return p[2]
+}
+
+// Add returns the block position that p is moved by q.
+func (p BlockPos) Add(q BlockPos) BlockPos {
+	return BlockPos{p[0] + q[0], p[1] + q[1], p[2] + q[2]}
+}
+
+// Sub returns the block position that p is moved by -q.
+func (p BlockPos) Sub(q BlockPos) BlockPos {
+	return BlockPos{p[0] - q[0], p[1] - q[1], p[2] - q[2]}
+}
+
+// ChunkPos returns the position of the chunk that the block is in.
+func (p BlockPos) ChunkPos() ChunkPos {
+	return ChunkPos{p[0] >> 4, p[2] >> 4}
+}
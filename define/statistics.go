@@ -0,0 +1,11 @@
+package define
+
+// BlockStatistics holds the amount of every block and biome in some chunks.
+type BlockStatistics struct {
+	// Chunks is the count of the chunks that are counted.
+	Chunks int
+	// Blocks maps the block runtime IDs to their amounts.
+	Blocks map[uint32]uint64
+	// Biomes maps the biome IDs to the amounts of the blocks in them.
+	Biomes map[uint32]uint64
+}
@@ -0,0 +1,24 @@
+package define
+
+// Rotation is a clockwise rotation around the Y axis when viewed from above,
+// which rotates north to east, east to south, and so on.
+type Rotation uint8
+
+const (
+	Rotation0 Rotation = iota
+	Rotation90
+	Rotation180
+	Rotation270
+)
+
+// Mirror is a mirroring in the horizontal plane.
+type Mirror uint8
+
+const (
+	// MirrorNone means not mirroring.
+	MirrorNone Mirror = iota
+	// MirrorX flips the X axis, so east and west are swapped.
+	MirrorX
+	// MirrorZ flips the Z axis, so north and south are swapped.
+	MirrorZ
+)
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world/leveldat"
+)
+
+// worldVersionPost118 is the world_version of the superflat layers that start from the bottom of the
+// overworld (-64), which is used by the flat worlds created since 1.18. The layers of the older ones
+// start from 0.
+const worldVersionPost118 = "version.post_1_18"
+
+// FlatLayer is a layer of a superflat world.
+type FlatLayer struct {
+	// Block is the runtime ID of the block that fills the layer.
+	Block uint32
+	// Count is the count of the block rows of the layer.
+	Count int
+}
+
+// flatWorldLayers is the FlatWorldLayers of level.dat, which is a JSON string, e.g.
+//
+//	{
+//	  "biome_id": 1,
+//	  "block_layers": [
+//	    {"block_name": "minecraft:bedrock", "count": 1},
+//	    {"block_name": "minecraft:dirt", "count": 2},
+//	    {"block_name": "minecraft:grass_block", "count": 1}
+//	  ],
+//	  "encoding_version": 6,
+//	  "structure_options": null,
+//	  "world_version": "version.post_1_18"
+//	}
+//
+// The layers are from the bottom to the top. block_data is the legacy metadata of the block, which is
+// only used by the older versions, e.g. {"block_data": 14, "block_name": "minecraft:wool", "count": 1}.
+type flatWorldLayers struct {
+	BiomeID     *uint32 `json:"biome_id"`
+	BlockLayers []struct {
+		BlockName string `json:"block_name"`
+		BlockData uint16 `json:"block_data"`
+		Count     int    `json:"count"`
+	} `json:"block_layers"`
+	WorldVersion string `json:"world_version"`
+}
+
+// Flat is a Generator that generates superflat chunks, whose terrain is the same layers of blocks
+// everywhere. Only the chunks in the overworld hold the layers, and the ones in the nether and the
+// end are generated as the ones of Void.
+type Flat struct {
+	template *chunk.Chunk
+	biome    uint32
+}
+
+// NewFlat creates a new Flat generator, whose layers are from the bottom to the top, and the lowest
+// one starts from bottom. The layers that are out of the range of the overworld are cut, and the
+// chunks in the overworld are filled with biome.
+func NewFlat(layers []FlatLayer, bottom int16, biome uint32) *Flat {
+	r := define.Dimension(define.DimensionIDOverworld).Range()
+	template := newChunk(define.DimensionIDOverworld, biome)
+
+	y := int(bottom)
+	for _, layer := range layers {
+		for range layer.Count {
+			if y >= r[0] && y <= r[1] && layer.Block != block.AirRuntimeID {
+				for x := range uint8(16) {
+					for z := range uint8(16) {
+						template.SetBlock(x, int16(y), z, 0, layer.Block)
+					}
+				}
+			}
+			y++
+		}
+	}
+	template.Compact()
+
+	return &Flat{template: template, biome: biome}
+}
+
+// NewFlatFromLevelDat creates a new Flat generator from the FlatWorldLayers of d, which is the superflat
+// preset used by Minecraft. The default layers (a bedrock, two dirts and a grass block in plains) are used
+// if it is empty. The blocks are found by their names, and the ones of the older versions (e.g. the ones
+// with block_data) are upgraded. The biome of the preset is overridden by the BiomeOverride of d if it is
+// set.
+func NewFlatFromLevelDat(d *leveldat.Data) (*Flat, error) {
+	preset := d.FlatWorldLayers
+	if preset == "" {
+		preset = `{"biome_id":1,"block_layers":[{"block_name":"minecraft:bedrock","count":1},{"block_name":"minecraft:dirt","count":2},{"block_name":"minecraft:grass_block","count":1}],"encoding_version":6,"structure_options":null,"world_version":"version.post_1_18"}`
+	}
+
+	var src flatWorldLayers
+	if err := json.Unmarshal([]byte(preset), &src); err != nil {
+		return nil, fmt.Errorf("NewFlatFromLevelDat: FlatWorldLayers: %v", err)
+	}
+
+	layers := make([]FlatLayer, len(src.BlockLayers))
+	for i, layer := range src.BlockLayers {
+		if layer.Count < 0 {
+			return nil, fmt.Errorf("NewFlatFromLevelDat: layer %d has negative count %d", i, layer.Count)
+		}
+		runtimeID, found := flatLayerBlock(layer.BlockName, layer.BlockData)
+		if !found {
+			return nil, fmt.Errorf("NewFlatFromLevelDat: unknown block %#v with data %d in layer %d", layer.BlockName, layer.BlockData, i)
+		}
+		layers[i] = FlatLayer{Block: runtimeID, Count: layer.Count}
+	}
+
+	var bottom int16
+	if src.WorldVersion == worldVersionPost118 {
+		bottom = int16(define.Dimension(define.DimensionIDOverworld).Range()[0])
+	}
+
+	biome := biomePlains
+	if src.BiomeID != nil {
+		biome = *src.BiomeID
+	}
+	if d.BiomeOverride != "" {
+		biome = overworldBiome(d)
+	}
+
+	return NewFlat(layers, bottom, biome), nil
+}
+
+// Generate generates the chunk at position in dm dimension.
+func (f *Flat) Generate(dm define.Dimension, position define.ChunkPos) *chunk.Chunk {
+	if dm != define.DimensionIDOverworld {
+		return newChunk(dm, dimensionBiome(dm, f.biome))
+	}
+	return f.template.Clone()
+}
+
+// flatLayerBlock returns the runtime ID of the block of a superflat layer. The block is found by
+// its current name first, and then by its legacy name and data.
+func flatLayerBlock(name string, data uint16) (runtimeID uint32, found bool) {
+	if data == 0 {
+		if runtimeID, found = block.StateToRuntimeID(name, nil); found {
+			return
+		}
+	}
+	return block.LegacyNameToRuntimeID(name, data)
+}
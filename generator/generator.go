@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/biome"
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world"
+	"github.com/TriM-Organization/bedrock-world-operator/world/leveldat"
+)
+
+// The generator types that are stored as the Generator of level.dat.
+const (
+	TypeLegacy int32 = iota
+	TypeOverworld
+	TypeFlat
+	TypeNether
+	TypeEnd
+	TypeVoid
+)
+
+// The numeric IDs of the biomes that the chunks are filled with by default.
+const (
+	biomePlains uint32 = 1
+	biomeHell   uint32 = 8
+	biomeTheEnd uint32 = 9
+)
+
+// Generator generates the terrain of the chunks that are not exist in a world.
+type Generator interface {
+	// Generate generates the chunk at position in dm dimension. The range of the returned chunk
+	// is the same as the one of dm. Generate must be safe to be called by multiple goroutines.
+	Generate(dm define.Dimension, position define.ChunkPos) *chunk.Chunk
+}
+
+// FromLevelDat returns the generator that is described by the Generator of d, which is a Terrain
+// generator seeded by the RandomSeed of d for TypeOverworld and TypeLegacy, a Flat generator for
+// TypeFlat and a Void generator for TypeVoid. An error is returned for the other generator types,
+// or if the superflat layers of d are not valid.
+func FromLevelDat(d *leveldat.Data) (Generator, error) {
+	switch d.Generator {
+	case TypeLegacy, TypeOverworld:
+		return NewTerrain(d.RandomSeed), nil
+	case TypeFlat:
+		g, err := NewFlatFromLevelDat(d)
+		if err != nil {
+			return nil, fmt.Errorf("FromLevelDat: %v", err)
+		}
+		return g, nil
+	case TypeVoid:
+		return NewVoid(overworldBiome(d)), nil
+	}
+	return nil, fmt.Errorf("FromLevelDat: generator type %d is not supported", d.Generator)
+}
+
+// Pregenerate generates the chunks from (center.X-radius, center.Z-radius) to (center.X+radius,
+// center.Z+radius) in dm dimension of w by g, and saves them to w. The chunks that are already exist
+// are skipped, so it could be used to fill the missing chunks around the spawn of an existing world.
+// generated is the count of the chunks that are generated.
+func Pregenerate(w world.World, g Generator, dm define.Dimension, center define.ChunkPos, radius int32) (generated int, err error) {
+	if radius < 0 {
+		return 0, fmt.Errorf("Pregenerate: radius must not be negative, but got %d", radius)
+	}
+
+	for x := center[0] - radius; x <= center[0]+radius; x++ {
+		for z := center[1] - radius; z <= center[1]+radius; z++ {
+			position := define.ChunkPos{x, z}
+			_, exists, err := w.LoadChunkPayloadOnly(dm, position)
+			if err != nil {
+				return generated, fmt.Errorf("Pregenerate: %v", err)
+			}
+			if exists {
+				continue
+			}
+
+			if err = w.SaveChunk(dm, position, g.Generate(dm, position)); err != nil {
+				return generated, fmt.Errorf("Pregenerate: %v", err)
+			}
+			generated++
+		}
+	}
+	return generated, nil
+}
+
+// newChunk returns an empty chunk of dm dimension, whose biomes are all biomeID.
+func newChunk(dm define.Dimension, biomeID uint32) *chunk.Chunk {
+	c := chunk.NewChunk(block.AirRuntimeID, dm.Range())
+	fillBiome(c, biomeID)
+	return c
+}
+
+// fillBiome sets all the biomes of c to biomeID.
+func fillBiome(c *chunk.Chunk, biomeID uint32) {
+	values := make([]uint32, 4096)
+	for i := range values {
+		values[i] = biomeID
+	}
+	biomes := make([][]uint32, len(c.Sub()))
+	for i := range biomes {
+		biomes[i] = values
+	}
+	c.SetBiomes(biomes)
+}
+
+// dimensionBiome returns the biome of dm dimension, where overworld is the one
+// that is used in the overworld.
+func dimensionBiome(dm define.Dimension, overworld uint32) uint32 {
+	switch dm {
+	case define.DimensionIDNether:
+		return biomeHell
+	case define.DimensionIDEnd:
+		return biomeTheEnd
+	}
+	return overworld
+}
+
+// overworldBiome returns the biome of the overworld that is set by the BiomeOverride of d,
+// or plains if it is empty or not a registered biome.
+func overworldBiome(d *leveldat.Data) uint32 {
+	if id, found := biome.ID(d.BiomeOverride); found && d.BiomeOverride != "" {
+		return id
+	}
+	return biomePlains
+}
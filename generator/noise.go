@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// perlin is the improved Perlin noise, whose permutation table and offsets are decided by a seed.
+type perlin struct {
+	perm                      [512]uint8
+	offsetX, offsetY, offsetZ float64
+}
+
+// newPerlin creates a new perlin noise, whose permutation table is shuffled by r.
+func newPerlin(r *rand.Rand) *perlin {
+	p := &perlin{
+		offsetX: r.Float64() * 256,
+		offsetY: r.Float64() * 256,
+		offsetZ: r.Float64() * 256,
+	}
+	for i := range 256 {
+		p.perm[i] = uint8(i)
+	}
+	r.Shuffle(256, func(i, j int) {
+		p.perm[i], p.perm[j] = p.perm[j], p.perm[i]
+	})
+	copy(p.perm[256:], p.perm[:256])
+	return p
+}
+
+// sample returns the noise at x, y and z, which is in [-1, 1].
+func (p *perlin) sample(x, y, z float64) float64 {
+	x, y, z = x+p.offsetX, y+p.offsetY, z+p.offsetZ
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	a := int(p.perm[xi]) + yi
+	aa, ab := int(p.perm[a])+zi, int(p.perm[a+1])+zi
+	b := int(p.perm[xi+1]) + yi
+	ba, bb := int(p.perm[b])+zi, int(p.perm[b+1])+zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p.perm[aa], x, y, z), grad(p.perm[ba], x-1, y, z)),
+			lerp(u, grad(p.perm[ab], x, y-1, z), grad(p.perm[bb], x-1, y-1, z)),
+		),
+		lerp(v,
+			lerp(u, grad(p.perm[aa+1], x, y, z-1), grad(p.perm[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p.perm[ab+1], x, y-1, z-1), grad(p.perm[bb+1], x-1, y-1, z-1)),
+		),
+	)
+}
+
+// octaves is the sum of several perlin noises, where each of them has the double frequency
+// and the half amplitude of the previous one, which makes the noise more detailed.
+type octaves []*perlin
+
+// newOctaves creates n octaves, whose noises are decided by r.
+func newOctaves(r *rand.Rand, n int) octaves {
+	result := make(octaves, n)
+	for i := range result {
+		result[i] = newPerlin(r)
+	}
+	return result
+}
+
+// sample2 returns the noise at x and z, which is in [-1, 1].
+func (o octaves) sample2(x, z float64) float64 {
+	return o.sample3(x, 0, z)
+}
+
+// sample3 returns the noise at x, y and z, which is in [-1, 1].
+func (o octaves) sample3(x, y, z float64) float64 {
+	var result, amplitude, total float64 = 0, 1, 0
+	for _, p := range o {
+		result += p.sample(x, y, z) * amplitude
+		total += amplitude
+		x, y, z, amplitude = x*2, y*2, z*2, amplitude/2
+	}
+	return result / total
+}
+
+// fade is the curve 6t^5-15t^4+10t^3 of the improved Perlin noise.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp interpolates a and b linearly by t.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of x, y, z and one of the 12 gradients of the
+// edges of a cube, which is chosen by hash.
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
@@ -0,0 +1,519 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// seaLevel is the Y of the surface of the seas generated by Terrain.
+const seaLevel = 62
+
+// The numeric IDs of the biomes that Terrain assigns.
+const (
+	biomeOcean           uint32 = 0
+	biomeDesert          uint32 = 2
+	biomeExtremeHills    uint32 = 3
+	biomeForest          uint32 = 4
+	biomeTaiga           uint32 = 5
+	biomeSwampland       uint32 = 6
+	biomeIcePlains       uint32 = 12
+	biomeBeach           uint32 = 16
+	biomeJungle          uint32 = 21
+	biomeDeepOcean       uint32 = 24
+	biomeColdBeach       uint32 = 26
+	biomeBirchForest     uint32 = 27
+	biomeColdTaiga       uint32 = 30
+	biomeSavanna         uint32 = 35
+	biomeFrozenOcean     uint32 = 46
+	biomeDeepFrozenOcean uint32 = 47
+	biomeFrozenPeaks     uint32 = 183
+	biomeStonyPeaks      uint32 = 189
+)
+
+// noBlock is used by oreVein if the vein is not placed in stone or deepslate.
+const noBlock = math.MaxUint32
+
+// The blocks that Terrain generates, which are found by their names when the package is loaded.
+var (
+	blockStone        = mustRuntimeID("minecraft:stone")
+	blockDeepslate    = mustRuntimeID("minecraft:deepslate")
+	blockBedrock      = mustRuntimeID("minecraft:bedrock")
+	blockGrass        = mustRuntimeID("minecraft:grass_block")
+	blockDirt         = mustRuntimeID("minecraft:dirt")
+	blockSand         = mustRuntimeID("minecraft:sand")
+	blockGravel       = mustRuntimeID("minecraft:gravel")
+	blockSnow         = mustRuntimeID("minecraft:snow")
+	blockSnowLayer    = mustRuntimeID("minecraft:snow_layer")
+	blockIce          = mustRuntimeID("minecraft:ice")
+	blockLava         = mustRuntimeID("minecraft:lava")
+	blockOakLog       = mustRuntimeID("minecraft:oak_log")
+	blockOakLeaves    = mustRuntimeID("minecraft:oak_leaves")
+	blockBirchLog     = mustRuntimeID("minecraft:birch_log")
+	blockBirchLeaves  = mustRuntimeID("minecraft:birch_leaves")
+	blockSpruceLog    = mustRuntimeID("minecraft:spruce_log")
+	blockSpruceLeaves = mustRuntimeID("minecraft:spruce_leaves")
+	blockCactus       = mustRuntimeID("minecraft:cactus")
+	blockShortGrass   = mustRuntimeID("minecraft:short_grass")
+	blockSandstone    = mustRuntimeID("minecraft:sandstone")
+)
+
+// oreVein describes the veins of a kind of ore, or the blobs of a kind of stone, e.g. granite.
+type oreVein struct {
+	// onStone and onDeepslate are the blocks that replace stone and deepslate,
+	// which are noBlock if the vein is not placed in them.
+	onStone, onDeepslate uint32
+	// count is the count of the veins in a chunk, and size is the count of the blocks of a vein.
+	count, size int
+	// minY and maxY are the range of the Y that the veins start from.
+	minY, maxY int
+}
+
+// oreVeins are the veins placed by Terrain, which are placed in order.
+var oreVeins = []oreVein{
+	{onStone: mustRuntimeID("minecraft:granite"), onDeepslate: noBlock, count: 2, size: 40, minY: 0, maxY: 120},
+	{onStone: mustRuntimeID("minecraft:diorite"), onDeepslate: noBlock, count: 2, size: 40, minY: 0, maxY: 120},
+	{onStone: mustRuntimeID("minecraft:andesite"), onDeepslate: noBlock, count: 2, size: 40, minY: 0, maxY: 120},
+	{onStone: noBlock, onDeepslate: mustRuntimeID("minecraft:tuff"), count: 2, size: 40, minY: -64, maxY: 0},
+	{onStone: blockDirt, onDeepslate: noBlock, count: 3, size: 24, minY: 0, maxY: 160},
+	{onStone: blockGravel, onDeepslate: blockGravel, count: 3, size: 24, minY: -64, maxY: 160},
+	{onStone: mustRuntimeID("minecraft:coal_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_coal_ore"), count: 16, size: 12, minY: 0, maxY: 192},
+	{onStone: mustRuntimeID("minecraft:copper_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_copper_ore"), count: 8, size: 8, minY: -16, maxY: 112},
+	{onStone: mustRuntimeID("minecraft:iron_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_iron_ore"), count: 10, size: 7, minY: -64, maxY: 72},
+	{onStone: mustRuntimeID("minecraft:gold_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_gold_ore"), count: 3, size: 7, minY: -64, maxY: 32},
+	{onStone: mustRuntimeID("minecraft:redstone_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_redstone_ore"), count: 5, size: 6, minY: -64, maxY: 16},
+	{onStone: mustRuntimeID("minecraft:lapis_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_lapis_ore"), count: 2, size: 5, minY: -64, maxY: 32},
+	{onStone: mustRuntimeID("minecraft:diamond_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_diamond_ore"), count: 2, size: 4, minY: -64, maxY: 16},
+	{onStone: mustRuntimeID("minecraft:emerald_ore"), onDeepslate: mustRuntimeID("minecraft:deepslate_emerald_ore"), count: 1, size: 1, minY: -16, maxY: 240},
+}
+
+// Terrain is a Generator that generates the terrain of the overworld by noises, which holds the seas, the
+// plains and the mountains, the biomes decided by the climate and the height, the caves and the ores, and
+// the trees. The chunks are only decided by the seed and their positions, so the same world is generated
+// for the same seed no matter the order the chunks are generated in. It does not match the terrain of
+// vanilla, but it is plausible enough to test the servers and the tools with the worlds that look real.
+//
+// Only the chunks in the overworld are generated by noises, and the ones in the nether and the end are
+// generated as the ones of Void.
+type Terrain struct {
+	seed                        int64
+	continent, mountain, detail octaves
+	temperature, humidity       octaves
+	caveX, caveY, cavern        octaves
+}
+
+// NewTerrain creates a new Terrain generator, whose terrain is decided by seed. The seed is the RandomSeed
+// of level.dat for the worlds opened by the world package, see FromLevelDat.
+func NewTerrain(seed int64) *Terrain {
+	r := rand.New(rand.NewPCG(uint64(seed), 0x7465727261696e))
+	return &Terrain{
+		seed:        seed,
+		continent:   newOctaves(r, 5),
+		mountain:    newOctaves(r, 4),
+		detail:      newOctaves(r, 3),
+		temperature: newOctaves(r, 3),
+		humidity:    newOctaves(r, 3),
+		caveX:       newOctaves(r, 2),
+		caveY:       newOctaves(r, 2),
+		cavern:      newOctaves(r, 3),
+	}
+}
+
+// column is the terrain of a block column.
+type column struct {
+	// height is the Y of the highest block of the ground.
+	height int
+	// biome is the numeric ID of the biome of the column.
+	biome uint32
+}
+
+// chunkBlocks holds the blocks of a chunk that is being generated, which is
+// in the same order as the ones returned by chunk.Chunk.Blocks.
+type chunkBlocks struct {
+	sub    [][]uint32
+	bottom int
+}
+
+// at returns the pointer to the block at x, y and z, which are relative to the chunk except y.
+func (b chunkBlocks) at(x, y, z int) *uint32 {
+	y -= b.bottom
+	return &b.sub[y>>4][x<<8|(y&15)<<4|z]
+}
+
+// Generate generates the chunk at position in dm dimension.
+func (t *Terrain) Generate(dm define.Dimension, position define.ChunkPos) *chunk.Chunk {
+	if dm != define.DimensionIDOverworld {
+		return newChunk(dm, dimensionBiome(dm, biomePlains))
+	}
+
+	r := dm.Range()
+	blocks := chunkBlocks{sub: make([][]uint32, (r.Height()>>4)+1), bottom: r[0]}
+	for i := range blocks.sub {
+		blocks.sub[i] = make([]uint32, 4096)
+		for j := range blocks.sub[i] {
+			blocks.sub[i][j] = block.AirRuntimeID
+		}
+	}
+
+	var columns [16][16]column
+	biomes := make([]uint32, 4096)
+	baseX, baseZ := int(position[0])<<4, int(position[1])<<4
+	for x := range 16 {
+		for z := range 16 {
+			col := t.column(baseX+x, baseZ+z)
+			columns[x][z] = col
+			for y := range 16 {
+				biomes[x<<8|y<<4|z] = col.biome
+			}
+			t.fillColumn(blocks, x, z, baseX+x, baseZ+z, col)
+			t.carveCaves(blocks, x, z, baseX+x, baseZ+z, col)
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(t.seed), uint64(position[0])<<32|uint64(uint32(position[1]))))
+	placeOres(blocks, rng)
+	decorate(blocks, &columns, rng)
+
+	c := chunk.NewChunk(block.AirRuntimeID, r)
+	c.SetBlocks(0, blocks.sub)
+	subBiomes := make([][]uint32, len(blocks.sub))
+	for i := range subBiomes {
+		subBiomes[i] = biomes
+	}
+	c.SetBiomes(subBiomes)
+	c.Compact()
+	return c
+}
+
+// column returns the terrain of the block column at x and z.
+func (t *Terrain) column(x, z int) column {
+	fx, fz := float64(x), float64(z)
+
+	// The continents decide where the seas are, and the mountains only rise up on the lands.
+	continent := clamp(t.continent.sample2(fx/1024, fz/1024)*2.2, -1, 1)
+	mountain := max(t.mountain.sample2(fx/384, fz/384)*2-0.15, 0)
+	mountain *= mountain * 180 * clamp(continent*4, 0, 1)
+	detail := t.detail.sample2(fx/64, fz/64) * 6
+
+	height := seaLevel + 5 + continent*48 + mountain + detail
+	height = clamp(height, float64(define.Dimension(define.DimensionIDOverworld).Range()[0]+16), 300)
+
+	// The higher the column is, the colder it is.
+	temperature := t.temperature.sample2(fx/1536, fz/1536)*2.2 - max(height-110, 0)/80
+	humidity := t.humidity.sample2(fx/1536, fz/1536) * 2.2
+
+	return column{height: int(height), biome: biomeOf(int(height), temperature, humidity)}
+}
+
+// biomeOf returns the biome of a column by its height and its climate.
+func biomeOf(height int, temperature, humidity float64) uint32 {
+	cold := temperature < -0.4
+	switch {
+	case height < seaLevel-14:
+		return pick(cold, biomeDeepFrozenOcean, biomeDeepOcean)
+	case height < seaLevel:
+		return pick(cold, biomeFrozenOcean, biomeOcean)
+	case height >= 170:
+		return pick(temperature < 0, biomeFrozenPeaks, biomeStonyPeaks)
+	case height >= 115:
+		return biomeExtremeHills
+	case height <= seaLevel+2 && !(temperature > 0.4 && humidity < -0.1):
+		return pick(cold, biomeColdBeach, biomeBeach)
+	case temperature > 0.4:
+		if humidity < -0.1 {
+			return biomeDesert
+		}
+		return pick(humidity > 0.3, biomeJungle, biomeSavanna)
+	case cold:
+		return pick(humidity > 0, biomeColdTaiga, biomeIcePlains)
+	case humidity > 0.35 && height <= seaLevel+6:
+		return biomeSwampland
+	case humidity > 0.15:
+		return pick(temperature < 0, biomeTaiga, biomeForest)
+	case humidity > 0 && temperature > 0.1:
+		return biomeBirchForest
+	}
+	return biomePlains
+}
+
+// fillColumn fills the block column at x and z with the ground and the water, where x and z are relative
+// to the chunk, and worldX and worldZ are the ones in the world.
+func (t *Terrain) fillColumn(blocks chunkBlocks, x, z, worldX, worldZ int, col column) {
+	top, filler, depth := surfaceOf(col)
+	for y := blocks.bottom; y <= col.height; y++ {
+		b := blockStone
+		switch {
+		case y-blocks.bottom < 5 && int(t.hash(worldX, y, worldZ)%5) >= y-blocks.bottom:
+			b = blockBedrock
+		case y < 0 || (y < 8 && int(t.hash(worldX, y, worldZ)%8) >= y):
+			b = blockDeepslate
+		case y == col.height:
+			b = top
+		case y > col.height-depth:
+			b = filler
+		case col.biome == biomeDesert && y > col.height-depth-3:
+			b = blockSandstone
+		}
+		*blocks.at(x, y, z) = b
+	}
+
+	frozen := isFrozen(col.biome)
+	for y := col.height + 1; y <= seaLevel; y++ {
+		*blocks.at(x, y, z) = block.WaterRuntimeID
+		if y == seaLevel && frozen {
+			*blocks.at(x, y, z) = blockIce
+		}
+	}
+	if frozen && col.height >= seaLevel && top == blockGrass {
+		*blocks.at(x, col.height+1, z) = blockSnowLayer
+	}
+}
+
+// surfaceOf returns the blocks of the surface of col, which are the top block, and the filler
+// blocks under it. depth is the count of the top block and the filler blocks.
+func surfaceOf(col column) (top, filler uint32, depth int) {
+	switch {
+	case col.height < seaLevel-14:
+		return blockGravel, blockGravel, 3
+	case col.height < seaLevel:
+		return blockSand, blockSand, 3
+	}
+	switch col.biome {
+	case biomeDesert, biomeBeach, biomeColdBeach:
+		return blockSand, blockSand, 4
+	case biomeStonyPeaks:
+		return blockStone, blockStone, 1
+	case biomeFrozenPeaks:
+		return blockSnow, blockSnow, 2
+	case biomeExtremeHills:
+		if col.height >= 140 {
+			return blockStone, blockStone, 1
+		}
+	}
+	return blockGrass, blockDirt, 4
+}
+
+// carveCaves carves the caves in the block column at x and z, where x and z are relative to the chunk, and
+// worldX and worldZ are the ones in the world. The caves are the tunnels where two noises are both close to
+// 0, and the caverns where another noise is high. The caves lower than 10 blocks above the bottom are
+// filled with lava.
+func (t *Terrain) carveCaves(blocks chunkBlocks, x, z, worldX, worldZ int, col column) {
+	// Keep the surface and the floor of the seas, so that the caves are not opened to the
+	// ground, and the water does not flow into them.
+	top := col.height - 5
+	if col.height < seaLevel {
+		top -= 3
+	}
+	fx, fz := float64(worldX), float64(worldZ)
+	for y := blocks.bottom + 5; y <= top; y++ {
+		fy := float64(y)
+		a := t.caveX.sample3(fx/72, fy/48, fz/72)
+		b := t.caveY.sample3(fx/72, fy/48, fz/72)
+		tunnel := a*a+b*b < 0.0018
+		cavern := y < col.height-12 && t.cavern.sample3(fx/128, fy/64, fz/128) > 0.3
+		if !tunnel && !cavern {
+			continue
+		}
+
+		p := blocks.at(x, y, z)
+		if *p == blockBedrock {
+			continue
+		}
+		*p = block.AirRuntimeID
+		if y < blocks.bottom+10 {
+			*p = blockLava
+		}
+	}
+}
+
+// placeOres places the veins of oreVeins into the stone and the deepslate of blocks.
+// Each vein is a random walk from a random position, which is cut by the border of
+// the chunk.
+func placeOres(blocks chunkBlocks, rng *rand.Rand) {
+	for _, vein := range oreVeins {
+		for range vein.count {
+			x, y, z := rng.IntN(16), vein.minY+rng.IntN(vein.maxY-vein.minY+1), rng.IntN(16)
+			for range vein.size {
+				if x >= 0 && x < 16 && z >= 0 && z < 16 && y >= blocks.bottom && y < blocks.bottom+len(blocks.sub)<<4 {
+					p := blocks.at(x, y, z)
+					switch {
+					case *p == blockStone && vein.onStone != noBlock:
+						*p = vein.onStone
+					case *p == blockDeepslate && vein.onDeepslate != noBlock:
+						*p = vein.onDeepslate
+					}
+				}
+				switch rng.IntN(3) {
+				case 0:
+					x += rng.IntN(3) - 1
+				case 1:
+					y += rng.IntN(3) - 1
+				default:
+					z += rng.IntN(3) - 1
+				}
+			}
+		}
+	}
+}
+
+// decorate places the trees, the cacti and the short grass on the surface of blocks. The trees
+// are kept away from the border of the chunk, so that they are never cut by it.
+func decorate(blocks chunkBlocks, columns *[16][16]column, rng *rand.Rand) {
+	for range 10 {
+		x, z := 2+rng.IntN(12), 2+rng.IntN(12)
+		col := columns[x][z]
+		ground := *blocks.at(x, col.height, z)
+		if col.height < seaLevel || col.height+12 >= blocks.bottom+len(blocks.sub)<<4 {
+			continue
+		}
+		above := *blocks.at(x, col.height+1, z)
+		if above != block.AirRuntimeID && above != blockSnowLayer {
+			continue
+		}
+
+		switch {
+		case ground == blockSand && col.biome == biomeDesert:
+			if rng.IntN(4) == 0 {
+				for y := range 1 + rng.IntN(3) {
+					*blocks.at(x, col.height+1+y, z) = blockCactus
+				}
+			}
+		case ground != blockGrass:
+		case col.biome == biomeTaiga || col.biome == biomeColdTaiga:
+			if rng.IntN(10) < 7 {
+				spruceTree(blocks, x, col.height+1, z, rng)
+			}
+		case col.biome == biomeBirchForest:
+			if rng.IntN(10) < 8 {
+				oakTree(blocks, x, col.height+1, z, blockBirchLog, blockBirchLeaves, rng)
+			}
+		case col.biome == biomeForest || col.biome == biomeJungle:
+			if rng.IntN(10) < 8 {
+				oakTree(blocks, x, col.height+1, z, blockOakLog, blockOakLeaves, rng)
+			}
+		case col.biome == biomeSwampland || col.biome == biomeSavanna || col.biome == biomeExtremeHills:
+			if rng.IntN(10) < 2 {
+				oakTree(blocks, x, col.height+1, z, blockOakLog, blockOakLeaves, rng)
+			}
+		case col.biome == biomePlains:
+			if rng.IntN(20) == 0 {
+				oakTree(blocks, x, col.height+1, z, blockOakLog, blockOakLeaves, rng)
+			}
+		}
+	}
+
+	for x := range 16 {
+		for z := range 16 {
+			col := columns[x][z]
+			if col.height < seaLevel || col.height+1 >= blocks.bottom+len(blocks.sub)<<4 || rng.IntN(8) != 0 {
+				continue
+			}
+			if *blocks.at(x, col.height, z) == blockGrass && *blocks.at(x, col.height+1, z) == block.AirRuntimeID {
+				*blocks.at(x, col.height+1, z) = blockShortGrass
+			}
+		}
+	}
+}
+
+// oakTree places a tree like an oak, whose trunk starts from x, y and z.
+func oakTree(blocks chunkBlocks, x, y, z int, log, leaves uint32, rng *rand.Rand) {
+	height := 4 + rng.IntN(3)
+	top := y + height - 1
+	for dy := -2; dy <= 1; dy++ {
+		radius := 2
+		if dy >= 0 {
+			radius = 1
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				corner := abs(dx) == radius && abs(dz) == radius
+				if corner && (dy == 1 || rng.IntN(2) == 0) {
+					continue
+				}
+				if p := blocks.at(x+dx, top+dy, z+dz); *p == block.AirRuntimeID {
+					*p = leaves
+				}
+			}
+		}
+	}
+	for dy := range height {
+		*blocks.at(x, y+dy, z) = log
+	}
+}
+
+// spruceTree places a spruce tree, whose trunk starts from x, y and z.
+func spruceTree(blocks chunkBlocks, x, y, z int, rng *rand.Rand) {
+	height := 6 + rng.IntN(3)
+	top := y + height - 1
+	radius := 0
+	for ly := top + 1; ly >= y+2; ly-- {
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				if radius > 0 && abs(dx) == radius && abs(dz) == radius {
+					continue
+				}
+				if p := blocks.at(x+dx, ly, z+dz); *p == block.AirRuntimeID || *p == blockSnowLayer {
+					*p = blockSpruceLeaves
+				}
+			}
+		}
+		if radius++; radius > 2 {
+			radius = 1
+		}
+	}
+	for dy := range height {
+		*blocks.at(x, y+dy, z) = blockSpruceLog
+	}
+}
+
+// hash returns a random number that is only decided by the seed of t and x, y and z.
+func (t *Terrain) hash(x, y, z int) uint64 {
+	h := uint64(t.seed) ^ uint64(x)*0x9e3779b97f4a7c15 ^ uint64(y)*0xbf58476d1ce4e5b9 ^ uint64(z)*0x94d049bb133111eb
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// isFrozen returns whether the water of biome is frozen.
+func isFrozen(biome uint32) bool {
+	switch biome {
+	case biomeFrozenOcean, biomeDeepFrozenOcean, biomeColdBeach, biomeIcePlains, biomeColdTaiga, biomeFrozenPeaks:
+		return true
+	}
+	return false
+}
+
+// mustRuntimeID returns the runtime ID of the default state of the block whose name is name,
+// which panics if the block is not exist.
+func mustRuntimeID(name string) uint32 {
+	runtimeID, found := block.StateToRuntimeID(name, nil)
+	if !found {
+		panic(fmt.Sprintf("generator: block %v is not exist", name))
+	}
+	return runtimeID
+}
+
+// pick returns a if cond is true, or b otherwise.
+func pick(cond bool, a, b uint32) uint32 {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// clamp limits v to [lower, upper].
+func clamp(v, lower, upper float64) float64 {
+	return min(max(v, lower), upper)
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// testPositions are the chunks generated by the tests, which are on both sides of the axes.
+var testPositions = []define.ChunkPos{{0, 0}, {-1, 0}, {0, -1}, {5, -7}, {-12, 33}, {100, 100}}
+
+func TestTerrainDeterministic(t *testing.T) {
+	a, b := NewTerrain(12345), NewTerrain(12345)
+
+	// The chunks of b are generated in the reverse order and simultaneously,
+	// which must not change the chunks that are generated.
+	expected := make([]*chunk.Chunk, len(testPositions))
+	for i, position := range testPositions {
+		expected[i] = a.Generate(define.DimensionIDOverworld, position)
+	}
+	got := make([]*chunk.Chunk, len(testPositions))
+	var wg sync.WaitGroup
+	for i := len(testPositions) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = b.Generate(define.DimensionIDOverworld, testPositions[i])
+		}()
+	}
+	wg.Wait()
+
+	for i, position := range testPositions {
+		if !expected[i].Equals(got[i]) {
+			t.Fatalf("chunk %v generated by the same seed is different", position)
+		}
+		if !expected[i].Equals(a.Generate(define.DimensionIDOverworld, position)) {
+			t.Fatalf("chunk %v is different when it is generated again", position)
+		}
+	}
+}
+
+func TestTerrainSeed(t *testing.T) {
+	a, b := NewTerrain(1), NewTerrain(2)
+	for _, position := range testPositions {
+		if !a.Generate(define.DimensionIDOverworld, position).Equals(b.Generate(define.DimensionIDOverworld, position)) {
+			return
+		}
+	}
+	t.Fatal("the chunks generated by different seeds are all the same")
+}
+
+func TestTerrainColumns(t *testing.T) {
+	g := NewTerrain(42)
+	for _, position := range testPositions {
+		c := g.Generate(define.DimensionIDOverworld, position)
+		r := c.Range()
+		for x := range uint8(16) {
+			for z := range uint8(16) {
+				if c.Block(x, int16(r[0]), z, 0) != blockBedrock {
+					t.Fatalf("expected bedrock at the bottom of (%d, %d) in chunk %v", x, z, position)
+				}
+				// The seas are filled up to seaLevel, so no column is lower than it.
+				if y := c.HighestBlock(x, z); y < seaLevel {
+					t.Fatalf("expected the highest block of (%d, %d) in chunk %v at or above the sea, but got %d", x, z, position, y)
+				}
+			}
+		}
+	}
+
+	for _, dm := range []define.Dimension{define.DimensionIDNether, define.DimensionIDEnd} {
+		c := g.Generate(dm, define.ChunkPos{0, 0})
+		if c.Range() != dm.Range() || !c.Equals(NewVoid(biomePlains).Generate(dm, define.ChunkPos{0, 0})) {
+			t.Fatalf("expected an empty chunk in dimension %v", dm)
+		}
+	}
+}
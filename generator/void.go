@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// Void is a Generator that generates the chunks that only hold air, which is used for the worlds
+// whose terrain are all built by the structures, e.g. the lobbies.
+type Void struct {
+	biome uint32
+}
+
+// NewVoid creates a new Void generator, whose chunks in the overworld are filled with biome.
+// The chunks in the nether and the end are filled with hell and the_end.
+func NewVoid(biome uint32) *Void {
+	return &Void{biome: biome}
+}
+
+// Generate generates the chunk at position in dm dimension.
+func (v *Void) Generate(dm define.Dimension, position define.ChunkPos) *chunk.Chunk {
+	return newChunk(dm, dimensionBiome(dm, v.biome))
+}
@@ -4,6 +4,7 @@ go 1.26
 
 require (
 	github.com/Happy2018new/worldupgrader v1.3.0
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/deatil/go-cryptobin v1.1.1005
 	github.com/df-mc/goleveldb v1.1.9
 	github.com/mitchellh/mapstructure v1.5.0
@@ -0,0 +1,44 @@
+// Package nbtvalue reads the values of the NBT tags that are decoded into any, which
+// is shared by the packages that import the NBT of Java Edition.
+package nbtvalue
+
+import "reflect"
+
+// Int returns the value of an integer tag of any size.
+func Int(v any) (int, bool) {
+	switch value := v.(type) {
+	case byte:
+		return int(value), true
+	case int16:
+		return int(value), true
+	case int32:
+		return int(value), true
+	case int64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+// Bytes returns the content of a TAG_Byte_Array, which is decoded
+// as a byte array of any length when it is decoded into any.
+func Bytes(v any) ([]byte, bool) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Array || value.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	result := make([]byte, value.Len())
+	reflect.Copy(reflect.ValueOf(result), value)
+	return result, true
+}
+
+// Int64s returns the content of a TAG_Long_Array, which is decoded
+// as an int64 array of any length when it is decoded into any.
+func Int64s(v any) ([]int64, bool) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Array || value.Type().Elem().Kind() != reflect.Int64 {
+		return nil, false
+	}
+	result := make([]int64, value.Len())
+	reflect.Copy(reflect.ValueOf(result), value)
+	return result, true
+}
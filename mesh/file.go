@@ -0,0 +1,55 @@
+package mesh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFile writes m to the file at name, whose format is decided by the extension. For ".obj", the
+// materials are written to the MTL file that has the same name but ends with ".mtl". For ".glb", a
+// binary glTF file is written.
+func (m *Mesh) WriteFile(name string) error {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch ext {
+	case ".obj":
+		mtlName := strings.TrimSuffix(name, filepath.Ext(name)) + ".mtl"
+		obj, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		defer obj.Close()
+		mtl, err := os.Create(mtlName)
+		if err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		defer mtl.Close()
+
+		if err = m.WriteOBJ(obj, mtl, filepath.Base(mtlName)); err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		if err = mtl.Close(); err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		if err = obj.Close(); err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+	case ".glb":
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		defer f.Close()
+
+		if err = m.WriteGLB(f); err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+		if err = f.Close(); err != nil {
+			return fmt.Errorf("WriteFile: %v", err)
+		}
+	default:
+		return fmt.Errorf("WriteFile: unsupported file extension %#v, which should be .obj or .glb", ext)
+	}
+	return nil
+}
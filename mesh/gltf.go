@@ -0,0 +1,196 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The constants of glTF 2.0.
+const (
+	glbMagic         = 0x46546c67
+	glbVersion       = 2
+	glbChunkJSON     = 0x4e4f534a
+	glbChunkBIN      = 0x004e4942
+	gltfFloat        = 5126
+	gltfUnsignedInt  = 5125
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+)
+
+// The JSON representation of the parts of glTF 2.0 that are used.
+type (
+	gltfDocument struct {
+		Asset       gltfAsset        `json:"asset"`
+		Scene       int              `json:"scene"`
+		Scenes      []gltfScene      `json:"scenes"`
+		Nodes       []gltfNode       `json:"nodes,omitempty"`
+		Meshes      []gltfMesh       `json:"meshes,omitempty"`
+		Materials   []gltfMaterial   `json:"materials,omitempty"`
+		Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+		BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+		Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+	}
+	gltfAsset struct {
+		Version   string `json:"version"`
+		Generator string `json:"generator"`
+	}
+	gltfScene struct {
+		Nodes []int `json:"nodes,omitempty"`
+	}
+	gltfNode struct {
+		Mesh int `json:"mesh"`
+	}
+	gltfMesh struct {
+		Primitives []gltfPrimitive `json:"primitives"`
+	}
+	gltfPrimitive struct {
+		Attributes map[string]int `json:"attributes"`
+		Indices    int            `json:"indices"`
+		Material   int            `json:"material"`
+	}
+	gltfMaterial struct {
+		Name                 string  `json:"name"`
+		PBRMetallicRoughness gltfPBR `json:"pbrMetallicRoughness"`
+		AlphaMode            string  `json:"alphaMode,omitempty"`
+		DoubleSided          bool    `json:"doubleSided,omitempty"`
+	}
+	gltfPBR struct {
+		BaseColorFactor [4]float64 `json:"baseColorFactor"`
+		MetallicFactor  float64    `json:"metallicFactor"`
+		RoughnessFactor float64    `json:"roughnessFactor"`
+	}
+	gltfAccessor struct {
+		BufferView    int       `json:"bufferView"`
+		ComponentType int       `json:"componentType"`
+		Count         int       `json:"count"`
+		Type          string    `json:"type"`
+		Min           []float32 `json:"min,omitempty"`
+		Max           []float32 `json:"max,omitempty"`
+	}
+	gltfBufferView struct {
+		Buffer     int `json:"buffer"`
+		ByteOffset int `json:"byteOffset"`
+		ByteLength int `json:"byteLength"`
+		Target     int `json:"target"`
+	}
+	gltfBuffer struct {
+		ByteLength int `json:"byteLength"`
+	}
+)
+
+// WriteGLB writes m as a binary glTF 2.0 (.glb) file to w. All the faces are in a mesh, where each
+// material is a primitive. The colors of the materials are the base colors, and the materials
+// that are not opaque use the BLEND alpha mode.
+func (m *Mesh) WriteGLB(w io.Writer) error {
+	doc := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "bedrock-world-operator"},
+		Scenes: []gltfScene{{}},
+	}
+	buf := bytes.NewBuffer(nil)
+
+	// addView appends data to the buffer and adds a buffer view and an accessor of it.
+	addView := func(data any, target int, accessor gltfAccessor) int {
+		offset := buf.Len()
+		_ = binary.Write(buf, binary.LittleEndian, data)
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{ByteOffset: offset, ByteLength: buf.Len() - offset, Target: target})
+		accessor.BufferView = len(doc.BufferViews) - 1
+		doc.Accessors = append(doc.Accessors, accessor)
+		return len(doc.Accessors) - 1
+	}
+
+	var primitives []gltfPrimitive
+	for _, material := range m.Materials {
+		positions := make([]float32, 0, len(material.Quads)*12)
+		normals := make([]float32, 0, len(material.Quads)*12)
+		indices := make([]uint32, 0, len(material.Quads)*6)
+		minPos := []float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+		maxPos := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+
+		for _, quad := range material.Quads {
+			base := uint32(len(positions) / 3)
+			for _, v := range quad.Vertices {
+				positions = append(positions, v[:]...)
+				normals = append(normals, quad.Normal[:]...)
+				for i := range 3 {
+					minPos[i], maxPos[i] = min(minPos[i], v[i]), max(maxPos[i], v[i])
+				}
+			}
+			indices = append(indices, base, base+1, base+2, base, base+2, base+3)
+		}
+
+		count := len(positions) / 3
+		primitives = append(primitives, gltfPrimitive{
+			Attributes: map[string]int{
+				"POSITION": addView(positions, gltfArrayBuffer, gltfAccessor{ComponentType: gltfFloat, Count: count, Type: "VEC3", Min: minPos, Max: maxPos}),
+				"NORMAL":   addView(normals, gltfArrayBuffer, gltfAccessor{ComponentType: gltfFloat, Count: count, Type: "VEC3"}),
+			},
+			Indices:  addView(indices, gltfElementArray, gltfAccessor{ComponentType: gltfUnsignedInt, Count: len(indices), Type: "SCALAR"}),
+			Material: len(doc.Materials),
+		})
+
+		c := material.Color
+		gltfMat := gltfMaterial{
+			Name: material.Name,
+			PBRMetallicRoughness: gltfPBR{
+				BaseColorFactor: [4]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B), float64(c.A) / 255},
+				RoughnessFactor: 1,
+			},
+		}
+		if c.A != 0xff {
+			gltfMat.AlphaMode, gltfMat.DoubleSided = "BLEND", true
+		}
+		doc.Materials = append(doc.Materials, gltfMat)
+	}
+
+	if len(primitives) > 0 {
+		doc.Scenes[0].Nodes = []int{0}
+		doc.Nodes = []gltfNode{{Mesh: 0}}
+		doc.Meshes = []gltfMesh{{Primitives: primitives}}
+		doc.Buffers = []gltfBuffer{{ByteLength: buf.Len()}}
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("WriteGLB: %v", err)
+	}
+	// Both chunks must be aligned to 4 bytes, where the JSON chunk is padded with
+	// spaces and the binary chunk is padded with zeros.
+	for len(jsonData)%4 != 0 {
+		jsonData = append(jsonData, ' ')
+	}
+	binData := buf.Bytes()
+	for len(binData)%4 != 0 {
+		binData = append(binData, 0)
+	}
+
+	length := 12 + 8 + len(jsonData)
+	if len(binData) > 0 {
+		length += 8 + len(binData)
+	}
+	out := bytes.NewBuffer(make([]byte, 0, length))
+	_ = binary.Write(out, binary.LittleEndian, [3]uint32{glbMagic, glbVersion, uint32(length)})
+	_ = binary.Write(out, binary.LittleEndian, [2]uint32{uint32(len(jsonData)), glbChunkJSON})
+	out.Write(jsonData)
+	if len(binData) > 0 {
+		_ = binary.Write(out, binary.LittleEndian, [2]uint32{uint32(len(binData)), glbChunkBIN})
+		out.Write(binData)
+	}
+
+	if _, err = w.Write(out.Bytes()); err != nil {
+		return fmt.Errorf("WriteGLB: %v", err)
+	}
+	return nil
+}
+
+// srgbToLinear converts a channel of an sRGB color to the linear one, which is used by glTF.
+func srgbToLinear(c uint8) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
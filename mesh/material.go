@@ -0,0 +1,58 @@
+package mesh
+
+import (
+	"image/color"
+	"path"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+// The colors that the tinted blocks are multiplied by, which are the ones of
+// plains. The materials are shared by all the biomes, so the biome is not used.
+var defaultTints = map[block.MapTint]color.NRGBA{
+	block.MapTintGrass:   {R: 0x91, G: 0xbd, B: 0x59, A: 0xff},
+	block.MapTintFoliage: {R: 0x77, G: 0xab, B: 0x2f, A: 0xff},
+	block.MapTintWater:   {R: 0x44, G: 0xaf, B: 0xf5, A: 0xff},
+}
+
+// The alpha of the water, and the color of the transparent blocks that
+// are not shown on the maps, e.g. glass, glass panes and iron bars.
+const waterAlpha = 0xa0
+
+var glassColor = color.NRGBA{R: 0xdb, G: 0xe9, B: 0xf4, A: 0x60}
+
+// newBlockMaterial returns how the block whose runtime ID is runtimeID is meshed.
+func newBlockMaterial(runtimeID uint32) blockMaterial {
+	name, _, found := block.RuntimeIDToState(runtimeID)
+	if !found {
+		name = "minecraft:unknown"
+	}
+
+	c, _ := block.Classify(runtimeID)
+	if !c.Solid && !c.Liquid {
+		return blockMaterial{}
+	}
+	for _, pattern := range hiddenBlocks {
+		if matched, _ := path.Match(pattern, name); matched {
+			return blockMaterial{}
+		}
+	}
+
+	result := blockMaterial{visible: true, opaque: !c.Transparent, name: name}
+	mapColor, _ := block.MapColorOf(runtimeID)
+	if mapColor.Color.A == 0 {
+		result.color = glassColor
+		return result
+	}
+
+	result.color = color.NRGBA{R: mapColor.Color.R, G: mapColor.Color.G, B: mapColor.Color.B, A: 0xff}
+	if tint, ok := defaultTints[mapColor.Tint]; ok {
+		result.color.R = uint8(uint16(result.color.R) * uint16(tint.R) / 255)
+		result.color.G = uint8(uint16(result.color.G) * uint16(tint.G) / 255)
+		result.color.B = uint8(uint16(result.color.B) * uint16(tint.B) / 255)
+	}
+	if mapColor.Tint == block.MapTintWater {
+		result.color.A = waterAlpha
+	}
+	return result
+}
@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world"
+)
+
+// Mesh is the mesh of the blocks in a cuboid of a world, where each block is a unit cube and the faces
+// are grouped by the materials. The lowest corner of the cuboid is at the origin of the mesh, and the
+// axes are the same as the ones of the world, so Y is up.
+type Mesh struct {
+	// Materials holds the materials that have any faces, which are sorted by their names.
+	Materials []*Material
+}
+
+// Material is a material of a Mesh, which is shared by all the states of a block.
+type Material struct {
+	// Name is the name of the block, e.g. minecraft:stone.
+	Name string
+	// Color is the color of the block, which is not alpha-premultiplied.
+	// The alpha is less than 255 for water and glass-like blocks.
+	Color color.NRGBA
+	// Quads holds the faces of the blocks that use this material.
+	Quads []Quad
+}
+
+// Quad is a square face of a block.
+type Quad struct {
+	// Vertices holds the corners of the face, which are in
+	// counter-clockwise order when seen from the outside.
+	Vertices [4][3]float32
+	// Normal is the direction that the face faces.
+	Normal [3]float32
+}
+
+// face is a face of the unit cube.
+type face struct {
+	// offset is the position of the neighbour that the face touches.
+	offset [3]int
+	// corners holds the corners of the face in counter-clockwise order.
+	corners [4][3]float32
+}
+
+// faces holds the 6 faces of the unit cube.
+var faces = [6]face{
+	{offset: [3]int{-1, 0, 0}, corners: [4][3]float32{{0, 0, 0}, {0, 0, 1}, {0, 1, 1}, {0, 1, 0}}},
+	{offset: [3]int{1, 0, 0}, corners: [4][3]float32{{1, 0, 0}, {1, 1, 0}, {1, 1, 1}, {1, 0, 1}}},
+	{offset: [3]int{0, -1, 0}, corners: [4][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 0, 1}, {0, 0, 1}}},
+	{offset: [3]int{0, 1, 0}, corners: [4][3]float32{{0, 1, 0}, {0, 1, 1}, {1, 1, 1}, {1, 1, 0}}},
+	{offset: [3]int{0, 0, -1}, corners: [4][3]float32{{0, 0, 0}, {0, 1, 0}, {1, 1, 0}, {1, 0, 0}}},
+	{offset: [3]int{0, 0, 1}, corners: [4][3]float32{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1}}},
+}
+
+// hiddenBlocks holds the name patterns of the solid blocks that are invisible in game.
+var hiddenBlocks = []string{
+	"minecraft:barrier", "minecraft:invisible_bedrock", "minecraft:structure_void",
+	"minecraft:light_block_*", "minecraft:moving_block",
+}
+
+// blockMaterial is how a block is meshed.
+type blockMaterial struct {
+	// visible is false if the block is not meshed.
+	visible bool
+	// opaque is true if the block hides the faces of its neighbours.
+	opaque bool
+	// name is the name of the material.
+	name string
+	// color is the color of the material.
+	color color.NRGBA
+}
+
+// Build builds the mesh of the blocks in the cuboid between start and end (both inclusive) in dm dimension
+// of w. The chunks are read by LoadChunk, and the missing chunks are treated as air.
+//
+// Only the solid blocks and the liquids are meshed, and each of them is a unit cube whatever its shape is,
+// so the plants, torches and other blocks without collision are skipped. The faces between two opaque blocks,
+// and the ones between two same blocks (e.g. water and water, glass and glass) are culled. The faces at the
+// boundary of the cuboid are kept, so that the mesh is closed.
+func Build(w world.World, dm define.Dimension, start, end define.BlockPos) (*Mesh, error) {
+	for i := range 3 {
+		start[i], end[i] = min(start[i], end[i]), max(start[i], end[i])
+	}
+	size := [3]int{int(end[0]-start[0]) + 1, int(end[1]-start[1]) + 1, int(end[2]-start[2]) + 1}
+
+	blocks, err := loadBlocks(w, dm, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("Build: %v", err)
+	}
+	index := func(x, y, z int) int {
+		return (x*size[1]+y)*size[2] + z
+	}
+
+	materialCache := make(map[uint32]blockMaterial)
+	materialOf := func(runtimeID uint32) blockMaterial {
+		m, ok := materialCache[runtimeID]
+		if !ok {
+			m = newBlockMaterial(runtimeID)
+			materialCache[runtimeID] = m
+		}
+		return m
+	}
+
+	materials := make(map[string]*Material)
+	for x := range size[0] {
+		for y := range size[1] {
+			for z := range size[2] {
+				runtimeID := blocks[index(x, y, z)]
+				current := materialOf(runtimeID)
+				if !current.visible {
+					continue
+				}
+
+				for _, f := range faces {
+					nx, ny, nz := x+f.offset[0], y+f.offset[1], z+f.offset[2]
+					if nx >= 0 && ny >= 0 && nz >= 0 && nx < size[0] && ny < size[1] && nz < size[2] {
+						neighbour := materialOf(blocks[index(nx, ny, nz)])
+						if neighbour.opaque || (neighbour.visible && neighbour.name == current.name) {
+							continue
+						}
+					}
+
+					m, ok := materials[current.name]
+					if !ok {
+						m = &Material{Name: current.name, Color: current.color}
+						materials[current.name] = m
+					}
+					quad := Quad{Normal: [3]float32{float32(f.offset[0]), float32(f.offset[1]), float32(f.offset[2])}}
+					for i, corner := range f.corners {
+						quad.Vertices[i] = [3]float32{float32(x) + corner[0], float32(y) + corner[1], float32(z) + corner[2]}
+					}
+					m.Quads = append(m.Quads, quad)
+				}
+			}
+		}
+	}
+
+	result := &Mesh{Materials: make([]*Material, 0, len(materials))}
+	for _, m := range materials {
+		result.Materials = append(result.Materials, m)
+	}
+	slices.SortFunc(result.Materials, func(a, b *Material) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// loadBlocks loads the blocks in layer 0 of the cuboid between start and end, which must be normalized.
+// The result is indexed by (x*sizeY+y)*sizeZ+z, where x, y and z are relative to start.
+func loadBlocks(w world.World, dm define.Dimension, start, end define.BlockPos) ([]uint32, error) {
+	sizeY, sizeZ := int(end[1]-start[1])+1, int(end[2]-start[2])+1
+	blocks := make([]uint32, int(end[0]-start[0]+1)*sizeY*sizeZ)
+	for i := range blocks {
+		blocks[i] = block.AirRuntimeID
+	}
+
+	r := dm.Range()
+	for _, position := range define.ChunkPosArea(start.ChunkPos(), end.ChunkPos()) {
+		c, exists, err := w.LoadChunk(dm, position)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		for x := max(start[0], position[0]<<4); x <= min(end[0], position[0]<<4+15); x++ {
+			for z := max(start[2], position[1]<<4); z <= min(end[2], position[1]<<4+15); z++ {
+				for y := max(start[1], int32(r[0])); y <= min(end[1], int32(r[1])); y++ {
+					index := (int(x-start[0])*sizeY+int(y-start[1]))*sizeZ + int(z-start[2])
+					blocks[index] = c.Block(uint8(x&15), int16(y), uint8(z&15), chunk.BlockLayer)
+				}
+			}
+		}
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,66 @@
+package mesh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// materialName returns the name of m that is safe to be used in OBJ and MTL files.
+func materialName(m *Material) string {
+	return strings.NewReplacer(":", "_", " ", "_").Replace(m.Name)
+}
+
+// WriteOBJ writes m as a Wavefront OBJ file to obj and its materials as an MTL file to mtl,
+// where mtlName is the name of the MTL file that the OBJ file refers to. The vertices that
+// shared by the faces are only written once.
+func (m *Mesh) WriteOBJ(obj io.Writer, mtl io.Writer, mtlName string) error {
+	mtlWriter := bufio.NewWriter(mtl)
+	for _, material := range m.Materials {
+		c := material.Color
+		fmt.Fprintf(mtlWriter, "newmtl %s\n", materialName(material))
+		fmt.Fprintf(mtlWriter, "Ka 0 0 0\nKd %.4f %.4f %.4f\nKs 0 0 0\n", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+		fmt.Fprintf(mtlWriter, "d %.4f\nillum 1\n\n", float64(c.A)/255)
+	}
+	if err := mtlWriter.Flush(); err != nil {
+		return fmt.Errorf("WriteOBJ: %v", err)
+	}
+
+	objWriter := bufio.NewWriter(obj)
+	fmt.Fprintf(objWriter, "mtllib %s\n", mtlName)
+
+	// The vertices and the normals are indexed from 1 in OBJ files.
+	vertices := make(map[[3]float32]int)
+	normals := make(map[[3]float32]int)
+	for _, material := range m.Materials {
+		for _, quad := range material.Quads {
+			for _, v := range quad.Vertices {
+				if _, ok := vertices[v]; !ok {
+					vertices[v] = len(vertices) + 1
+					fmt.Fprintf(objWriter, "v %g %g %g\n", v[0], v[1], v[2])
+				}
+			}
+			if _, ok := normals[quad.Normal]; !ok {
+				normals[quad.Normal] = len(normals) + 1
+				fmt.Fprintf(objWriter, "vn %g %g %g\n", quad.Normal[0], quad.Normal[1], quad.Normal[2])
+			}
+		}
+	}
+
+	for _, material := range m.Materials {
+		fmt.Fprintf(objWriter, "usemtl %s\n", materialName(material))
+		for _, quad := range material.Quads {
+			n := normals[quad.Normal]
+			fmt.Fprintf(objWriter, "f %d//%d %d//%d %d//%d %d//%d\n",
+				vertices[quad.Vertices[0]], n, vertices[quad.Vertices[1]], n,
+				vertices[quad.Vertices[2]], n, vertices[quad.Vertices[3]], n,
+			)
+		}
+	}
+	if err := objWriter.Flush(); err != nil {
+		return fmt.Errorf("WriteOBJ: %v", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,14 @@
-from .world.chunk import Chunk, new_chunk
+from .world.chunk import Chunk, new_chunk, chunk_from_json
 from .world.sub_chunk import SubChunk, SubChunkWithIndex, new_sub_chunk
+from .world.structure import (
+    Structure,
+    new_structure,
+    from_mcstructure,
+    from_schem,
+    from_schematic,
+)
 from .world.world import World, new_world
+from .world.biome import biome_by_id, biome_by_name, all_biomes, register_biome
 from .world.level_dat import LevelDat, Abilities
 
 from .world.constant import (
@@ -18,22 +26,37 @@ from .world.constant import (
     RANGE_INVALID,
     AIR_BLOCK_STATES,
     AIR_BLOCK_RUNTIME_ID,
+    STRUCTURE_VOID_RUNTIME_ID,
+    ROTATION_0,
+    ROTATION_90,
+    ROTATION_180,
+    ROTATION_270,
+    MIRROR_NONE,
+    MIRROR_X,
+    MIRROR_Z,
 )
 
 from .world.define import (
     ChunkPos,
     SubChunkPos,
+    BlockPos,
     Range,
     Dimension,
     BlockStates,
+    BlockClassification,
+    Biome,
     QuickChunkBlocks,
     QuickSubChunkBlocks,
     HashWithPosY,
+    BlockStatistics,
+    chunk_pos_area,
 )
 
 from .world.conversion import (
     runtime_id_to_state,
     state_to_runtime_id,
+    block_classification,
+    chunk_network_payload,
     sub_chunk_network_payload,
     from_sub_chunk_network_payload,
     sub_chunk_disk_payload,
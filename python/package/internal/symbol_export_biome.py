@@ -0,0 +1,84 @@
+import struct
+from io import BytesIO
+from .types import LIB
+from .types import CSlice, CString, CInt
+from .types import as_c_bytes, as_c_string, as_python_bytes, as_python_string
+
+
+LIB.BiomeByID.argtypes = [CInt]
+LIB.BiomeByName.argtypes = [CString]
+LIB.AllBiomes.argtypes = []
+LIB.RegisterBiome.argtypes = [CSlice]
+
+LIB.BiomeByID.restype = CSlice
+LIB.BiomeByName.restype = CSlice
+LIB.AllBiomes.restype = CSlice
+LIB.RegisterBiome.restype = CString
+
+
+BiomeTuple = tuple[
+    int,
+    str,
+    float,
+    float,
+    tuple[int, int, int],
+    tuple[int, int, int],
+    tuple[int, int, int],
+]
+
+
+def unpack_biome(reader: BytesIO) -> BiomeTuple:
+    biome_id: int = struct.unpack("<I", reader.read(4))[0]
+    length: int = struct.unpack("<H", reader.read(2))[0]
+    name = reader.read(length).decode(encoding="utf-8")
+    temperature, downfall = struct.unpack("<dd", reader.read(16))
+    colors = reader.read(9)
+    return (
+        biome_id,
+        name,
+        temperature,
+        downfall,
+        (colors[0], colors[1], colors[2]),
+        (colors[3], colors[4], colors[5]),
+        (colors[6], colors[7], colors[8]),
+    )
+
+
+def pack_biome(biome: BiomeTuple) -> bytes:
+    biome_id, name, temperature, downfall, grass, foliage, water = biome
+    name_bytes = name.encode(encoding="utf-8")
+    return (
+        struct.pack("<IH", biome_id, len(name_bytes))
+        + name_bytes
+        + struct.pack("<dd", temperature, downfall)
+        + bytes([*grass, *foliage, *water])
+    )
+
+
+def biome_by_id(biome_id: int) -> BiomeTuple | None:
+    reader = BytesIO(as_python_bytes(LIB.BiomeByID(CInt(biome_id))))
+    if reader.read(1) == b"\x00":
+        return None
+    return unpack_biome(reader)
+
+
+def biome_by_name(name: str) -> BiomeTuple | None:
+    reader = BytesIO(as_python_bytes(LIB.BiomeByName(as_c_string(name))))
+    if reader.read(1) == b"\x00":
+        return None
+    return unpack_biome(reader)
+
+
+def all_biomes() -> list[BiomeTuple]:
+    payload = as_python_bytes(LIB.AllBiomes())
+    reader = BytesIO(payload)
+    result = []
+
+    while reader.tell() < len(payload):
+        result.append(unpack_biome(reader))
+
+    return result
+
+
+def register_biome(biome: BiomeTuple) -> str:
+    return as_python_string(LIB.RegisterBiome(as_c_bytes(pack_biome(biome))))
@@ -8,17 +8,32 @@ from .types import as_c_bytes, as_python_bytes, as_python_string
 
 LIB.NewChunk.argtypes = [CInt, CInt]
 LIB.ReleaseChunk.argtypes = [CLongLong]
+LIB.ChunkFromJSON.argtypes = [CSlice]
 LIB.Chunk_Biome.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.Chunk_BiomeCounts.argtypes = [CLongLong]
 LIB.Chunk_Biomes.argtypes = [CLongLong]
 LIB.Chunk_Block.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
+LIB.Chunk_BlockCounts.argtypes = [CLongLong, CInt]
 LIB.Chunk_Blocks.argtypes = [CLongLong, CInt]
+LIB.Chunk_Clone.argtypes = [CLongLong]
 LIB.Chunk_Compact.argtypes = [CLongLong]
 LIB.Chunk_Equals.argtypes = [CLongLong, CLongLong]
+LIB.Chunk_ComputeLight.argtypes = [CLongLong, CInt, CInt, CSlice]
+LIB.Chunk_SkyLight.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.Chunk_BlockLight.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.Chunk_HighestBlock.argtypes = [CLongLong, CInt, CInt]
+LIB.Chunk_HighestSolidBlock.argtypes = [CLongLong, CInt, CInt]
 LIB.Chunk_HighestFilledSubChunk.argtypes = [CLongLong]
+LIB.Chunk_HighestLightBlocker.argtypes = [CLongLong, CInt, CInt]
+LIB.Chunk_IsWaterlogged.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.Chunk_MarshalJSON.argtypes = [CLongLong]
+LIB.Chunk_PlaceBlock.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
+LIB.Chunk_ReplaceBlocks.argtypes = [CLongLong, CInt, CSlice]
 LIB.Chunk_SetBiome.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
 LIB.Chunk_SetBiomes.argtypes = [CLongLong, CSlice]
 LIB.Chunk_SetBlock.argtypes = [CLongLong, CInt, CInt, CInt, CInt, CInt]
 LIB.Chunk_SetBlocks.argtypes = [CLongLong, CInt, CSlice]
+LIB.Chunk_SetWaterlogged.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
 LIB.Chunk_Sub.argtypes = [CLongLong]
 LIB.Chunk_SetSub.argtypes = [CLongLong, CSlice]
 LIB.Chunk_SubChunk.argtypes = [CLongLong, CInt]
@@ -26,17 +41,32 @@ LIB.Chunk_SetSubChunk.argtypes = [CLongLong, CLongLong, CInt]
 
 LIB.NewChunk.restype = CSlice
 LIB.ReleaseChunk.restype = None
+LIB.ChunkFromJSON.restype = CSlice
 LIB.Chunk_Biome.restype = CInt
+LIB.Chunk_BiomeCounts.restype = CSlice
 LIB.Chunk_Biomes.restype = CSlice
 LIB.Chunk_Block.restype = CInt
+LIB.Chunk_BlockCounts.restype = CSlice
 LIB.Chunk_Blocks.restype = CSlice
+LIB.Chunk_Clone.restype = CLongLong
 LIB.Chunk_Compact.restype = CString
 LIB.Chunk_Equals.restype = CInt
+LIB.Chunk_ComputeLight.restype = CString
+LIB.Chunk_SkyLight.restype = CInt
+LIB.Chunk_BlockLight.restype = CInt
+LIB.Chunk_HighestBlock.restype = CInt
+LIB.Chunk_HighestSolidBlock.restype = CInt
 LIB.Chunk_HighestFilledSubChunk.restype = CInt
+LIB.Chunk_HighestLightBlocker.restype = CInt
+LIB.Chunk_IsWaterlogged.restype = CInt
+LIB.Chunk_MarshalJSON.restype = CSlice
+LIB.Chunk_PlaceBlock.restype = CString
+LIB.Chunk_ReplaceBlocks.restype = CInt
 LIB.Chunk_SetBiome.restype = CString
 LIB.Chunk_SetBiomes.restype = CString
 LIB.Chunk_SetBlock.restype = CString
 LIB.Chunk_SetBlocks.restype = CString
+LIB.Chunk_SetWaterlogged.restype = CInt
 LIB.Chunk_Sub.restype = CSlice
 LIB.Chunk_SetSub.restype = CString
 LIB.Chunk_SubChunk.restype = CLongLong
@@ -52,10 +82,28 @@ def release_chunk(id: int) -> None:
     LIB.ReleaseChunk(CLongLong(id))
 
 
+def chunk_from_json(payload: bytes) -> tuple[int, int, int]:
+    result = as_python_bytes(LIB.ChunkFromJSON(as_c_bytes(payload)))
+    return struct.unpack("<hhQ", result)
+
+
 def chunk_biome(id: int, x: int, y: int, z: int) -> int:
     return int(LIB.Chunk_Biome(CLongLong(id), CInt(x), CInt(y), CInt(z)))
 
 
+def chunk_biome_counts(id: int) -> dict[int, int]:
+    payload = as_python_bytes(LIB.Chunk_BiomeCounts(CLongLong(id)))
+    result = {}
+
+    ptr = 0
+    while ptr < len(payload):
+        key, count = struct.unpack("<II", payload[ptr : ptr + 8])
+        result[key] = count
+        ptr += 8
+
+    return result
+
+
 def chunk_biomes(id: int) -> numpy.ndarray:
     return numpy.frombuffer(
         as_python_bytes(LIB.Chunk_Biomes(CLongLong(id))), dtype="<u4"
@@ -66,12 +114,29 @@ def chunk_block(id: int, x: int, y: int, z: int, layer: int) -> int:
     return int(LIB.Chunk_Block(CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(layer)))
 
 
+def chunk_block_counts(id: int, layer: int) -> dict[int, int]:
+    payload = as_python_bytes(LIB.Chunk_BlockCounts(CLongLong(id), CInt(layer)))
+    result = {}
+
+    ptr = 0
+    while ptr < len(payload):
+        key, count = struct.unpack("<II", payload[ptr : ptr + 8])
+        result[key] = count
+        ptr += 8
+
+    return result
+
+
 def chunk_blocks(id: int, layer: int) -> numpy.ndarray:
     return numpy.frombuffer(
         as_python_bytes(LIB.Chunk_Blocks(CLongLong(id), CInt(layer))), dtype="<u4"
     ).copy()
 
 
+def chunk_clone(id: int) -> int:
+    return int(LIB.Chunk_Clone(CLongLong(id)))
+
+
 def chunk_compact(id: int) -> str:
     return as_python_string(LIB.Chunk_Compact(CLongLong(id)))
 
@@ -80,10 +145,68 @@ def chunk_equals(id: int, another_chunk_id: int) -> int:
     return int(LIB.Chunk_Equals(CLongLong(id), CLongLong(another_chunk_id)))
 
 
+def chunk_compute_light(
+    id: int, x: int, z: int, neighbours: dict[tuple[int, int], int]
+) -> str:
+    writer = BytesIO()
+    for (nx, nz), neighbour_id in neighbours.items():
+        writer.write(struct.pack("<iiq", nx, nz, neighbour_id))
+    return as_python_string(
+        LIB.Chunk_ComputeLight(
+            CLongLong(id), CInt(x), CInt(z), as_c_bytes(writer.getvalue())
+        )
+    )
+
+
+def chunk_sky_light(id: int, x: int, y: int, z: int) -> int:
+    return int(LIB.Chunk_SkyLight(CLongLong(id), CInt(x), CInt(y), CInt(z)))
+
+
+def chunk_block_light(id: int, x: int, y: int, z: int) -> int:
+    return int(LIB.Chunk_BlockLight(CLongLong(id), CInt(x), CInt(y), CInt(z)))
+
+
+def chunk_highest_block(id: int, x: int, z: int) -> int:
+    return int(LIB.Chunk_HighestBlock(CLongLong(id), CInt(x), CInt(z)))
+
+
+def chunk_highest_solid_block(id: int, x: int, z: int) -> int:
+    return int(LIB.Chunk_HighestSolidBlock(CLongLong(id), CInt(x), CInt(z)))
+
+
 def chunk_highest_filled_sub_chunk(id: int) -> int:
     return int(LIB.Chunk_HighestFilledSubChunk(CLongLong(id)))
 
 
+def chunk_highest_light_blocker(id: int, x: int, z: int) -> int:
+    return int(LIB.Chunk_HighestLightBlocker(CLongLong(id), CInt(x), CInt(z)))
+
+
+def chunk_is_waterlogged(id: int, x: int, y: int, z: int) -> int:
+    return int(LIB.Chunk_IsWaterlogged(CLongLong(id), CInt(x), CInt(y), CInt(z)))
+
+
+def chunk_marshal_json(id: int) -> bytes:
+    return as_python_bytes(LIB.Chunk_MarshalJSON(CLongLong(id)))
+
+
+def chunk_place_block(id: int, x: int, y: int, z: int, block_runtime_id: int) -> str:
+    return as_python_string(
+        LIB.Chunk_PlaceBlock(
+            CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(block_runtime_id)
+        )
+    )
+
+
+def chunk_replace_blocks(id: int, layer: int, mapping: dict[int, int]) -> int:
+    writer = BytesIO()
+    for old, new in mapping.items():
+        writer.write(struct.pack("<II", old, new))
+    return int(
+        LIB.Chunk_ReplaceBlocks(CLongLong(id), CInt(layer), as_c_bytes(writer.getvalue()))
+    )
+
+
 def chunk_set_biome(id: int, x: int, y: int, z: int, biome_id: int) -> str:
     return as_python_string(
         LIB.Chunk_SetBiome(CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(biome_id))
@@ -117,6 +240,14 @@ def chunk_set_blocks(id: int, layer: int, blocks: numpy.ndarray) -> str:
     )
 
 
+def chunk_set_waterlogged(id: int, x: int, y: int, z: int, waterlogged: bool) -> int:
+    return int(
+        LIB.Chunk_SetWaterlogged(
+            CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(int(waterlogged))
+        )
+    )
+
+
 def chunk_sub(id: int) -> list[int]:
     raw = as_python_bytes(LIB.Chunk_Sub(CLongLong(id)))
     result = []
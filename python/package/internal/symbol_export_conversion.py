@@ -9,6 +9,8 @@ from ..utils import marshalNBT, unmarshalNBT
 
 LIB.RuntimeIDToState.argtypes = [CInt]
 LIB.StateToRuntimeID.argtypes = [CString, CSlice]
+LIB.BlockClassification.argtypes = [CInt]
+LIB.ChunkNetworkPayload.argtypes = [CLongLong, CSlice]
 LIB.SubChunkNetworkPayload.argtypes = [CLongLong, CInt, CInt, CInt]
 LIB.FromSubChunkNetworkPayload.argtypes = [CInt, CInt, CSlice]
 LIB.SubChunkDiskPayload.argtypes = [CLongLong, CInt, CInt, CInt]
@@ -16,6 +18,8 @@ LIB.FromSubChunkDiskPayload.argtypes = [CInt, CInt, CSlice]
 
 LIB.RuntimeIDToState.restype = CSlice
 LIB.StateToRuntimeID.restype = CSlice
+LIB.BlockClassification.restype = CSlice
+LIB.ChunkNetworkPayload.restype = CSlice
 LIB.SubChunkNetworkPayload.restype = CSlice
 LIB.FromSubChunkNetworkPayload.restype = CSlice
 LIB.SubChunkDiskPayload.restype = CSlice
@@ -61,6 +65,49 @@ def state_to_runtime_id(
     return struct.unpack("<I", reader.read(4))[0], True
 
 
+def block_classification(
+    block_runtime_id: int,
+) -> tuple[bool, bool, bool, int, int, bool, bool]:
+    payload = as_python_bytes(LIB.BlockClassification(CInt(block_runtime_id)))
+
+    if payload[0] == 0:
+        return False, False, True, 0, 0, False, False
+
+    return (
+        payload[1] == 1,
+        payload[2] == 1,
+        payload[3] == 1,
+        payload[4],
+        payload[5],
+        payload[6] == 1,
+        True,
+    )
+
+
+def chunk_network_payload(
+    id: int, block_entities: list[nbtlib.tag.Compound]
+) -> tuple[bytes, int]:
+    writer = BytesIO()
+
+    for i in block_entities:
+        w = BytesIO()
+        marshalNBT.MarshalPythonNBTObjectToWriter(w, i, "")
+
+        binary_nbt = w.getvalue()
+        length = struct.pack("<I", len(binary_nbt))
+
+        writer.write(length)
+        writer.write(binary_nbt)
+
+    payload = as_python_bytes(
+        LIB.ChunkNetworkPayload(CLongLong(id), as_c_bytes(writer.getvalue()))
+    )
+    if len(payload) == 0:
+        return b"", 0
+
+    return payload[4:], struct.unpack("<I", payload[:4])[0]
+
+
 def sub_chunk_network_payload(
     id: int, range_start: int, range_end: int, ind: int
 ) -> bytes:
@@ -0,0 +1,106 @@
+import struct
+from .types import LIB
+from .types import CSlice, CString, CInt, CLongLong
+from .types import as_c_bytes, as_python_bytes, as_python_string
+
+
+LIB.NewStructure.argtypes = [CInt, CInt, CInt]
+LIB.ReleaseStructure.argtypes = [CLongLong]
+LIB.FromMCStructure.argtypes = [CSlice]
+LIB.FromSchem.argtypes = [CSlice]
+LIB.FromSchematic.argtypes = [CSlice]
+LIB.Structure_Block.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
+LIB.Structure_MCStructure.argtypes = [CLongLong]
+LIB.Structure_Mirror.argtypes = [CLongLong, CInt]
+LIB.Structure_Origin.argtypes = [CLongLong]
+LIB.Structure_Rotate.argtypes = [CLongLong, CInt]
+LIB.Structure_SetBlock.argtypes = [CLongLong, CInt, CInt, CInt, CInt, CLongLong]
+LIB.Structure_SetOrigin.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.Structure_Size.argtypes = [CLongLong]
+
+LIB.NewStructure.restype = CLongLong
+LIB.ReleaseStructure.restype = None
+LIB.FromMCStructure.restype = CLongLong
+LIB.FromSchem.restype = CLongLong
+LIB.FromSchematic.restype = CLongLong
+LIB.Structure_Block.restype = CLongLong
+LIB.Structure_MCStructure.restype = CSlice
+LIB.Structure_Mirror.restype = CLongLong
+LIB.Structure_Origin.restype = CSlice
+LIB.Structure_Rotate.restype = CLongLong
+LIB.Structure_SetBlock.restype = CString
+LIB.Structure_SetOrigin.restype = CString
+LIB.Structure_Size.restype = CSlice
+
+
+def new_structure(x: int, y: int, z: int) -> int:
+    return int(LIB.NewStructure(CInt(x), CInt(y), CInt(z)))
+
+
+def release_structure(id: int) -> None:
+    LIB.ReleaseStructure(CLongLong(id))
+
+
+def from_mcstructure(payload: bytes) -> int:
+    return int(LIB.FromMCStructure(as_c_bytes(payload)))
+
+
+def from_schem(payload: bytes) -> int:
+    return int(LIB.FromSchem(as_c_bytes(payload)))
+
+
+def from_schematic(payload: bytes) -> int:
+    return int(LIB.FromSchematic(as_c_bytes(payload)))
+
+
+def structure_block(id: int, x: int, y: int, z: int, layer: int) -> int:
+    return int(
+        LIB.Structure_Block(CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(layer))
+    )
+
+
+def structure_mcstructure(id: int) -> bytes:
+    return as_python_bytes(LIB.Structure_MCStructure(CLongLong(id)))
+
+
+def structure_mirror(id: int, mirror: int) -> int:
+    return int(LIB.Structure_Mirror(CLongLong(id), CInt(mirror)))
+
+
+def structure_origin(id: int) -> tuple[int, int, int] | None:
+    payload = as_python_bytes(LIB.Structure_Origin(CLongLong(id)))
+    if len(payload) == 0:
+        return None
+    return struct.unpack("<iii", payload)
+
+
+def structure_rotate(id: int, rotation: int) -> int:
+    return int(LIB.Structure_Rotate(CLongLong(id), CInt(rotation)))
+
+
+def structure_set_block(
+    id: int, x: int, y: int, z: int, layer: int, block_runtime_id: int
+) -> str:
+    return as_python_string(
+        LIB.Structure_SetBlock(
+            CLongLong(id),
+            CInt(x),
+            CInt(y),
+            CInt(z),
+            CInt(layer),
+            CLongLong(block_runtime_id),
+        )
+    )
+
+
+def structure_set_origin(id: int, x: int, y: int, z: int) -> str:
+    return as_python_string(
+        LIB.Structure_SetOrigin(CLongLong(id), CInt(x), CInt(y), CInt(z))
+    )
+
+
+def structure_size(id: int) -> tuple[int, int, int] | None:
+    payload = as_python_bytes(LIB.Structure_Size(CLongLong(id)))
+    if len(payload) == 0:
+        return None
+    return struct.unpack("<iii", payload)
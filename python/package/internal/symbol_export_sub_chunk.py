@@ -1,4 +1,6 @@
+import struct
 import numpy
+from io import BytesIO
 from .types import LIB
 from .types import CSlice, CString, CInt, CLongLong
 from .types import as_c_bytes, as_python_bytes, as_python_string
@@ -7,20 +9,32 @@ from .types import as_c_bytes, as_python_bytes, as_python_string
 LIB.NewSubChunk.argtypes = []
 LIB.ReleaseSubChunk.argtypes = [CLongLong]
 LIB.SubChunk_Block.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
+LIB.SubChunk_BlockCounts.argtypes = [CLongLong, CInt]
 LIB.SubChunk_Blocks.argtypes = [CLongLong, CInt]
+LIB.SubChunk_Clone.argtypes = [CLongLong]
 LIB.SubChunk_Empty.argtypes = [CLongLong]
 LIB.SubChunk_Equals.argtypes = [CLongLong, CLongLong]
+LIB.SubChunk_IsWaterlogged.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.SubChunk_PlaceBlock.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
+LIB.SubChunk_ReplaceBlocks.argtypes = [CLongLong, CInt, CSlice]
 LIB.SubChunk_SetBlock.argtypes = [CLongLong, CInt, CInt, CInt, CInt, CInt]
 LIB.SubChunk_SetBlocks.argtypes = [CLongLong, CInt, CSlice]
+LIB.SubChunk_SetWaterlogged.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
 
 LIB.NewSubChunk.restype = CLongLong
 LIB.ReleaseSubChunk.restype = None
 LIB.SubChunk_Block.restype = CInt
+LIB.SubChunk_BlockCounts.restype = CSlice
 LIB.SubChunk_Blocks.restype = CSlice
+LIB.SubChunk_Clone.restype = CLongLong
 LIB.SubChunk_Empty.restype = CInt
 LIB.SubChunk_Equals.restype = CInt
+LIB.SubChunk_IsWaterlogged.restype = CInt
+LIB.SubChunk_PlaceBlock.restype = CString
+LIB.SubChunk_ReplaceBlocks.restype = CInt
 LIB.SubChunk_SetBlock.restype = CString
 LIB.SubChunk_SetBlocks.restype = CString
+LIB.SubChunk_SetWaterlogged.restype = CInt
 
 
 def new_sub_chunk() -> int:
@@ -37,12 +51,29 @@ def sub_chunk_block(id: int, x: int, y: int, z: int, layer: int) -> int:
     )
 
 
+def sub_chunk_block_counts(id: int, layer: int) -> dict[int, int]:
+    payload = as_python_bytes(LIB.SubChunk_BlockCounts(CLongLong(id), CInt(layer)))
+    result = {}
+
+    ptr = 0
+    while ptr < len(payload):
+        key, count = struct.unpack("<II", payload[ptr : ptr + 8])
+        result[key] = count
+        ptr += 8
+
+    return result
+
+
 def sub_chunk_blocks(id: int, layer: int) -> numpy.ndarray:
     return numpy.frombuffer(
         as_python_bytes(LIB.SubChunk_Blocks(CLongLong(id), CInt(layer))), dtype="<u4"
     ).copy()
 
 
+def sub_chunk_clone(id: int) -> int:
+    return int(LIB.SubChunk_Clone(CLongLong(id)))
+
+
 def sub_chunk_empty(id: int) -> int:
     return int(LIB.SubChunk_Empty(CLongLong(id)))
 
@@ -51,6 +82,31 @@ def sub_chunk_equals(id: int, another_sub_chunk_id: int) -> int:
     return int(LIB.SubChunk_Equals(CLongLong(id), CLongLong(another_sub_chunk_id)))
 
 
+def sub_chunk_is_waterlogged(id: int, x: int, y: int, z: int) -> int:
+    return int(LIB.SubChunk_IsWaterlogged(CLongLong(id), CInt(x), CInt(y), CInt(z)))
+
+
+def sub_chunk_place_block(
+    id: int, x: int, y: int, z: int, block_runtime_id: int
+) -> str:
+    return as_python_string(
+        LIB.SubChunk_PlaceBlock(
+            CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(block_runtime_id)
+        )
+    )
+
+
+def sub_chunk_replace_blocks(id: int, layer: int, mapping: dict[int, int]) -> int:
+    writer = BytesIO()
+    for old, new in mapping.items():
+        writer.write(struct.pack("<II", old, new))
+    return int(
+        LIB.SubChunk_ReplaceBlocks(
+            CLongLong(id), CInt(layer), as_c_bytes(writer.getvalue())
+        )
+    )
+
+
 def sub_chunk_set_block(
     id: int, x: int, y: int, z: int, layer: int, block_runtime_id: int
 ) -> str:
@@ -70,3 +126,13 @@ def sub_chunk_set_blocks(id: int, layer: int, blocks: numpy.ndarray) -> str:
     return as_python_string(
         LIB.SubChunk_SetBlocks(CLongLong(id), CInt(layer), as_c_bytes(blocks.tobytes()))
     )
+
+
+def sub_chunk_set_waterlogged(
+    id: int, x: int, y: int, z: int, waterlogged: bool
+) -> int:
+    return int(
+        LIB.SubChunk_SetWaterlogged(
+            CLongLong(id), CInt(x), CInt(y), CInt(z), CInt(int(waterlogged))
+        )
+    )
@@ -26,6 +26,7 @@ LIB.SaveNBTPayloadOnly.argtypes = [CLongLong, CInt, CInt, CInt, CSlice]
 LIB.SaveNBT.argtypes = [CLongLong, CInt, CInt, CInt, CSlice]
 LIB.LoadDeltaUpdate.argtypes = [CLongLong, CInt, CInt, CInt]
 LIB.SaveDeltaUpdate.argtypes = [CLongLong, CInt, CInt, CInt, CSlice]
+LIB.ApplyDeltaUpdates.argtypes = [CLongLong, CInt, CInt, CInt]
 LIB.LoadTimeStamp.argtypes = [CLongLong, CInt, CInt, CInt]
 LIB.SaveTimeStamp.argtypes = [CLongLong, CInt, CInt, CInt, CLongLong]
 LIB.LoadDeltaUpdateTimeStamp.argtypes = [CLongLong, CInt, CInt, CInt]
@@ -34,6 +35,36 @@ LIB.LoadFullSubChunkBlobHash.argtypes = [CLongLong, CInt, CInt, CInt]
 LIB.SaveFullSubChunkBlobHash.argtypes = [CLongLong, CInt, CInt, CInt, CSlice]
 LIB.LoadSubChunkBlobHash.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
 LIB.SaveSubChunkBlobHash.argtypes = [CLongLong, CInt, CInt, CInt, CInt, CLongLong]
+LIB.ChunkPositions.argtypes = [CLongLong, CInt]
+LIB.ReplaceBlocks.argtypes = [CLongLong, CInt, CInt, CSlice]
+LIB.BlockStatistics.argtypes = [CLongLong, CInt, CInt, CSlice]
+LIB.CaptureStructure.argtypes = [
+    CLongLong,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+]
+LIB.PasteStructure.argtypes = [CLongLong, CInt, CInt, CInt, CInt, CLongLong]
+LIB.ConvertJavaWorld.argtypes = [CLongLong, CString]
+LIB.RenderMapTiles.argtypes = [CLongLong, CInt, CString, CInt]
+LIB.ExportMesh.argtypes = [
+    CLongLong,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CInt,
+    CString,
+]
+LIB.ExportChunkJSON.argtypes = [CLongLong, CInt, CInt, CInt]
+LIB.ImportChunkJSON.argtypes = [CLongLong, CSlice]
+LIB.PregenerateChunks.argtypes = [CLongLong, CInt, CInt, CInt, CInt]
 
 LIB.NewBedrockWorld.restype = CLongLong
 LIB.ReleaseBedrockWorld.restype = None
@@ -54,6 +85,7 @@ LIB.SaveNBTPayloadOnly.restype = CString
 LIB.SaveNBT.restype = CString
 LIB.LoadDeltaUpdate.restype = CSlice
 LIB.SaveDeltaUpdate.restype = CString
+LIB.ApplyDeltaUpdates.restype = CString
 LIB.LoadTimeStamp.restype = CLongLong
 LIB.SaveTimeStamp.restype = CString
 LIB.LoadDeltaUpdateTimeStamp.restype = CLongLong
@@ -62,6 +94,17 @@ LIB.LoadFullSubChunkBlobHash.restype = CSlice
 LIB.SaveFullSubChunkBlobHash.restype = CString
 LIB.LoadSubChunkBlobHash.restype = CLongLong
 LIB.SaveSubChunkBlobHash.restype = CString
+LIB.ChunkPositions.restype = CSlice
+LIB.ReplaceBlocks.restype = CLongLong
+LIB.BlockStatistics.restype = CSlice
+LIB.CaptureStructure.restype = CLongLong
+LIB.PasteStructure.restype = CString
+LIB.ConvertJavaWorld.restype = CSlice
+LIB.RenderMapTiles.restype = CLongLong
+LIB.ExportMesh.restype = CString
+LIB.ExportChunkJSON.restype = CSlice
+LIB.ImportChunkJSON.restype = CString
+LIB.PregenerateChunks.restype = CLongLong
 
 
 def new_bedrock_world(dir: str) -> int:
@@ -229,6 +272,12 @@ def save_delta_update(id: int, dm: int, x: int, z: int, payload: bytes) -> str:
     )
 
 
+def apply_delta_updates(id: int, dm: int, x: int, z: int) -> str:
+    return as_python_string(
+        LIB.ApplyDeltaUpdates(CLongLong(id), CInt(dm), CInt(x), CInt(z))
+    )
+
+
 def load_time_stamp(id: int, dm: int, x: int, z: int) -> int:
     return int(LIB.LoadTimeStamp(CLongLong(id), CInt(dm), CInt(x), CInt(z)))
 
@@ -300,3 +349,141 @@ def save_sub_chunk_blob_hash(
             CLongLong(id), CInt(dm), CInt(x), CInt(y), CInt(z), CLongLong(hash)
         )
     )
+
+
+def chunk_positions(id: int, dm: int) -> list[tuple[int, int]]:
+    payload = as_python_bytes(LIB.ChunkPositions(CLongLong(id), CInt(dm)))
+    result = []
+
+    ptr = 0
+    while ptr < len(payload):
+        result.append(struct.unpack("<ii", payload[ptr : ptr + 8]))
+        ptr += 8
+
+    return result
+
+
+def replace_blocks(id: int, dm: int, layer: int, mapping: dict[int, int]) -> int:
+    writer = BytesIO()
+    for old, new in mapping.items():
+        writer.write(struct.pack("<II", old, new))
+    return int(
+        LIB.ReplaceBlocks(
+            CLongLong(id), CInt(dm), CInt(layer), as_c_bytes(writer.getvalue())
+        )
+    )
+
+
+def block_statistics(
+    id: int, dm: int, layer: int, positions: list[tuple[int, int]] | None
+) -> tuple[int, dict[int, int], dict[int, int]] | None:
+    writer = BytesIO()
+    for x, z in positions or []:
+        writer.write(struct.pack("<ii", x, z))
+
+    payload = as_python_bytes(
+        LIB.BlockStatistics(
+            CLongLong(id), CInt(dm), CInt(layer), as_c_bytes(writer.getvalue())
+        )
+    )
+    if len(payload) == 0:
+        return None
+
+    chunks = struct.unpack("<I", payload[0:4])[0]
+    ptr = 4
+
+    result = []
+    for _ in range(2):
+        counts = {}
+        length = struct.unpack("<I", payload[ptr : ptr + 4])[0]
+        ptr += 4
+        for _ in range(length):
+            key, count = struct.unpack("<IQ", payload[ptr : ptr + 12])
+            counts[key] = count
+            ptr += 12
+        result.append(counts)
+
+    return chunks, result[0], result[1]
+
+
+def capture_structure(
+    id: int,
+    dm: int,
+    start: tuple[int, int, int],
+    end: tuple[int, int, int],
+) -> int:
+    return int(
+        LIB.CaptureStructure(
+            CLongLong(id),
+            CInt(dm),
+            CInt(start[0]),
+            CInt(start[1]),
+            CInt(start[2]),
+            CInt(end[0]),
+            CInt(end[1]),
+            CInt(end[2]),
+        )
+    )
+
+
+def paste_structure(
+    id: int, dm: int, x: int, y: int, z: int, structure_id: int
+) -> str:
+    return as_python_string(
+        LIB.PasteStructure(
+            CLongLong(id), CInt(dm), CInt(x), CInt(y), CInt(z), CLongLong(structure_id)
+        )
+    )
+
+
+def convert_java_world(id: int, java_dir: str) -> tuple[int, int]:
+    payload = as_python_bytes(
+        LIB.ConvertJavaWorld(CLongLong(id), as_c_string(java_dir))
+    )
+    if len(payload) == 0:
+        return -1, -1
+    return struct.unpack("<qq", payload)
+
+
+def render_map_tiles(id: int, dm: int, dir: str, levels: int) -> int:
+    return int(
+        LIB.RenderMapTiles(CLongLong(id), CInt(dm), as_c_string(dir), CInt(levels))
+    )
+
+
+def export_mesh(
+    id: int,
+    dm: int,
+    start: tuple[int, int, int],
+    end: tuple[int, int, int],
+    file_name: str,
+) -> str:
+    return as_python_string(
+        LIB.ExportMesh(
+            CLongLong(id),
+            CInt(dm),
+            CInt(start[0]),
+            CInt(start[1]),
+            CInt(start[2]),
+            CInt(end[0]),
+            CInt(end[1]),
+            CInt(end[2]),
+            as_c_string(file_name),
+        )
+    )
+
+
+def export_chunk_json(id: int, dm: int, x: int, z: int) -> bytes:
+    return as_python_bytes(
+        LIB.ExportChunkJSON(CLongLong(id), CInt(dm), CInt(x), CInt(z))
+    )
+
+
+def import_chunk_json(id: int, payload: bytes) -> str:
+    return as_python_string(LIB.ImportChunkJSON(CLongLong(id), as_c_bytes(payload)))
+
+
+def pregenerate_chunks(id: int, dm: int, x: int, z: int, radius: int) -> int:
+    return int(
+        LIB.PregenerateChunks(CLongLong(id), CInt(dm), CInt(x), CInt(z), CInt(radius))
+    )
@@ -0,0 +1,85 @@
+from .define import Biome
+from ..internal.symbol_export_biome import (
+    all_biomes as ab,
+    biome_by_id as bbi,
+    biome_by_name as bbn,
+    register_biome as rb,
+)
+
+
+def biome_by_id(biome_id: int) -> Biome | None:
+    """
+    biome_by_id returns the biome whose numeric ID is biome_id.
+
+    Args:
+        biome_id (int): The numeric ID of the biome, e.g. 1 for minecraft:plains.
+
+    Returns:
+        Biome | None: If the biome is not registered, then return None.
+                      Otherwise, return the found biome.
+    """
+    result = bbi(biome_id)
+    if result is None:
+        return None
+    return Biome(*result)
+
+
+def biome_by_name(name: str) -> Biome | None:
+    """
+    biome_by_name returns the biome whose name is name.
+
+    Args:
+        name (str): The name of the biome, which could omit the "minecraft:" prefix.
+
+    Returns:
+        Biome | None: If the biome is not registered, then return None.
+                      Otherwise, return the found biome.
+    """
+    result = bbn(name)
+    if result is None:
+        return None
+    return Biome(*result)
+
+
+def all_biomes() -> list[Biome]:
+    """
+    all_biomes returns all registered biomes,
+    which are sorted by their numeric IDs.
+
+    Returns:
+        list[Biome]: All registered biomes.
+    """
+    return [Biome(*i) for i in ab()]
+
+
+def register_biome(biome: Biome):
+    """
+    register_biome registers biome, so that it could be found by
+    its ID and its name. It is used to register the biomes that
+    are not vanilla, e.g. the custom biomes of add-ons and the
+    ones only exist in NetEase worlds.
+
+    Note that only the vanilla biomes are built in. The biomes that
+    only exist in NetEase worlds are not supported, so their IDs and
+    names must be registered by this function before they are used.
+
+    Args:
+        biome (Biome): The biome to register.
+                       Its name could omit the "minecraft:" prefix.
+
+    Raises:
+        Exception: When the ID or the name is already registered.
+    """
+    err = rb(
+        (
+            biome.id,
+            biome.name,
+            biome.temperature,
+            biome.downfall,
+            biome.grass_color,
+            biome.foliage_color,
+            biome.water_color,
+        )
+    )
+    if len(err) > 0:
+        raise Exception(err)
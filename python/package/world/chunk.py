@@ -3,25 +3,41 @@ from dataclasses import dataclass, field
 from .constant import RANGE_INVALID, RANGE_OVERWORLD
 from ..internal.symbol_export_chunk import (
     chunk_biome,
+    chunk_biome_counts,
     chunk_biomes,
     chunk_block,
+    chunk_block_counts,
+    chunk_block_light,
     chunk_blocks,
+    chunk_clone,
     chunk_compact,
+    chunk_compute_light,
     chunk_equals,
+    chunk_from_json as chunk_from_json_internal,
+    chunk_highest_block,
     chunk_highest_filled_sub_chunk,
+    chunk_highest_light_blocker,
+    chunk_highest_solid_block,
+    chunk_is_waterlogged,
+    chunk_marshal_json,
+    chunk_place_block,
+    chunk_replace_blocks,
     chunk_set_biome,
     chunk_set_biomes,
     chunk_set_block,
     chunk_set_blocks,
+    chunk_set_waterlogged,
     chunk_set_sub,
     chunk_set_sub_chunk,
+    chunk_sky_light,
     chunk_sub,
     chunk_sub_chunk,
     new_chunk as nc,
     release_chunk,
 )
-from ..world.define import QuickChunkBlocks, Range
+from ..world.define import ChunkPos, QuickChunkBlocks, Range
 from ..world.sub_chunk import SubChunk
+from ..internal.symbol_export_biome import biome_by_id, biome_by_name
 
 
 @dataclass
@@ -86,6 +102,36 @@ class Chunk(ChunkBase):
         """
         return chunk_biome(self._chunk_id, x, y, z)
 
+    def biome_name(self, x: int, y: int, z: int) -> str:
+        """biome_name returns the name of the biome at a specific column in the chunk.
+
+        Args:
+            x (int): The relative x position of this column. Must in a range of 0-15.
+            y (int): The y position of this column.
+                     Must in a range of -64~319 (Overworld), 0-127 (Nether) and 0-255 (End).
+            z (int): The relative z position of this column. Must in a range of 0-15.
+
+        Returns:
+            str: The name of the biome of this column, e.g. minecraft:plains.
+                 If the current chunk is not found or the biome is not registered,
+                 then return an empty string.
+        """
+        result = biome_by_id(self.biome(x, y, z))
+        if result is None:
+            return ""
+        return result[1]
+
+    def biome_counts(self) -> dict[int, int]:
+        """
+        biome_counts returns the amount of the
+        blocks in every biome of the whole chunk.
+
+        Returns:
+            dict[int, int]: The biome IDs and the amounts of the blocks in them.
+                            If the chunk is not found, return an empty dict.
+        """
+        return chunk_biome_counts(self._chunk_id)
+
     def biomes(self) -> QuickChunkBlocks:
         """
         biomes returns all biome IDs for each blocks in this chunk.
@@ -129,6 +175,24 @@ class Chunk(ChunkBase):
         """
         return chunk_block(self._chunk_id, x, y, z, layer)
 
+    def block_counts(self, layer: int = 0) -> dict[int, int]:
+        """
+        block_counts returns the amount of every block
+        (block runtime ID) in layer of the whole chunk.
+
+        The blocks are counted from the palette and the indices
+        of each sub chunk directly, so it is much faster than
+        counting the result of c.blocks(...) by yourself.
+
+        Args:
+            layer (int, optional): The layer of the blocks to count. Defaults to 0.
+
+        Returns:
+            dict[int, int]: The block runtime IDs and their amounts.
+                            If the chunk is not found, return an empty dict.
+        """
+        return chunk_block_counts(self._chunk_id, layer)
+
     def blocks(self, layer: int) -> QuickChunkBlocks:
         """
         blocks returns all blocks (block runtime IDs) whose in the
@@ -159,6 +223,27 @@ class Chunk(ChunkBase):
             self._chunk_range.end_range,
         )
 
+    def clone(self) -> "Chunk":
+        """
+        clone returns a deep copy of the chunk.
+
+        The sub chunks and biomes of the returned chunk
+        do not share any memory with the current one, so
+        editing one of them does not affect the other.
+
+        Returns:
+            Chunk: If the current chunk is not found, then return an invalid chunk.
+                   Otherwise, return the copy of current chunk.
+                   Note that you could use c.is_valid() to check whether the chunk is valid or not.
+        """
+        c = Chunk()
+        c._chunk_id = chunk_clone(self._chunk_id)
+        if c._chunk_id >= 0:
+            c._chunk_range = Range(
+                self._chunk_range.start_range, self._chunk_range.end_range
+            )
+        return c
+
     def compact(self):
         """
         compact compacts the chunk as much as possible,
@@ -190,6 +275,48 @@ class Chunk(ChunkBase):
         result = chunk_equals(self._chunk_id, another_chunk._chunk_id)
         return result == 1
 
+    def highest_block(self, x: int, z: int) -> int:
+        """
+        highest_block iterates from the highest non-empty sub chunk
+        downwards to find the Y value of the highest non-air block at
+        an x and z.
+
+        Args:
+            x (int): The relative x position of target column. Must in a range of 0-15.
+            z (int): The relative z position of target column. Must in a range of 0-15.
+
+        Returns:
+            int: The Y value of the highest non-air block.
+                 If no blocks are present in the column, the minimum height is returned.
+                 Additionally, if the current chunk is not found, then return -32768,
+                 which is out of the height range of any dimension (note that -1
+                 is a valid height).
+        """
+        return chunk_highest_block(self._chunk_id, x, z)
+
+    def highest_solid_block(self, x: int, z: int) -> int:
+        """
+        highest_solid_block iterates from the highest non-empty sub chunk
+        downwards to find the Y value of the highest solid block at
+        an x and z.
+
+        Air-like blocks (e.g. short grass, flowers and torches) and
+        liquids are skipped, so the result is the ground that entities
+        could stand on.
+
+        Args:
+            x (int): The relative x position of target column. Must in a range of 0-15.
+            z (int): The relative z position of target column. Must in a range of 0-15.
+
+        Returns:
+            int: The Y value of the highest solid block.
+                 If no solid blocks are present in the column, the minimum height is returned.
+                 Additionally, if the current chunk is not found, then return -32768,
+                 which is out of the height range of any dimension (note that -1
+                 is a valid height).
+        """
+        return chunk_highest_solid_block(self._chunk_id, x, z)
+
     def highest_filled_sub_chunk(self) -> int:
         """
         highest_filled_sub_chunk returns the index of
@@ -205,6 +332,178 @@ class Chunk(ChunkBase):
         """
         return chunk_highest_filled_sub_chunk(self._chunk_id)
 
+    def highest_light_blocker(self, x: int, z: int) -> int:
+        """
+        highest_light_blocker iterates from the highest non-empty
+        sub chunk downwards to find the Y value of the highest block
+        that filters light at an x and z, which is the value of the
+        heightmap used by lighting.
+
+        Blocks of all layers are checked, so the water of a
+        waterlogged block is also counted.
+
+        Args:
+            x (int): The relative x position of target column. Must in a range of 0-15.
+            z (int): The relative z position of target column. Must in a range of 0-15.
+
+        Returns:
+            int: The Y value of the highest block that filters light.
+                 If no such blocks are present in the column, the minimum height is returned.
+                 Additionally, if the current chunk is not found, then return -32768,
+                 which is out of the height range of any dimension (note that -1
+                 is a valid height).
+        """
+        return chunk_highest_light_blocker(self._chunk_id, x, z)
+
+    def compute_light(
+        self, chunk_pos: ChunkPos, neighbours: dict[ChunkPos, "Chunk"] | None = None
+    ):
+        """
+        compute_light computes the sky light and the block light of the
+        current chunk, whose position is chunk_pos. The result is kept
+        in the chunk, and could be read by sky_light, block_light and light.
+
+        Light spreads from (and through) the chunks in neighbours, so
+        the light at the border of the current chunk is the same as
+        the one computed by Minecraft. The chunks not in neighbours are
+        treated as not existing. Only the current chunk is modified,
+        and the chunks in neighbours must have the same range as it.
+
+        Note that the light is not saved with the chunk, because Minecraft
+        computes the light by itself when it loads the chunk, so it must
+        be computed again after the chunk is modified.
+
+        Args:
+            chunk_pos (ChunkPos): The position of the current chunk.
+            neighbours (dict[ChunkPos, Chunk] | None, optional):
+                The chunks around the current chunk, which are indexed by their positions.
+                Defaults to None.
+
+        Raises:
+            Exception: When failed to compute the light.
+        """
+        mapping = {}
+        for pos, c in (neighbours or {}).items():
+            mapping[(pos.x, pos.z)] = c._chunk_id
+        err = chunk_compute_light(self._chunk_id, chunk_pos.x, chunk_pos.z, mapping)
+        if len(err) > 0:
+            raise Exception(err)
+
+    def sky_light(self, x: int, y: int, z: int) -> int:
+        """
+        sky_light returns the sky light level (0 to 15) at a given x, y and z
+        in the chunk. 0 is returned if the light has not been computed,
+        see compute_light for more information.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+
+        Returns:
+            int: The sky light level, or -1 if the current chunk is not found.
+        """
+        return chunk_sky_light(self._chunk_id, x, y, z)
+
+    def block_light(self, x: int, y: int, z: int) -> int:
+        """
+        block_light returns the block light level (0 to 15) at a given x, y and z
+        in the chunk. 0 is returned if the light has not been computed,
+        see compute_light for more information.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+
+        Returns:
+            int: The block light level, or -1 if the current chunk is not found.
+        """
+        return chunk_block_light(self._chunk_id, x, y, z)
+
+    def light(self, x: int, y: int, z: int) -> int:
+        """
+        light returns the light level (0 to 15) at a given x, y and z
+        in the chunk, which is the highest one of the sky light and
+        the block light.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+
+        Returns:
+            int: The light level, or -1 if the current chunk is not found.
+        """
+        return max(self.sky_light(x, y, z), self.block_light(x, y, z))
+
+    def is_waterlogged(self, x: int, y: int, z: int) -> bool:
+        """
+        is_waterlogged reports whether the block at the given X, Y and Z
+        is waterlogged, which means there is a liquid in layer 1.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+
+        Returns:
+            bool: Return True if the block is waterlogged.
+                  Return False if not, or the current chunk is not found.
+        """
+        return chunk_is_waterlogged(self._chunk_id, x, y, z) == 1
+
+    def marshal_json(self) -> str:
+        """
+        marshal_json encodes the chunk as JSON, which holds the blocks and
+        the biomes of the chunk by their names instead of their runtime IDs
+        and numeric IDs. The result is the same for the chunks that are equal,
+        so it could be used for golden tests and diffing.
+
+        The JSON holds "range", "sub_chunks" and "biomes". sub_chunks only holds
+        the sub chunks that are not empty, where each of them holds its "y"
+        (the Y of its lowest blocks divided by 16) and its "layers". Each layer
+        holds a "palette" of {"name": ..., "states": {...}} and the "indices",
+        where the block at x, y and z (relative to the sub chunk) is
+        palette[indices[(x<<8)|(z<<4)|y]]. indices is omitted if the palette
+        only holds one block. biomes holds the biomes of every sub chunk
+        in the same way, where the palette holds the names of the biomes.
+
+        Returns:
+            str: The JSON of the chunk.
+                 If the current chunk is not found, or any block is unknown,
+                 then return an empty string.
+        """
+        return chunk_marshal_json(self._chunk_id).decode(encoding="utf-8")
+
+    def place_block(
+        self, x: int, y: int, z: int, block_runtime_id: int | numpy.uint32
+    ):
+        """
+        place_block sets the block in layer 0 at the given X, Y and Z
+        in the same way as the game places a block, and layer 1 which
+        holds the water of waterlogged blocks is kept coherent with it.
+
+        If the new block could be waterlogged, then it is waterlogged when
+        the old position is waterlogged or the old block is a water source.
+        Otherwise, the water in layer 1 at there is cleared.
+
+        Note that set_block(...) only sets the layer that passed,
+        and the other layers are kept as is.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+            block_runtime_id (int | numpy.uint32): The block runtime ID of target block will be.
+
+        Raises:
+            Exception: When failed to place block.
+        """
+        err = chunk_place_block(self._chunk_id, x, y, z, block_runtime_id)  # type: ignore
+        if len(err) > 0:
+            raise Exception(err)
+
     def range(self) -> Range:
         """Range returns the Range of the Chunk as passed to new_chunk.
 
@@ -214,6 +513,25 @@ class Chunk(ChunkBase):
         """
         return self._chunk_range
 
+    def replace_blocks(self, mapping: dict[int, int], layer: int = 0) -> bool:
+        """
+        replace_blocks replaces all the blocks in layer of this chunk
+        by mapping, which maps the old block runtime ID to the new one.
+
+        The replacement is done on the palette of each sub chunk, so it
+        is much faster than checking and setting the blocks one by one.
+        The blocks that not in mapping are kept unchanged.
+
+        Args:
+            mapping (dict[int, int]): The block runtime ID mapping from old to new.
+            layer (int, optional): The layer of the blocks to replace. Defaults to 0.
+
+        Returns:
+            bool: Return True if any block is replaced.
+                  Return False if nothing changed or the chunk is not found.
+        """
+        return chunk_replace_blocks(self._chunk_id, layer, mapping) == 1
+
     def set_biome(self, x: int, y: int, z: int, biome_id: int):
         """set_biome sets the biome ID at a specific column in the chunk.
 
@@ -231,6 +549,24 @@ class Chunk(ChunkBase):
         if len(err) > 0:
             raise Exception(err)
 
+    def set_biome_by_name(self, x: int, y: int, z: int, name: str):
+        """set_biome_by_name sets the biome at a specific column in the chunk by its name.
+
+        Args:
+            x (int): The relative x position of this column. Must in a range of 0-15.
+            y (int): The y position of this column.
+                     Must in a range of -64~319 (Overworld), 0-127 (Nether) and 0-255 (End).
+            z (int): The relative z position of this column. Must in a range of 0-15.
+            name (str): The name of the biome, which could omit the "minecraft:" prefix.
+
+        Raises:
+            Exception: When the biome is not registered or failed to set the biome.
+        """
+        result = biome_by_name(name)
+        if result is None:
+            raise Exception(f"set_biome_by_name: Biome {name} is not registered")
+        self.set_biome(x, y, z, result[0])
+
     def set_biomes(self, biome_ids: QuickChunkBlocks):
         """
         set_biomes sets the biome IDs for each block in this chunk.
@@ -300,6 +636,24 @@ class Chunk(ChunkBase):
         if len(err) > 0:
             raise Exception(err)
 
+    def set_waterlogged(self, x: int, y: int, z: int, waterlogged: bool) -> bool:
+        """
+        set_waterlogged sets water to layer 1 at the given X, Y and Z
+        if waterlogged is True, or clears layer 1 at there if not.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The y position of target block.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+            waterlogged (bool): Whether the block should be waterlogged.
+
+        Returns:
+            bool: Return False if waterlogged is True but the block in layer 0
+                  could not be waterlogged, or the current chunk is not found.
+                  Otherwise, return True.
+        """
+        return chunk_set_waterlogged(self._chunk_id, x, y, z, waterlogged) == 1
+
     def sub(self) -> list[SubChunk]:
         """
         sub returns a list of all sub chunks present in the chunk.
@@ -465,3 +819,25 @@ def new_chunk(r: Range = RANGE_OVERWORLD) -> Chunk:
     c._chunk_range = Range(start_range, end_range)
     c._chunk_id = chunk_id
     return c
+
+
+def chunk_from_json(data: str) -> Chunk:
+    """
+    chunk_from_json decodes the JSON made by Chunk.marshal_json to a new chunk.
+    The types of the block states are restored from the properties of the blocks.
+
+    Args:
+        data (str): The JSON of the chunk.
+
+    Returns:
+        Chunk: If failed to decode, then return an invalid chunk.
+               Otherwise, return the decoded chunk.
+               Note that you could use c.is_valid() to check whether the chunk is valid or not.
+    """
+    c = Chunk()
+    start_range, end_range, chunk_id = chunk_from_json_internal(
+        data.encode(encoding="utf-8")
+    )
+    c._chunk_range = Range(start_range, end_range)
+    c._chunk_id = chunk_id
+    return c
@@ -20,3 +20,14 @@ RANGE_INVALID = Range(0, -1)
 
 AIR_BLOCK_STATES = BlockStates("minecraft:air")
 AIR_BLOCK_RUNTIME_ID = state_to_runtime_id("minecraft:air", EMPTY_BLOCK_STATES)[0]
+
+STRUCTURE_VOID_RUNTIME_ID = 0xFFFFFFFF
+
+ROTATION_0 = 0
+ROTATION_90 = 1
+ROTATION_180 = 2
+ROTATION_270 = 3
+
+MIRROR_NONE = 0
+MIRROR_X = 1
+MIRROR_Z = 2
@@ -5,11 +5,14 @@ from .constant import (
     EMPTY_BLOCK_STATES,
     RANGE_OVERWORLD,
 )
-from .define import BlockStates, Range
+from .define import BlockClassification, BlockStates, Range
+from ..world.chunk import Chunk
 from ..world.sub_chunk import SubChunk, SubChunkWithIndex
 from ..internal.symbol_export_conversion import (
     runtime_id_to_state as rits,
     state_to_runtime_id as stri,
+    block_classification as bc,
+    chunk_network_payload as cnp,
     sub_chunk_network_payload as scnp,
     from_sub_chunk_network_payload as fscnp,
     sub_chunk_disk_payload as scdp,
@@ -67,6 +70,52 @@ def state_to_runtime_id(
     return block_runtime_id
 
 
+def block_classification(block_runtime_id: int | numpy.uint32) -> BlockClassification:
+    """
+    block_classification returns the physical and lighting
+    categories of the block whose runtime ID is block_runtime_id.
+
+    Args:
+        block_runtime_id (int | numpy.uint32): The runtime ID of target block.
+
+    Returns:
+        BlockClassification: If not found, return the classification of air.
+                             Otherwise, return the classification of this block.
+    """
+    solid, liquid, transparent, light_emission, light_filtering, waterloggable, _ = bc(
+        block_runtime_id  # type: ignore
+    )
+    return BlockClassification(
+        solid, liquid, transparent, light_emission, light_filtering, waterloggable
+    )
+
+
+def chunk_network_payload(
+    chunk: Chunk, block_entities: list[nbtlib.tag.Compound] = []
+) -> tuple[bytes, int]:
+    """
+    chunk_network_payload encodes chunk to the payload of a LevelChunk
+    packet, which holds the sub chunks up to the highest filled one,
+    the biomes, the border blocks and the block entities.
+
+    This is used when the sub chunk request system is not used, and
+    the block entities are encoded in network little endian NBT.
+
+    Args:
+        chunk (Chunk): The chunk want to encode.
+        block_entities (list[nbtlib.tag.Compound], optional):
+            The block entities (NBT) in this chunk.
+            Defaults to empty list.
+
+    Returns:
+        tuple[bytes, int]:
+            The payload and the sub chunk count of it, and the sub chunk count
+            must be set as the SubChunkCount field of the LevelChunk packet.
+            If the chunk is not valid, then return empty bytes and 0.
+    """
+    return cnp(chunk._chunk_id, block_entities)
+
+
 def sub_chunk_network_payload(
     sub_chunk: SubChunk, index: int, r: Range = RANGE_OVERWORLD
 ) -> bytes:
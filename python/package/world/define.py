@@ -38,6 +38,19 @@ class SubChunkPos:
     z: int = 0
 
 
+@dataclass(frozen=True)
+class BlockPos:
+    """
+    BlockPos holds the position of a block in the world.
+
+    Note that BlockPos is a hashable and cannot be further modified object.
+    """
+
+    x: int = 0
+    y: int = 0
+    z: int = 0
+
+
 @dataclass(frozen=True)
 class Range:
     """
@@ -134,6 +147,77 @@ class BlockStates:
     States: nbtlib.tag.Compound = field(default_factory=lambda: nbtlib.tag.Compound())
 
 
+@dataclass(frozen=True)
+class BlockClassification:
+    """
+    BlockClassification holds the physical and lighting categories
+    of a block, which is used by rendering, heightmap computing and
+    lighting.
+
+    The default value is the classification of air.
+
+    Note that BlockClassification is a hashable and cannot be further modified object.
+
+    Args:
+        solid (bool): Whether the block has collision, so that entities could
+                      stand on it, e.g. stone, glass and slab.
+        liquid (bool): Whether the block is water or lava.
+        transparent (bool): Whether the block does not fully occlude its neighbours
+                            when rendering, e.g. glass, leaves and stairs.
+        light_emission (int): The light level (0 to 15) that the block emits.
+        light_filtering (int): The light level (0 to 15) that the block takes from
+                               the light passing through it. Opaque blocks have 15.
+        waterloggable (bool): Whether the block could be placed in the same position
+                              with water (at layer 1).
+    """
+
+    solid: bool = False
+    liquid: bool = False
+    transparent: bool = True
+    light_emission: int = 0
+    light_filtering: int = 0
+    waterloggable: bool = False
+
+    def air_like(self) -> bool:
+        """
+        air_like reports whether the block has neither collision nor liquid,
+        e.g. air, short grass, flowers and torches. Such a block should not
+        be treated as the ground.
+
+        Returns:
+            bool: Whether the block is air-like.
+        """
+        return not self.solid and not self.liquid
+
+
+@dataclass(frozen=True)
+class Biome:
+    """
+    Biome is a biome of Bedrock Edition, which holds its
+    climate and the colors that the blocks are tinted with.
+
+    Note that Biome is a hashable and cannot be further modified object.
+
+    Args:
+        id (int): The numeric ID of the biome, which is the one stored in the chunks.
+        name (str): The name of the biome, e.g. minecraft:plains.
+        temperature (float): The temperature of the biome.
+        downfall (float): The downfall of the biome.
+        grass_color (tuple[int, int, int]): The RGB color that grass blocks, short grass
+                                            and other plants are tinted with.
+        foliage_color (tuple[int, int, int]): The RGB color that leaves and vines are tinted with.
+        water_color (tuple[int, int, int]): The RGB color that water is tinted with.
+    """
+
+    id: int = 0
+    name: str = ""
+    temperature: float = 0
+    downfall: float = 0
+    grass_color: tuple[int, int, int] = (0, 0, 0)
+    foliage_color: tuple[int, int, int] = (0, 0, 0)
+    water_color: tuple[int, int, int] = (0, 0, 0)
+
+
 # ptr = ((y >> 4) - (self.start_range >> 4)) << 12
 # offset = x * 256 + (y & 15) * 16 + z
 @dataclass
@@ -307,3 +391,40 @@ class HashWithPosY:
 
     Hash: int = 0
     PosY: int = 0
+
+
+@dataclass
+class BlockStatistics:
+    """
+    BlockStatistics holds the amount of every block and biome in some chunks.
+
+    Args:
+        chunks (int): The count of the chunks that are counted.
+        blocks (dict[int, int]): The block runtime IDs and their amounts.
+        biomes (dict[int, int]): The biome IDs and the amounts of the blocks in them.
+    """
+
+    chunks: int = 0
+    blocks: dict[int, int] = field(default_factory=lambda: {})
+    biomes: dict[int, int] = field(default_factory=lambda: {})
+
+
+def chunk_pos_area(start: ChunkPos, end: ChunkPos) -> list[ChunkPos]:
+    """
+    chunk_pos_area returns the positions of all the chunks
+    in the area from start to end (both inclusive).
+
+    The positions are sorted by X, and then by Z.
+
+    Args:
+        start (ChunkPos): The one corner of the area.
+        end (ChunkPos): The another corner of the area.
+
+    Returns:
+        list[ChunkPos]: The positions of the chunks in the area.
+    """
+    return [
+        ChunkPos(x, z)
+        for x in range(min(start.x, end.x), max(start.x, end.x) + 1)
+        for z in range(min(start.z, end.z), max(start.z, end.z) + 1)
+    ]
@@ -0,0 +1,302 @@
+import numpy
+from .define import BlockPos
+from ..internal.symbol_export_structure import (
+    from_mcstructure as fms,
+    from_schem as fsch,
+    from_schematic as fscht,
+    new_structure as ns,
+    release_structure,
+    structure_block,
+    structure_mcstructure,
+    structure_mirror,
+    structure_origin,
+    structure_rotate,
+    structure_set_block,
+    structure_set_origin,
+    structure_size,
+)
+
+
+class StructureBase:
+    """StructureBase is the base implement of a Minecraft structure."""
+
+    _structure_id: int
+
+    def __init__(self):
+        self._structure_id = -1
+
+    def __del__(self):
+        if self._structure_id >= 0 and release_structure is not None:
+            release_structure(self._structure_id)
+
+    def is_valid(self) -> bool:
+        """
+        is_valid check current structure is valid or not.
+
+        If not valid, it means the structure actually not exist,
+        not only Python but also in Go.
+
+        Try to use an invalid structure is not allowed,
+        and any operation will be terminated.
+
+        Returns:
+            bool: Whether the structure is valid or not.
+        """
+        return self._structure_id >= 0
+
+
+class Structure(StructureBase):
+    """
+    Structure is a cuboid of blocks, together with
+    the block entities and the entities in it.
+
+    Each block has two layers, where layer 0 holds the
+    block itself and layer 1 holds the liquid in it.
+
+    All the positions in a structure are relative
+    to its lowest corner.
+    """
+
+    def __init__(self):
+        super().__init__()
+
+    def size(self) -> BlockPos:
+        """size returns the size of the structure on the X, Y and Z axis.
+
+        Returns:
+            BlockPos: The size of the structure.
+                      If the current structure is not found, then return BlockPos(0, 0, 0).
+        """
+        result = structure_size(self._structure_id)
+        if result is None:
+            return BlockPos()
+        return BlockPos(result[0], result[1], result[2])
+
+    def origin(self) -> BlockPos:
+        """
+        origin returns the position in the world of the
+        lowest corner of the structure when it is captured.
+
+        Returns:
+            BlockPos: The origin of the structure.
+                      If the current structure is not found, then return BlockPos(0, 0, 0).
+        """
+        result = structure_origin(self._structure_id)
+        if result is None:
+            return BlockPos()
+        return BlockPos(result[0], result[1], result[2])
+
+    def set_origin(self, origin: BlockPos):
+        """
+        set_origin sets the position in the world
+        of the lowest corner of the structure.
+
+        It is only used as the metadata, and the positions of
+        the block entities and the entities are written to
+        .mcstructure files as the positions based on it.
+
+        Args:
+            origin (BlockPos): The new origin of the structure.
+
+        Raises:
+            Exception: When failed to set origin.
+        """
+        err = structure_set_origin(self._structure_id, origin.x, origin.y, origin.z)
+        if len(err) > 0:
+            raise Exception(err)
+
+    def block(self, x: int, y: int, z: int, layer: int = 0) -> int:
+        """
+        block returns the runtime ID of the block
+        located at the given relative X, Y and Z.
+
+        Args:
+            x (int): The relative x position of target block.
+            y (int): The relative y position of target block.
+            z (int): The relative z position of target block.
+            layer (int, optional): The layer that the target block is in. Must be 0 or 1. Defaults to 0.
+
+        Returns:
+            int: Return the block runtime ID of target block.
+                 If the current structure is not found, then return -1.
+                 If there is no block at the position, or the position is out of the structure,
+                 then return STRUCTURE_VOID_RUNTIME_ID.
+        """
+        return structure_block(self._structure_id, x, y, z, layer)
+
+    def set_block(
+        self,
+        x: int,
+        y: int,
+        z: int,
+        block_runtime_id: int | numpy.uint32,
+        layer: int = 0,
+    ):
+        """
+        set_block sets the given block runtime ID
+        at the given relative X, Y and Z.
+
+        Use STRUCTURE_VOID_RUNTIME_ID to mark there is no block
+        at the position, so the block in the world is not
+        changed when the structure is pasted.
+
+        Args:
+            x (int): The relative x position of target block.
+            y (int): The relative y position of target block.
+            z (int): The relative z position of target block.
+            block_runtime_id (int | numpy.uint32): The block runtime ID of target block will be.
+            layer (int, optional): The layer that the target block is in. Must be 0 or 1. Defaults to 0.
+
+        Raises:
+            Exception: When failed to set block.
+        """
+        err = structure_set_block(
+            self._structure_id, x, y, z, layer, int(block_runtime_id)
+        )
+        if len(err) > 0:
+            raise Exception(err)
+
+    def rotate(self, rotation: int) -> "Structure":
+        """
+        rotate returns a new structure that is the current
+        one rotated clockwise (viewed from above) by rotation.
+
+        The block states, the block entities and the entities
+        are rotated as well, and the current one is not changed.
+
+        Args:
+            rotation (int): One of ROTATION_0, ROTATION_90, ROTATION_180 and ROTATION_270.
+
+        Returns:
+            Structure: The rotated structure.
+                       If the current structure is not found, then return an invalid structure.
+        """
+        s = Structure()
+        s._structure_id = structure_rotate(self._structure_id, rotation)
+        return s
+
+    def mirror(self, mirror: int) -> "Structure":
+        """
+        mirror returns a new structure that is the
+        current one mirrored by mirror.
+
+        The block states, the block entities and the entities
+        are mirrored as well, and the current one is not changed.
+
+        Args:
+            mirror (int): One of MIRROR_NONE, MIRROR_X and MIRROR_Z.
+
+        Returns:
+            Structure: The mirrored structure.
+                       If the current structure is not found, then return an invalid structure.
+        """
+        s = Structure()
+        s._structure_id = structure_mirror(self._structure_id, mirror)
+        return s
+
+    def mcstructure(self) -> bytes:
+        """
+        mcstructure encodes the structure as a .mcstructure file,
+        which could be loaded by the structure block of the game.
+
+        Raises:
+            Exception: When failed to encode the structure.
+
+        Returns:
+            bytes: The content of the .mcstructure file.
+        """
+        result = structure_mcstructure(self._structure_id)
+        if len(result) == 0:
+            raise Exception("mcstructure: Failed to encode the structure")
+        return result
+
+
+def new_structure(size: BlockPos) -> Structure:
+    """
+    new_structure creates a new structure whose size is size.
+
+    All the blocks in layer 0 are air, and
+    the ones in layer 1 are STRUCTURE_VOID_RUNTIME_ID.
+
+    Args:
+        size (BlockPos): The size of the structure on the X, Y and Z axis.
+
+    Returns:
+        Structure: If any axis of size is negative, or the volume is larger than 2**27
+                   (134217728 blocks), then return an invalid structure.
+                   Otherwise, return the new structure.
+                   Note that you could use s.is_valid() to check whether the structure is valid or not.
+    """
+    s = Structure()
+    s._structure_id = ns(size.x, size.y, size.z)
+    return s
+
+
+def from_mcstructure(payload: bytes) -> Structure:
+    """
+    from_mcstructure decodes a structure from the
+    content of a .mcstructure file.
+
+    The blocks in the file are upgraded to the current version,
+    and both layers and the block entities are kept.
+
+    Args:
+        payload (bytes): The content of the .mcstructure file.
+
+    Returns:
+        Structure: If the payload is not a valid .mcstructure file, then return an invalid structure.
+                   Otherwise, return the decoded structure.
+                   Note that you could use s.is_valid() to check whether the structure is valid or not.
+    """
+    s = Structure()
+    s._structure_id = fms(payload)
+    return s
+
+
+def from_schem(payload: bytes) -> Structure:
+    """
+    from_schem decodes a structure from the content of a
+    Sponge schematic (.schem) file, whose version is 1, 2 or 3.
+
+    The Java Edition blocks are converted to Bedrock Edition, and
+    the blocks that could not be converted become minecraft:unknown.
+    The waterlogged blocks are placed with water in layer 1.
+
+    Note that the block entities and the entities are not imported.
+
+    Args:
+        payload (bytes): The content of the .schem file, which could be compressed by gzip or not.
+
+    Returns:
+        Structure: If the payload is not a valid .schem file, then return an invalid structure.
+                   Otherwise, return the decoded structure.
+                   Note that you could use s.is_valid() to check whether the structure is valid or not.
+    """
+    s = Structure()
+    s._structure_id = fsch(payload)
+    return s
+
+
+def from_schematic(payload: bytes) -> Structure:
+    """
+    from_schematic decodes a structure from the content
+    of a MCEdit schematic (.schematic) file.
+
+    The legacy numeric IDs and metadata of Java Edition (or Bedrock
+    Edition if the materials is "Pocket") are converted to the
+    current blocks, and the blocks that could not be converted
+    become minecraft:unknown.
+
+    Note that the block entities and the entities are not imported.
+
+    Args:
+        payload (bytes): The content of the .schematic file, which could be compressed by gzip or not.
+
+    Returns:
+        Structure: If the payload is not a valid .schematic file, then return an invalid structure.
+                   Otherwise, return the decoded structure.
+                   Note that you could use s.is_valid() to check whether the structure is valid or not.
+    """
+    s = Structure()
+    s._structure_id = fscht(payload)
+    return s
@@ -5,11 +5,17 @@ from ..internal.symbol_export_sub_chunk import (
     new_sub_chunk as nsc,
     release_sub_chunk,
     sub_chunk_block,
+    sub_chunk_block_counts,
     sub_chunk_blocks,
+    sub_chunk_clone,
     sub_chunk_empty,
     sub_chunk_equals,
+    sub_chunk_is_waterlogged,
+    sub_chunk_place_block,
+    sub_chunk_replace_blocks,
     sub_chunk_set_block,
     sub_chunk_set_blocks,
+    sub_chunk_set_waterlogged,
 )
 
 
@@ -52,6 +58,21 @@ class SubChunk(SubChunkBase):
     def __init__(self):
         super().__init__()
 
+    def clone(self) -> "SubChunk":
+        """
+        clone returns a deep copy of the sub chunk,
+        and editing the returned sub chunk does not
+        affect the current one.
+
+        Returns:
+            SubChunk: If the current sub chunk is not found, then return an invalid sub chunk.
+                      Otherwise, return the copy of current sub chunk.
+                      Note that you could use s.is_valid() to check whether the sub chunk is valid or not.
+        """
+        s = SubChunk()
+        s._sub_chunk_id = sub_chunk_clone(self._sub_chunk_id)
+        return s
+
     def equals(self, another_sub_chunk: SubChunkBase) -> bool:
         """Equals returns if the sub chunk passed is equal to the current one.
 
@@ -100,6 +121,23 @@ class SubChunk(SubChunkBase):
         """
         return sub_chunk_block(self._sub_chunk_id, x, y, z, layer)
 
+    def block_counts(self, layer: int = 0) -> dict[int, int]:
+        """
+        block_counts returns the amount of every block
+        (block runtime ID) in layer of this sub chunk.
+
+        If the layer is not exist, then all the
+        4096 blocks are counted as air.
+
+        Args:
+            layer (int, optional): The layer of the blocks to count. Defaults to 0.
+
+        Returns:
+            dict[int, int]: The block runtime IDs and their amounts.
+                            If the sub chunk is not found, return an empty dict.
+        """
+        return sub_chunk_block_counts(self._sub_chunk_id, layer)
+
     def blocks(self, layer: int) -> QuickSubChunkBlocks:
         """
         blocks all blocks (block runtime IDs) whose in layer.
@@ -121,6 +159,69 @@ class SubChunk(SubChunkBase):
         """
         return QuickSubChunkBlocks(sub_chunk_blocks(self._sub_chunk_id, layer))
 
+    def is_waterlogged(self, x: int, y: int, z: int) -> bool:
+        """
+        is_waterlogged reports whether the block at the given X, Y and Z
+        is waterlogged, which means there is a liquid in layer 1.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The relative y position of target block. Must in a range of 0-15.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+
+        Returns:
+            bool: Return True if the block is waterlogged.
+                  Return False if not, or the current sub chunk is not found.
+        """
+        return sub_chunk_is_waterlogged(self._sub_chunk_id, x, y, z) == 1
+
+    def place_block(
+        self, x: int, y: int, z: int, block_runtime_id: int | numpy.uint32
+    ):
+        """
+        place_block sets the block in layer 0 at the given X, Y and Z
+        in the same way as the game places a block, and layer 1 which
+        holds the water of waterlogged blocks is kept coherent with it.
+
+        If the new block could be waterlogged, then it is waterlogged when
+        the old position is waterlogged or the old block is a water source.
+        Otherwise, the water in layer 1 at there is cleared.
+
+        Note that set_block(...) only sets the layer that passed,
+        and the other layers are kept as is.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The relative y position of target block. Must in a range of 0-15.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+            block_runtime_id (int | numpy.uint32): The block runtime ID of target block will be.
+
+        Raises:
+            Exception: When failed to place block.
+        """
+        err = sub_chunk_place_block(self._sub_chunk_id, x, y, z, block_runtime_id)  # type: ignore
+        if len(err) > 0:
+            raise Exception(err)
+
+    def replace_blocks(self, mapping: dict[int, int], layer: int = 0) -> bool:
+        """
+        replace_blocks replaces all the blocks in layer of this sub chunk
+        by mapping, which maps the old block runtime ID to the new one.
+
+        The replacement is done on the palette of the sub chunk, so it
+        is much faster than checking and setting the blocks one by one.
+        The blocks that not in mapping are kept unchanged.
+
+        Args:
+            mapping (dict[int, int]): The block runtime ID mapping from old to new.
+            layer (int, optional): The layer of the blocks to replace. Defaults to 0.
+
+        Returns:
+            bool: Return True if any block is replaced.
+                  Return False if nothing changed or the sub chunk is not found.
+        """
+        return sub_chunk_replace_blocks(self._sub_chunk_id, layer, mapping) == 1
+
     def set_block(
         self, x: int, y: int, z: int, layer: int, block_runtime_id: int | numpy.uint32
     ):
@@ -165,6 +266,24 @@ class SubChunk(SubChunkBase):
         if len(err) > 0:
             raise Exception(err)
 
+    def set_waterlogged(self, x: int, y: int, z: int, waterlogged: bool) -> bool:
+        """
+        set_waterlogged sets water to layer 1 at the given X, Y and Z
+        if waterlogged is True, or clears layer 1 at there if not.
+
+        Args:
+            x (int): The relative x position of target block. Must in a range of 0-15.
+            y (int): The relative y position of target block. Must in a range of 0-15.
+            z (int): The relative z position of target block. Must in a range of 0-15.
+            waterlogged (bool): Whether the block should be waterlogged.
+
+        Returns:
+            bool: Return False if waterlogged is True but the block in layer 0
+                  could not be waterlogged, or the current sub chunk is not found.
+                  Otherwise, return True.
+        """
+        return sub_chunk_set_waterlogged(self._sub_chunk_id, x, y, z, waterlogged) == 1
+
 
 @dataclass
 class SubChunkWithIndex:
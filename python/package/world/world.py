@@ -1,8 +1,17 @@
 import nbtlib
 from .constant import DIMENSION_OVERWORLD
 from ..world.chunk import Chunk
+from ..world.structure import Structure
 from ..world.sub_chunk import SubChunk
 from ..internal.symbol_export_world import (
+    apply_delta_updates,
+    block_statistics,
+    capture_structure,
+    chunk_positions,
+    convert_java_world,
+    export_chunk_json,
+    export_mesh,
+    import_chunk_json,
     load_biomes,
     load_chunk,
     load_chunk_payload_only,
@@ -15,7 +24,11 @@ from ..internal.symbol_export_world import (
     load_sub_chunk_blob_hash,
     load_time_stamp,
     new_bedrock_world as nbw,
+    paste_structure,
+    pregenerate_chunks,
     release_bedrock_world,
+    render_map_tiles,
+    replace_blocks,
     save_biomes,
     save_chunk,
     save_chunk_payload_only,
@@ -37,7 +50,15 @@ from ..internal.symbol_export_world_underlying import (
     db_has,
     db_put,
 )
-from ..world.define import ChunkPos, Dimension, HashWithPosY, Range, SubChunkPos
+from ..world.define import (
+    BlockPos,
+    BlockStatistics,
+    ChunkPos,
+    Dimension,
+    HashWithPosY,
+    Range,
+    SubChunkPos,
+)
 from ..world.level_dat import LevelDat
 
 
@@ -469,6 +490,29 @@ class World(WorldBase):
         if len(err) > 0:
             raise Exception(err)
 
+    def apply_delta_updates(
+        self, chunk_pos: ChunkPos, dm: Dimension = DIMENSION_OVERWORLD
+    ):
+        """
+        apply_delta_updates folds the pending delta updates of
+        a chunk into the chunk, and then clears them.
+
+        The delta update payload must be in the format that
+        defined by the Go function world.EncodeDeltaUpdate,
+        and the time stamp of the chunk is set to the delta
+        update time stamp.
+
+        Args:
+            chunk_pos (ChunkPos): The chunk pos of this chunk.
+            dm (Dimension, optional): The dimension of this chunk. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to apply delta updates.
+        """
+        err = apply_delta_updates(self._world_id, dm.dm, chunk_pos.x, chunk_pos.z)
+        if len(err) > 0:
+            raise Exception(err)
+
     def load_time_stamp(
         self, chunk_pos: ChunkPos, dm: Dimension = DIMENSION_OVERWORLD
     ) -> int:
@@ -687,6 +731,361 @@ class World(WorldBase):
             raise Exception(err)
 
 
+    def chunk_positions(self, dm: Dimension = DIMENSION_OVERWORLD) -> list[ChunkPos]:
+        """
+        chunk_positions returns the positions of all
+        the chunks that saved in the dm dimension.
+
+        Args:
+            dm (Dimension, optional): The dimension of the chunks. Defaults to DIMENSION_OVERWORLD.
+
+        Returns:
+            list[ChunkPos]: The positions of the chunks.
+                            If the world is not found or failed to read the database, return an empty list.
+        """
+        return [ChunkPos(x, z) for x, z in chunk_positions(self._world_id, dm.dm)]
+
+    def replace_blocks(
+        self,
+        mapping: dict[int, int],
+        layer: int = 0,
+        dm: Dimension = DIMENSION_OVERWORLD,
+    ) -> int:
+        """
+        replace_blocks replaces all the blocks in layer of all
+        the chunks that saved in the dm dimension by mapping,
+        which maps the old block runtime ID to the new one.
+
+        The sub chunks are read from and written back to the
+        database one by one without loading the whole chunks,
+        and only the modified sub chunks are written back.
+
+        Args:
+            mapping (dict[int, int]): The block runtime ID mapping from old to new.
+            layer (int, optional): The layer of the blocks to replace. Defaults to 0.
+            dm (Dimension, optional): The dimension to replace blocks in. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to replace blocks.
+
+        Returns:
+            int: The count of the sub chunks that modified.
+        """
+        modified = replace_blocks(self._world_id, dm.dm, layer, mapping)
+        if modified < 0:
+            raise Exception("replace_blocks: Failed to replace blocks")
+        return modified
+
+
+    def block_statistics(
+        self,
+        layer: int = 0,
+        dm: Dimension = DIMENSION_OVERWORLD,
+        positions: list[ChunkPos] | None = None,
+    ) -> BlockStatistics:
+        """
+        block_statistics counts every block in layer and every biome
+        of the chunks in the dm dimension whose positions are in positions.
+
+        The chunks are counted one by one in the Go side, and the blocks of
+        them are never sent to Python, so it is much faster than loading the
+        chunks and counting them by yourself.
+
+        Args:
+            layer (int, optional): The layer of the blocks to count. Defaults to 0.
+            dm (Dimension, optional): The dimension of the chunks. Defaults to DIMENSION_OVERWORLD.
+            positions (list[ChunkPos] | None, optional):
+                The positions of the chunks to count, and the chunks that are not exist are skipped.
+                Use chunk_pos_area to count the chunks in a region.
+                If None or an empty list, then all the chunks in the dm dimension are counted.
+                Defaults to None.
+
+        Raises:
+            Exception: When failed to count the blocks.
+
+        Returns:
+            BlockStatistics: The amount of every block and biome in these chunks.
+        """
+        result = block_statistics(
+            self._world_id,
+            dm.dm,
+            layer,
+            None if positions is None else [(pos.x, pos.z) for pos in positions],
+        )
+        if result is None:
+            raise Exception("block_statistics: Failed to count blocks")
+        return BlockStatistics(result[0], result[1], result[2])
+
+    def capture_structure(
+        self,
+        start: BlockPos,
+        end: BlockPos,
+        dm: Dimension = DIMENSION_OVERWORLD,
+    ) -> Structure:
+        """
+        capture_structure captures the cuboid between start and
+        end (both inclusive) in dm dimension as a structure,
+        whose origin is the lowest corner of the cuboid.
+
+        Both layers of the blocks and the block entities are captured,
+        where the air in layer 1 is captured as STRUCTURE_VOID_RUNTIME_ID.
+        The entities are not captured.
+
+        Args:
+            start (BlockPos): One corner of the cuboid.
+            end (BlockPos): The opposite corner of the cuboid.
+            dm (Dimension, optional): The dimension to capture from. Defaults to DIMENSION_OVERWORLD.
+
+        Returns:
+            Structure: If the current world is not found or failed to capture, then return an invalid structure.
+                       Otherwise, return the captured structure.
+                       Note that you could use s.is_valid() to check whether the structure is valid or not.
+        """
+        s = Structure()
+        s._structure_id = capture_structure(
+            self._world_id,
+            dm.dm,
+            (start.x, start.y, start.z),
+            (end.x, end.y, end.z),
+        )
+        return s
+
+    def paste_structure(
+        self,
+        origin: BlockPos,
+        structure: Structure,
+        dm: Dimension = DIMENSION_OVERWORLD,
+    ):
+        """
+        paste_structure pastes structure into dm dimension, and
+        the lowest corner of the structure is placed at origin.
+
+        The positions whose block in layer 0 is STRUCTURE_VOID_RUNTIME_ID
+        are not changed. For the others, both layers and the block entities
+        are replaced by the ones of the structure.
+
+        Note that the entities of the structure are not pasted,
+        and the blob hashes of the modified chunks are not updated.
+
+        Args:
+            origin (BlockPos): The position to place the lowest corner of the structure.
+            structure (Structure): The structure to paste.
+            dm (Dimension, optional): The dimension to paste into. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to paste the structure.
+        """
+        err = paste_structure(
+            self._world_id,
+            dm.dm,
+            origin.x,
+            origin.y,
+            origin.z,
+            structure._structure_id,
+        )
+        if len(err) > 0:
+            raise Exception(err)
+
+    def convert_java_world(self, java_dir: str) -> tuple[int, int]:
+        """
+        convert_java_world converts the Java Edition world at java_dir,
+        which is the folder holds the level.dat, and writes the chunks
+        into the current world. The overworld, the nether and the end
+        are converted if the region files of them are exist.
+
+        Only the chunks that saved by Minecraft 1.18 or later are supported.
+        The chunks that still saved in the older format (the ones that hold
+        a "Level" tag, or whose DataVersion is lower than 2860) are skipped
+        and counted instead of failing the whole conversion, because the
+        upgraded worlds usually still hold them in the areas that nobody
+        visited since then.
+
+        The blocks, the biomes and the block entities of the chests, the signs
+        and the spawners are converted, and the blocks that could not be
+        converted become "minecraft:unknown". The waterlogged blocks are
+        converted to the blocks with water in layer 1.
+
+        Note that the level.dat of the current world is not changed,
+        and the blob hashes of the written chunks are not updated.
+
+        Args:
+            java_dir (str): The path of the Java Edition world.
+
+        Raises:
+            Exception: When failed to convert the world.
+
+        Returns:
+            tuple[int, int]:
+                The count of the chunks that written, and the count of
+                the chunks that skipped because they are saved in the
+                format before Minecraft 1.18.
+        """
+        chunks, skipped = convert_java_world(self._world_id, java_dir)
+        if chunks < 0:
+            raise Exception(
+                "convert_java_world: Failed to convert the Java Edition world"
+            )
+        return chunks, skipped
+
+    def render_map_tiles(
+        self, dir: str, levels: int = 5, dm: Dimension = DIMENSION_OVERWORLD
+    ) -> int:
+        """
+        render_map_tiles renders the top-down map of all the chunks in dm dimension,
+        and writes it as the tiled PNG files at dir/<level>/<x>/<z>.png.
+
+        Each block column becomes a pixel, whose color comes from the highest
+        block that shown on the map, tinted by the biome (for grass, foliage
+        and water) and shaded by the height difference to the column at the north.
+        The world is only read, and it is fully done offline.
+
+        The tiles are 256x256 pixels. The tiles of level 0 are rendered with 1
+        pixel per block, so the tile at x and z holds the chunks from (x*16, z*16)
+        to (x*16+15, z*16+15). Each tile of level n is the 2x2 tiles of level n-1
+        that scaled down to a half. Note that level 0 is the most detailed one,
+        so the map viewers should number the zoom levels reversely,
+        e.g. enable zoomReverse of Leaflet.
+
+        Args:
+            dir (str): The folder to write the tiles into.
+            levels (int, optional): The count of the zoom levels. Defaults to 5.
+            dm (Dimension, optional): The dimension to render. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to render the map.
+
+        Returns:
+            int: The count of the tiles that written.
+        """
+        tiles = render_map_tiles(self._world_id, dm.dm, dir, levels)
+        if tiles < 0:
+            raise Exception("render_map_tiles: Failed to render the map")
+        return tiles
+
+    def export_mesh(
+        self,
+        start: BlockPos,
+        end: BlockPos,
+        file_name: str,
+        dm: Dimension = DIMENSION_OVERWORLD,
+    ):
+        """
+        export_mesh exports the blocks in the cuboid between start and end
+        (both inclusive) in dm dimension to a 3D mesh file, which could be
+        imported into the modelling tools, e.g. Blender.
+
+        The format is decided by the extension of file_name. For ".obj",
+        a Wavefront OBJ file and an MTL file with the same name are written.
+        For ".glb", a binary glTF file is written.
+
+        Each block is a unit cube whatever its shape is, and only the solid
+        blocks and the liquids are exported. The faces between two opaque blocks
+        or two same blocks are culled, and the faces are grouped by the materials,
+        which are one for each block (e.g. minecraft:stone) and colored by the map
+        color of the block. The lowest corner of the cuboid is at the origin of
+        the mesh, and Y is up.
+
+        Args:
+            start (BlockPos): One corner of the cuboid.
+            end (BlockPos): The opposite corner of the cuboid.
+            file_name (str): The path of the file to write, which ends with .obj or .glb.
+            dm (Dimension, optional): The dimension to export from. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to export the mesh.
+        """
+        err = export_mesh(
+            self._world_id,
+            dm.dm,
+            (start.x, start.y, start.z),
+            (end.x, end.y, end.z),
+            file_name,
+        )
+        if len(err) > 0:
+            raise Exception(err)
+
+    def export_chunk_json(
+        self, chunk_pos: ChunkPos, dm: Dimension = DIMENSION_OVERWORLD
+    ) -> str:
+        """
+        export_chunk_json exports the chunk at chunk_pos in dm dimension as JSON,
+        which holds the "dimension", the "position", the "chunk", the
+        "block_entities" and the "entities" of the chunk.
+
+        chunk is the same as the one returned by Chunk.marshal_json.
+        Each block entity and entity is written as stringified NBT (SNBT),
+        so that the types of the values are kept, which could be parsed by
+        nbtlib.parse_nbt.
+
+        Args:
+            chunk_pos (ChunkPos): The chunk pos of this chunk.
+            dm (Dimension, optional): The dimension of this chunk. Defaults to DIMENSION_OVERWORLD.
+
+        Returns:
+            str: The JSON of the chunk.
+                 If meet error or the chunk is not exist, then return an empty string.
+        """
+        payload = export_chunk_json(self._world_id, dm.dm, chunk_pos.x, chunk_pos.z)
+        return payload.decode(encoding="utf-8")
+
+    def import_chunk_json(self, data: str):
+        """
+        import_chunk_json imports the chunk exported by export_chunk_json,
+        which is saved to the dimension and the position in data.
+        The chunk, its block entities and its entities are replaced.
+
+        The range of the chunk must be the same as the one of the dimension,
+        and each entity must hold a "UniqueID" of long.
+
+        Args:
+            data (str): The JSON of the chunk.
+
+        Raises:
+            Exception: When failed to import the chunk.
+        """
+        err = import_chunk_json(self._world_id, data.encode(encoding="utf-8"))
+        if len(err) > 0:
+            raise Exception(err)
+
+    def pregenerate_chunks(
+        self, center: ChunkPos, radius: int, dm: Dimension = DIMENSION_OVERWORLD
+    ) -> int:
+        """
+        pregenerate_chunks generates the chunks around center in dm dimension,
+        which are the chunks from (center.x-radius, center.z-radius) to
+        (center.x+radius, center.z+radius), and saves them to the current world.
+        The chunks that are already exist are skipped.
+
+        The terrain is decided by the Generator of the level.dat.
+        The infinite worlds (Generator is 1) are generated by noises
+        seeded by the RandomSeed of the level.dat, which holds the seas,
+        the mountains, the biomes, the caves, the ores and the trees.
+        The terrain does not match vanilla, but the same seed always
+        generates the same chunks. The superflat worlds (Generator is 2)
+        are generated by the FlatWorldLayers of the level.dat, and the
+        void worlds (Generator is 5) only hold air. Note that the new
+        worlds are superflat ones by default.
+
+        Args:
+            center (ChunkPos): The chunk pos of the center chunk.
+            radius (int): The radius in chunks, which must not be negative.
+            dm (Dimension, optional): The dimension to generate. Defaults to DIMENSION_OVERWORLD.
+
+        Raises:
+            Exception: When failed to generate the chunks, e.g. the generator
+                       of the level.dat is not supported.
+
+        Returns:
+            int: The count of the chunks that generated.
+        """
+        generated = pregenerate_chunks(
+            self._world_id, dm.dm, center.x, center.z, radius
+        )
+        if generated < 0:
+            raise Exception("pregenerate_chunks: Failed to generate the chunks")
+        return generated
+
+
 def new_world(dir: str) -> World:
     """
     new_world creates a new provider reading and
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/TriM-Organization/bedrock-world-operator/biome"
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+)
+
+// plainsID is the numeric ID of plains, whose colors are used by the unknown biomes.
+const plainsID = 1
+
+// biomeOf returns the biome whose numeric ID is id. The unknown biomes, e.g. the
+// custom ones that are not registered, use the colors of plains.
+func biomeOf(id uint32) biome.Biome {
+	if b, ok := biome.ByID(id); ok {
+		return b
+	}
+	b, _ := biome.ByID(plainsID)
+	return b
+}
+
+// biomeTint returns the biome color that the blocks tinted with kind use in b.
+func biomeTint(b biome.Biome, kind block.MapTint) color.RGBA {
+	switch kind {
+	case block.MapTintGrass:
+		return b.GrassColor
+	case block.MapTintFoliage:
+		return b.FoliageColor
+	case block.MapTintWater:
+		return b.WaterColor
+	}
+	return color.RGBA{A: 0xff}
+}
@@ -0,0 +1,191 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/world"
+)
+
+// The brightness of the columns that are higher than, as high as and lower
+// than the column at their north, which is the same as the maps of Java Edition.
+const (
+	brightnessHigher = 255
+	brightnessEqual  = 220
+	brightnessLower  = 180
+)
+
+// Renderer renders the top-down maps of a dimension of a world, where each block column becomes a pixel.
+// The color of a pixel is the map color (see block.MapColorOf) of the highest block that is shown on the
+// map, tinted by the biome of that block, and shaded by comparing its height with the column at its north.
+// The water is blended with the ground below it by its depth. The columns of the missing chunks are
+// transparent.
+//
+// A Renderer only reads the world, and it never changes anything of the world.
+type Renderer struct {
+	w  world.World
+	dm define.Dimension
+}
+
+// NewRenderer creates a new Renderer that renders dm dimension of w.
+func NewRenderer(w world.World, dm define.Dimension) *Renderer {
+	return &Renderer{w: w, dm: dm}
+}
+
+// column is the result of rendering a block column.
+type column struct {
+	// color is the color before shaded, and it is transparent if nothing is shown.
+	color color.RGBA
+	// height is the Y of the block that color comes from.
+	height int16
+	// water is true if the column is covered by water, which is not shaded by height.
+	water bool
+}
+
+// RenderChunk renders the chunk at position to a 16x16 image.
+func (r *Renderer) RenderChunk(position define.ChunkPos) (*image.RGBA, error) {
+	img, err := r.RenderArea(position, 1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("RenderChunk: %v", err)
+	}
+	return img, nil
+}
+
+// RenderRegion renders the 32x32 chunks from (x*32, z*32) to (x*32+31, z*32+31), which is the same area as a
+// region file of Java Edition, to a 512x512 image.
+func (r *Renderer) RenderRegion(x, z int32) (*image.RGBA, error) {
+	img, err := r.RenderArea(define.ChunkPos{x << 5, z << 5}, 32, 32)
+	if err != nil {
+		return nil, fmt.Errorf("RenderRegion: %v", err)
+	}
+	return img, nil
+}
+
+// RenderArea renders the width x length chunks whose lowest corner is the chunk at start to an image, whose
+// width is width*16 and height is length*16. The pixel at (0, 0) is the north-west corner of start, and the
+// X and Z of the world grow to the right and the bottom of the image.
+func (r *Renderer) RenderArea(start define.ChunkPos, width, length int) (*image.RGBA, error) {
+	if width <= 0 || length <= 0 {
+		return nil, fmt.Errorf("RenderArea: invalid size %dx%d", width, length)
+	}
+
+	// The columns are indexed by (z+1)*pixelWidth+x, where the extra row
+	// at the top is the south edge of the chunks at the north of the area,
+	// which is used to shade the first row.
+	pixelWidth, pixelLength := width<<4, length<<4
+	columns := make([]column, pixelWidth*(pixelLength+1))
+	rendered := make([]bool, pixelWidth*(pixelLength+1))
+
+	for chunkZ := -1; chunkZ < length; chunkZ++ {
+		for chunkX := range width {
+			position := define.ChunkPos{start[0] + int32(chunkX), start[1] + int32(chunkZ)}
+			c, exists, err := r.w.LoadChunk(r.dm, position)
+			if err != nil {
+				return nil, fmt.Errorf("RenderArea: %v", err)
+			}
+			if !exists {
+				continue
+			}
+
+			top := c.SubY(int16(c.HighestFilledSubChunk())) + 15
+			for z := uint8(0); z < 16; z++ {
+				if chunkZ == -1 && z != 15 {
+					continue
+				}
+				for x := uint8(0); x < 16; x++ {
+					index := (chunkZ<<4+int(z)+1)*pixelWidth + chunkX<<4 + int(x)
+					columns[index], rendered[index] = renderColumn(c, x, z, top), true
+				}
+			}
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, pixelWidth, pixelLength))
+	for z := range pixelLength {
+		for x := range pixelWidth {
+			index := (z+1)*pixelWidth + x
+			col := columns[index]
+			if !rendered[index] || col.color.A == 0 {
+				continue
+			}
+
+			brightness := brightnessEqual
+			if north := index - pixelWidth; !col.water && rendered[north] {
+				switch {
+				case col.height > columns[north].height:
+					brightness = brightnessHigher
+				case col.height < columns[north].height:
+					brightness = brightnessLower
+				}
+			}
+			img.SetRGBA(x, z, shade(col.color, brightness))
+		}
+	}
+
+	return img, nil
+}
+
+// renderColumn renders the block column at x and z of c, where top is
+// the highest Y that could have blocks.
+func renderColumn(c *chunk.Chunk, x, z uint8, top int16) column {
+	// The blocks above the ground are checked from the top,
+	// so that the snow layers, the plants and the water that
+	// are on the ground are shown.
+	ground := c.HighestSolidBlock(x, z)
+	for y := top; y > ground; y-- {
+		mapColor, _ := block.MapColorOf(c.Block(x, y, z, chunk.BlockLayer))
+		if mapColor.Tint != block.MapTintWater && c.IsWaterlogged(x, y, z) {
+			mapColor, _ = block.MapColorOf(c.Block(x, y, z, chunk.LiquidLayer))
+		}
+
+		switch {
+		case mapColor.Tint == block.MapTintWater:
+			water := tinted(mapColor, c.Biome(x, y, z))
+			groundColor, _ := block.MapColorOf(c.Block(x, ground, z, chunk.BlockLayer))
+			if groundColor.Color.A == 0 {
+				return column{color: water, height: y, water: true}
+			}
+			// The deeper the water is, the less the ground is seen.
+			alpha := min(0.5+float64(y-ground)*0.05, 0.9)
+			return column{color: blend(tinted(groundColor, c.Biome(x, ground, z)), water, alpha), height: y, water: true}
+		case mapColor.Color.A != 0:
+			return column{color: tinted(mapColor, c.Biome(x, y, z)), height: y}
+		}
+	}
+
+	mapColor, _ := block.MapColorOf(c.Block(x, ground, z, chunk.BlockLayer))
+	return column{color: tinted(mapColor, c.Biome(x, ground, z)), height: ground}
+}
+
+// tinted returns the color of mapColor in the biome whose numeric ID is biome.
+func tinted(mapColor block.MapColor, biome uint32) color.RGBA {
+	result := mapColor.Color
+	if mapColor.Tint == block.MapTintNone || result.A == 0 {
+		return result
+	}
+	tint := biomeTint(biomeOf(biome), mapColor.Tint)
+	result.R = uint8(uint16(result.R) * uint16(tint.R) / 255)
+	result.G = uint8(uint16(result.G) * uint16(tint.G) / 255)
+	result.B = uint8(uint16(result.B) * uint16(tint.B) / 255)
+	return result
+}
+
+// blend mixes the opaque colors a and b, where alpha is the weight of b.
+func blend(a, b color.RGBA, alpha float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x)*(1-alpha) + float64(y)*alpha + 0.5)
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+// shade multiplies the opaque color c by brightness, which is in a range of 0-255.
+func shade(c color.RGBA, brightness int) color.RGBA {
+	c.R = uint8(int(c.R) * brightness / 255)
+	c.G = uint8(int(c.G) * brightness / 255)
+	c.B = uint8(int(c.B) * brightness / 255)
+	return c
+}
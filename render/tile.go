@@ -0,0 +1,145 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// TileSize is the width and the height of a tile in pixels, and
+// a tile of level 0 holds 16x16 chunks with 1 pixel per block.
+const TileSize = 256
+
+// tilePos is the position of a tile in a level.
+type tilePos [2]int32
+
+// WriteTiles renders all the chunks of the dimension, and writes them as the tiled PNG files at
+// dir/<level>/<x>/<z>.png, where level is from 0 to levels-1. The tiles of level 0 are rendered with 1
+// pixel per block, so the tile at x and z holds the chunks from (x*16, z*16) to (x*16+15, z*16+15). Each
+// tile of level n is the 2x2 tiles of level n-1 that scaled down to a half, so it covers 2^n times the
+// width of a tile of level 0. The directories are created if they are not exist, and the existing tiles
+// are overwritten. tiles is the count of the tiles that are written.
+//
+// Note that level 0 is the most detailed one, so the map viewers should number the zoom levels reversely,
+// e.g. enable zoomReverse of Leaflet.
+func (r *Renderer) WriteTiles(dir string, levels int) (tiles int, err error) {
+	if levels < 1 {
+		return 0, fmt.Errorf("WriteTiles: invalid levels %d", levels)
+	}
+
+	positions, err := r.w.ChunkPositions(r.dm)
+	if err != nil {
+		return 0, fmt.Errorf("WriteTiles: %v", err)
+	}
+	current := make(map[tilePos]bool)
+	for _, position := range positions {
+		current[tilePos{position[0] >> 4, position[1] >> 4}] = true
+	}
+
+	for pos := range current {
+		img, err := r.RenderArea(define.ChunkPos{pos[0] << 4, pos[1] << 4}, 16, 16)
+		if err != nil {
+			return tiles, fmt.Errorf("WriteTiles: %v", err)
+		}
+		if err = writeTile(dir, 0, pos, img); err != nil {
+			return tiles, fmt.Errorf("WriteTiles: %v", err)
+		}
+		tiles++
+	}
+
+	for level := 1; level < levels; level++ {
+		parents := make(map[tilePos]bool)
+		for pos := range current {
+			parents[tilePos{pos[0] >> 1, pos[1] >> 1}] = true
+		}
+
+		for pos := range parents {
+			img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+			for i := range int32(4) {
+				child := tilePos{pos[0]<<1 | i&1, pos[1]<<1 | i>>1}
+				if !current[child] {
+					continue
+				}
+				childImg, err := readTile(dir, level-1, child)
+				if err != nil {
+					return tiles, fmt.Errorf("WriteTiles: %v", err)
+				}
+				downscale(img, image.Pt(int(i&1)*TileSize/2, int(i>>1)*TileSize/2), childImg)
+			}
+			if err := writeTile(dir, level, pos, img); err != nil {
+				return tiles, fmt.Errorf("WriteTiles: %v", err)
+			}
+			tiles++
+		}
+
+		current = parents
+	}
+
+	return tiles, nil
+}
+
+// tilePath returns the path of the tile at pos of level in dir.
+func tilePath(dir string, level int, pos tilePos) string {
+	return filepath.Join(dir, strconv.Itoa(level), strconv.Itoa(int(pos[0])), strconv.Itoa(int(pos[1]))+".png")
+}
+
+// writeTile encodes img as PNG and writes it as the tile at pos of level in dir.
+func writeTile(dir string, level int, pos tilePos, img image.Image) error {
+	name := tilePath(dir, level, pos)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readTile reads the tile at pos of level in dir.
+func readTile(dir string, level int, pos tilePos) (*image.RGBA, error) {
+	f, err := os.Open(tilePath(dir, level, pos))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	result := image.NewRGBA(img.Bounds())
+	draw.Draw(result, result.Bounds(), img, img.Bounds().Min, draw.Src)
+	return result, nil
+}
+
+// downscale scales src down to a half by averaging each 2x2 pixels, and draws it to dst at offset.
+func downscale(dst *image.RGBA, offset image.Point, src *image.RGBA) {
+	bounds := src.Bounds()
+	for y := 0; y < bounds.Dy()/2; y++ {
+		for x := 0; x < bounds.Dx()/2; x++ {
+			var sum [4]int
+			for i := range 4 {
+				c := src.RGBAAt(bounds.Min.X+x*2+i&1, bounds.Min.Y+y*2+i>>1)
+				sum[0], sum[1], sum[2], sum[3] = sum[0]+int(c.R), sum[1]+int(c.G), sum[2]+int(c.B), sum[3]+int(c.A)
+			}
+			// The colors of image.RGBA are alpha-premultiplied,
+			// so the transparent pixels are averaged correctly.
+			dst.SetRGBA(offset.X+x, offset.Y+y, color.RGBA{
+				R: uint8(sum[0] / 4), G: uint8(sum[1] / 4), B: uint8(sum[2] / 4), A: uint8(sum[3] / 4),
+			})
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package snbt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v as stringified NBT (SNBT), which is the text format of NBT that is used by the commands
+// of Minecraft, e.g. {Count:1b,Name:"minecraft:stone"}. The Go types of the values are the same as the ones
+// that NBT is decoded into by the nbt package of gophertunnel:
+//
+//	TAG_Byte: byte/uint8 or bool, which is written as 1b
+//	TAG_Short: int16, which is written as 1s
+//	TAG_Int: int32, which is written as 1
+//	TAG_Long: int64, which is written as 1L
+//	TAG_Float: float32, which is written as 1.5f
+//	TAG_Double: float64, which is written as 1.5d
+//	TAG_ByteArray: [...]byte, which is written as [B;1b,2b]
+//	TAG_String: string, which is always quoted
+//	TAG_List: a slice of any type above, which is written as [a,b]
+//	TAG_Compound: map[string]any (or a map of a type above), whose keys are sorted
+//	TAG_IntArray: [...]int32, which is written as [I;1,2]
+//	TAG_LongArray: [...]int64, which is written as [L;1L,2L]
+//
+// The result is the same for the same value, so it could be compared directly.
+func Marshal(v any) (string, error) {
+	var b strings.Builder
+	if err := marshal(&b, reflect.ValueOf(v)); err != nil {
+		return "", fmt.Errorf("Marshal: %v", err)
+	}
+	return b.String(), nil
+}
+
+// marshal writes the SNBT of val to b.
+func marshal(b *strings.Builder, val reflect.Value) error {
+	if val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return fmt.Errorf("nil value is not valid NBT")
+	}
+
+	switch val.Kind() {
+	case reflect.Uint8:
+		b.WriteString(strconv.Itoa(int(int8(val.Uint()))) + "b")
+	case reflect.Bool:
+		if val.Bool() {
+			b.WriteString("1b")
+		} else {
+			b.WriteString("0b")
+		}
+	case reflect.Int16:
+		b.WriteString(strconv.FormatInt(val.Int(), 10) + "s")
+	case reflect.Int32:
+		b.WriteString(strconv.FormatInt(val.Int(), 10))
+	case reflect.Int64:
+		b.WriteString(strconv.FormatInt(val.Int(), 10) + "L")
+	case reflect.Float32, reflect.Float64:
+		f, bitSize, suffix := val.Float(), 32, "f"
+		if val.Kind() == reflect.Float64 {
+			bitSize, suffix = 64, "d"
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%v could not be written as SNBT", f)
+		}
+		b.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize) + suffix)
+	case reflect.String:
+		writeString(b, val.String())
+	case reflect.Array:
+		var prefix string
+		switch val.Type().Elem().Kind() {
+		case reflect.Uint8:
+			prefix = "B;"
+		case reflect.Int32:
+			prefix = "I;"
+		case reflect.Int64:
+			prefix = "L;"
+		default:
+			return fmt.Errorf("array of %v is not valid NBT", val.Type().Elem())
+		}
+		b.WriteString("[" + prefix)
+		for i := range val.Len() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			_ = marshal(b, val.Index(i))
+		}
+		b.WriteByte(']')
+	case reflect.Slice:
+		b.WriteByte('[')
+		var elemType reflect.Type
+		for i := range val.Len() {
+			elem := val.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if i == 0 {
+				elemType = elem.Type()
+			} else {
+				if elem.Type() != elemType {
+					return fmt.Errorf("list holds both %v and %v", elemType, elem.Type())
+				}
+				b.WriteByte(',')
+			}
+			if err := marshal(b, elem); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("map with %v keys is not valid NBT", val.Type().Key())
+		}
+		keys := make([]string, 0, val.Len())
+		for _, key := range val.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		slices.Sort(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if isUnquoted(key) {
+				b.WriteString(key)
+			} else {
+				writeString(b, key)
+			}
+			b.WriteByte(':')
+			if err := marshal(b, val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))); err != nil {
+				return fmt.Errorf("%v: %v", key, err)
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("%v is not valid NBT", val.Type())
+	}
+	return nil
+}
+
+// writeString writes s to b as a quoted string, where the backslashes and the double quotes are escaped.
+func writeString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s))
+	b.WriteByte('"')
+}
+
+// isUnquoted returns if s could be written without quotes, which is true if s is not empty
+// and only holds letters, digits and _-.+ in ASCII.
+func isUnquoted(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := range len(s) {
+		if !isUnquotedChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnquotedChar returns if c could be a part of an unquoted string or number.
+func isUnquotedChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-' || c == '.' || c == '+'
+}
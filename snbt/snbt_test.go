@@ -0,0 +1,88 @@
+package snbt
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testCompound holds every type of tag that Marshal accepts.
+var testCompound = map[string]any{
+	"byte":      byte(255),
+	"short":     int16(-300),
+	"int":       int32(70000),
+	"long":      int64(-1) << 40,
+	"float":     float32(1.5),
+	"double":    float64(-0.125),
+	"string":    "a \"quoted\" \\ string",
+	"key space": "value",
+	"bytes":     [3]byte{1, 2, 255},
+	"ints":      [2]int32{-1, 1 << 30},
+	"longs":     [1]int64{1 << 50},
+	"empty":     [0]int32{},
+	"list":      []any{int32(1), int32(2), int32(3)},
+	"compounds": []any{map[string]any{"a": byte(1)}, map[string]any{}},
+	"nested":    map[string]any{"Name": "minecraft:stone", "Count": byte(1)},
+}
+
+func TestRoundTrip(t *testing.T) {
+	data, err := Marshal(testCompound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalCompound(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, testCompound) {
+		t.Fatalf("expected %#v, but got %#v", testCompound, decoded)
+	}
+
+	again, err := Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != data {
+		t.Fatalf("the SNBT of the decoded compound %s is not the same as the original one %s", again, data)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(map[string]any{"Name": "minecraft:stone", "Count": byte(1), "Damage": int16(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{Count:1b,Damage:0s,Name:"minecraft:stone"}`; data != expected {
+		t.Fatalf("expected %s, but got %s", expected, data)
+	}
+
+	for _, v := range []any{nil, []any{int32(1), "mixed"}, map[int]any{1: int32(1)}, float32(math.NaN())} {
+		if _, err = Marshal(v); err == nil {
+			t.Fatalf("expected an error for %#v", v)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	decoded, err := Unmarshal(`{ 'single': 'quoted', flag: true, n: 12, d: 1.5, word: stone, list: [] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"single": "quoted",
+		"flag":   byte(1),
+		"n":      int32(12),
+		"d":      float64(1.5),
+		"word":   "stone",
+		"list":   []any{},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, decoded)
+	}
+
+	for _, data := range []string{`{a:1`, `{a:1}b`, `[I;1b]`, `"unterminated`, `[1,"mixed"]`} {
+		if _, err = Unmarshal(data); err == nil {
+			t.Fatalf("expected an error for %s", data)
+		}
+	}
+}
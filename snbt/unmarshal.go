@@ -0,0 +1,306 @@
+package snbt
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes the stringified NBT (SNBT) in data, which is the reverse of Marshal. The values are
+// decoded into the same Go types as Marshal accepts, where the lists are decoded into []any and the
+// compounds are decoded into map[string]any, so the result could be encoded by the nbt package directly.
+//
+// The keys and the strings could be quoted by either double or single quotes. An unquoted value without
+// a suffix is an int if it is an integer, a double if it is a decimal, and a string otherwise. true and
+// false are the bytes 1b and 0b.
+func Unmarshal(data string) (any, error) {
+	p := &parser{data: data}
+	v, err := p.value()
+	if err == nil {
+		p.skipSpaces()
+		if p.offset < len(p.data) {
+			err = p.errorf("unexpected %q after the value", p.data[p.offset])
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshal: %v", err)
+	}
+	return v, nil
+}
+
+// UnmarshalCompound decodes the stringified NBT (SNBT) in data, which must be a compound.
+// See Unmarshal for more information.
+func UnmarshalCompound(data string) (map[string]any, error) {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("UnmarshalCompound: %v", err)
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("UnmarshalCompound: expected a compound, but got %T", v)
+	}
+	return m, nil
+}
+
+// parser parses SNBT from data.
+type parser struct {
+	data   string
+	offset int
+}
+
+// errorf returns an error that holds the current offset of p.
+func (p *parser) errorf(format string, a ...any) error {
+	return fmt.Errorf("offset %d: %s", p.offset, fmt.Sprintf(format, a...))
+}
+
+// skipSpaces skips the white spaces.
+func (p *parser) skipSpaces() {
+	for p.offset < len(p.data) && strings.IndexByte(" \t\r\n", p.data[p.offset]) >= 0 {
+		p.offset++
+	}
+}
+
+// peek skips the white spaces and returns the next character, which is 0 at the end of data.
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.offset >= len(p.data) {
+		return 0
+	}
+	return p.data[p.offset]
+}
+
+// expect skips the white spaces and reads c.
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		if p.offset >= len(p.data) {
+			return p.errorf("expected %q, but got the end", c)
+		}
+		return p.errorf("expected %q, but got %q", c, p.data[p.offset])
+	}
+	p.offset++
+	return nil
+}
+
+// value parses a value of any type.
+func (p *parser) value() (any, error) {
+	switch p.peek() {
+	case '{':
+		return p.compound()
+	case '[':
+		if p.offset+2 < len(p.data) && p.data[p.offset+2] == ';' {
+			return p.array()
+		}
+		return p.list()
+	case '"', '\'':
+		return p.quoted()
+	case 0:
+		return nil, p.errorf("expected a value, but got the end")
+	}
+
+	token := p.unquoted()
+	if token == "" {
+		return nil, p.errorf("unexpected %q", p.data[p.offset])
+	}
+	return parseToken(token), nil
+}
+
+// compound parses a compound, whose keys could be quoted.
+func (p *parser) compound() (map[string]any, error) {
+	result := make(map[string]any)
+	p.offset++
+	if p.peek() == '}' {
+		p.offset++
+		return result, nil
+	}
+
+	for {
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			var err error
+			if key, err = p.quoted(); err != nil {
+				return nil, err
+			}
+		} else if key = p.unquoted(); key == "" {
+			return nil, p.errorf("expected a key")
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		result[key] = v
+
+		if p.peek() == ',' {
+			p.offset++
+			continue
+		}
+		return result, p.expect('}')
+	}
+}
+
+// list parses a list, whose elements must have the same type.
+func (p *parser) list() ([]any, error) {
+	result := make([]any, 0)
+	p.offset++
+	if p.peek() == ']' {
+		p.offset++
+		return result, nil
+	}
+
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) > 0 && reflect.TypeOf(v) != reflect.TypeOf(result[0]) {
+			return nil, p.errorf("list holds both %T and %T", result[0], v)
+		}
+		result = append(result, v)
+
+		if p.peek() == ',' {
+			p.offset++
+			continue
+		}
+		return result, p.expect(']')
+	}
+}
+
+// array parses a byte array, an int array or a long array, e.g. [B;1b,2b].
+func (p *parser) array() (any, error) {
+	var elemType reflect.Type
+	switch p.data[p.offset+1] {
+	case 'B':
+		elemType = reflect.TypeFor[byte]()
+	case 'I':
+		elemType = reflect.TypeFor[int32]()
+	case 'L':
+		elemType = reflect.TypeFor[int64]()
+	default:
+		return nil, p.errorf("unknown array type %q", p.data[p.offset+1])
+	}
+	p.offset += 3
+
+	var values []any
+	if p.peek() == ']' {
+		p.offset++
+	} else {
+		for {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if reflect.TypeOf(v) != elemType {
+				return nil, p.errorf("array of %v holds %T", elemType, v)
+			}
+			values = append(values, v)
+
+			if p.peek() == ',' {
+				p.offset++
+				continue
+			}
+			if err = p.expect(']'); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	result := reflect.New(reflect.ArrayOf(len(values), elemType)).Elem()
+	for i, v := range values {
+		result.Index(i).Set(reflect.ValueOf(v))
+	}
+	return result.Interface(), nil
+}
+
+// quoted parses a string quoted by either double or single quotes.
+func (p *parser) quoted() (string, error) {
+	quote := p.data[p.offset]
+	p.offset++
+
+	var b strings.Builder
+	for p.offset < len(p.data) {
+		c := p.data[p.offset]
+		p.offset++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.offset >= len(p.data) {
+				return "", p.errorf("unterminated string")
+			}
+			switch escaped := p.data[p.offset]; escaped {
+			case '\\', '"', '\'':
+				b.WriteByte(escaped)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				return "", p.errorf("unknown escape %q", escaped)
+			}
+			p.offset++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// unquoted reads the characters that could be a part of an unquoted string or number.
+func (p *parser) unquoted() string {
+	start := p.offset
+	for p.offset < len(p.data) && isUnquotedChar(p.data[p.offset]) {
+		p.offset++
+	}
+	return p.data[start:p.offset]
+}
+
+// parseToken converts an unquoted token to a number, a boolean or a string.
+func parseToken(token string) any {
+	switch strings.ToLower(token) {
+	case "true":
+		return byte(1)
+	case "false":
+		return byte(0)
+	}
+
+	number, suffix := token[:len(token)-1], token[len(token)-1]
+	switch suffix {
+	case 'b', 'B':
+		if v, err := strconv.ParseInt(number, 10, 8); err == nil {
+			return byte(v)
+		}
+	case 's', 'S':
+		if v, err := strconv.ParseInt(number, 10, 16); err == nil {
+			return int16(v)
+		}
+	case 'l', 'L':
+		if v, err := strconv.ParseInt(number, 10, 64); err == nil {
+			return v
+		}
+	case 'f', 'F':
+		if v, err := strconv.ParseFloat(number, 32); err == nil {
+			return float32(v)
+		}
+	case 'd', 'D':
+		if v, err := strconv.ParseFloat(number, 64); err == nil {
+			return v
+		}
+	}
+
+	if v, err := strconv.ParseInt(token, 10, 32); err == nil {
+		return int32(v)
+	}
+	if strings.ContainsAny(token, ".eE") {
+		if v, err := strconv.ParseFloat(token, 64); err == nil {
+			return v
+		}
+	}
+	return token
+}
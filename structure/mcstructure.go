@@ -0,0 +1,282 @@
+package structure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// mcStructureFormatVersion is the format_version of the .mcstructure files that Bedrock writes.
+const mcStructureFormatVersion int32 = 1
+
+// mcStructurePalette is the name of the palette that is used by Bedrock.
+const mcStructurePalette = "default"
+
+// ReadMCStructureFile reads a .mcstructure file at a path and returns the Structure in it.
+func ReadMCStructureFile(name string) (*Structure, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("mcstructure: open file: %w", err)
+	}
+	defer f.Close()
+	return ReadMCStructure(bufio.NewReader(f))
+}
+
+// ReadMCStructure reads a .mcstructure file, which is a little endian NBT, from r and returns the
+// Structure in it. The block states in the palette are upgraded to the current version, and the
+// positions of the entities are converted to be relative to the lowest corner of the Structure.
+func ReadMCStructure(r io.Reader) (*Structure, error) {
+	var m map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("mcstructure: decode nbt: %w", err)
+	}
+	return decodeMCStructure(m)
+}
+
+// decodeMCStructure builds the Structure from the NBT of a .mcstructure file.
+func decodeMCStructure(m map[string]any) (*Structure, error) {
+	size, ok := int32Triple(m["size"])
+	if !ok {
+		return nil, fmt.Errorf("mcstructure: invalid size %v", m["size"])
+	}
+	volume, ok := Volume(size)
+	if !ok {
+		return nil, fmt.Errorf("mcstructure: invalid size %v, whose volume must be no more than %d", size, MaxVolume)
+	}
+	origin, _ := int32Triple(m["structure_world_origin"])
+
+	// The size is checked against the indices of layer 0, which are always
+	// written, before the blocks are allocated.
+	data, _ := m["structure"].(map[string]any)
+	layers, _ := data["block_indices"].([]any)
+	var blockIndices []int32
+	if len(layers) > 0 {
+		blockIndices, _ = layers[0].([]int32)
+	}
+	if len(blockIndices) != volume {
+		return nil, fmt.Errorf("mcstructure: expected %d block indices in layer 0, but got %d", volume, len(blockIndices))
+	}
+
+	s := NewStructure(size)
+	s.origin = define.BlockPos(origin)
+
+	palettes, _ := data["palette"].(map[string]any)
+	palette, _ := palettes[mcStructurePalette].(map[string]any)
+
+	// Decode the block palette, which is shared by the two layers.
+	blockPalette, _ := palette["block_palette"].([]any)
+	runtimeIDs := make([]uint32, len(blockPalette))
+	for i, entry := range blockPalette {
+		state, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("mcstructure: invalid block palette entry %v", entry)
+		}
+		runtimeID, err := chunk.BlockPaletteEncoding.DecodeBlockState(state)
+		if err != nil {
+			return nil, fmt.Errorf("mcstructure: decode block palette: %w", err)
+		}
+		runtimeIDs[i] = runtimeID
+	}
+
+	// Decode the two layers of blocks, where -1 means there is no block.
+	for layer := range min(len(layers), len(s.blocks)) {
+		indices, _ := layers[layer].([]int32)
+		if len(indices) == 0 {
+			// The layer is empty if all its blocks are void.
+			continue
+		}
+		if len(indices) != len(s.blocks[layer]) {
+			return nil, fmt.Errorf("mcstructure: expected %d block indices in layer %d, but got %d", len(s.blocks[layer]), layer, len(indices))
+		}
+		for i, index := range indices {
+			switch {
+			case index == -1:
+				s.blocks[layer][i] = VoidRuntimeID
+			case index >= 0 && int(index) < len(runtimeIDs):
+				s.blocks[layer][i] = runtimeIDs[index]
+			default:
+				return nil, fmt.Errorf("mcstructure: block index %d out of palette (len=%d)", index, len(runtimeIDs))
+			}
+		}
+	}
+
+	// Decode the block entities, which are indexed by the index of the blocks.
+	positionData, _ := palette["block_position_data"].(map[string]any)
+	for key, value := range positionData {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(s.blocks[0]) {
+			return nil, fmt.Errorf("mcstructure: invalid block position data index %q", key)
+		}
+		entry, _ := value.(map[string]any)
+		if blockEntity, ok := entry["block_entity_data"].(map[string]any); ok {
+			s.blockEntities[s.position(index)] = blockEntity
+		}
+	}
+
+	// Decode the entities, and make their positions relative.
+	entities, _ := data["entities"].([]any)
+	for _, entity := range entities {
+		if nbt, ok := entity.(map[string]any); ok {
+			s.entities = append(s.entities, offsetEntity(nbt, s.origin, -1))
+		}
+	}
+
+	return s, nil
+}
+
+// WriteMCStructureFile writes s to a .mcstructure file at name.
+func (s *Structure) WriteMCStructureFile(name string) error {
+	f, err := os.OpenFile(name, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("mcstructure: open file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	if err = s.WriteMCStructure(w); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("mcstructure: flush file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("mcstructure: close file: %w", err)
+	}
+	return nil
+}
+
+// WriteMCStructure writes s to w as a .mcstructure file, whose block states are the ones of
+// chunk.CurrentBlockVersion. The positions of the block entities and the entities are written as
+// the positions in the world, which are computed by the origin of s.
+func (s *Structure) WriteMCStructure(w io.Writer) error {
+	return s.WriteMCStructureDowngraded(w, chunk.CurrentBlockVersion, block.AirRuntimeID)
+}
+
+// WriteMCStructureDowngraded writes s to w as a .mcstructure file like WriteMCStructure, but the block
+// states are downgraded to targetVersion, and the blocks that are not exist at targetVersion are written
+// as replacement. See chunk.NewBlockPaletteEncoding for more information.
+func (s *Structure) WriteMCStructureDowngraded(w io.Writer, targetVersion int32, replacement uint32) error {
+	m := s.encodeMCStructure(chunk.NewBlockPaletteEncoding(targetVersion, replacement).EncodeBlockState)
+	if err := nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(m); err != nil {
+		return fmt.Errorf("mcstructure: encode nbt: %w", err)
+	}
+	return nil
+}
+
+// encodeMCStructure builds the NBT of a .mcstructure file from s, where the block states in the
+// palette are encoded by encodeBlockState.
+func (s *Structure) encodeMCStructure(encodeBlockState func(runtimeID uint32) define.BlockState) map[string]any {
+	// Build the block palette, which is shared by the two layers.
+	blockPalette := make([]define.BlockState, 0)
+	paletteIndex := make(map[uint32]int32)
+	blockIndices := make([][]int32, len(s.blocks))
+	for layer, blocks := range s.blocks {
+		indices := make([]int32, len(blocks))
+		for i, runtimeID := range blocks {
+			if runtimeID == VoidRuntimeID {
+				indices[i] = -1
+				continue
+			}
+			index, ok := paletteIndex[runtimeID]
+			if !ok {
+				index = int32(len(blockPalette))
+				paletteIndex[runtimeID] = index
+				blockPalette = append(blockPalette, encodeBlockState(runtimeID))
+			}
+			indices[i] = index
+		}
+		blockIndices[layer] = indices
+	}
+
+	// The block entities are indexed by the index of the
+	// blocks, and their positions are the ones in the world.
+	positionData := make(map[string]any)
+	for pos, nbt := range s.blockEntities {
+		if !s.Contains(pos[0], pos[1], pos[2]) {
+			continue
+		}
+		positionData[strconv.Itoa(s.index(pos[0], pos[1], pos[2]))] = map[string]any{
+			"block_entity_data": withBlockPos(nbt, s.origin.Add(pos)),
+		}
+	}
+
+	entities := make([]map[string]any, len(s.entities))
+	for i, nbt := range s.entities {
+		entities[i] = offsetEntity(nbt, s.origin, 1)
+	}
+
+	return map[string]any{
+		"format_version": mcStructureFormatVersion,
+		"size":           s.size[:],
+		"structure": map[string]any{
+			"block_indices": blockIndices,
+			"entities":      entities,
+			"palette": map[string]any{
+				mcStructurePalette: map[string]any{
+					"block_palette":       blockPalette,
+					"block_position_data": positionData,
+				},
+			},
+		},
+		"structure_world_origin": s.origin[:],
+	}
+}
+
+// position returns the relative position of the block at index in the blocks.
+func (s *Structure) position(index int) define.BlockPos {
+	z := int32(index % int(s.size[2]))
+	index /= int(s.size[2])
+	return define.BlockPos{int32(index / int(s.size[1])), int32(index % int(s.size[1])), z}
+}
+
+// int32Triple converts a TAG_List of 3 TAG_Int to an array.
+func int32Triple(v any) (result [3]int32, ok bool) {
+	switch list := v.(type) {
+	case []int32:
+		if len(list) == 3 {
+			return [3]int32(list), true
+		}
+	case []any:
+		if len(list) == 3 {
+			for i, value := range list {
+				if result[i], ok = value.(int32); !ok {
+					return result, false
+				}
+			}
+			return result, true
+		}
+	}
+	return result, false
+}
+
+// withBlockPos returns a copy of the NBT of a block entity whose x, y and z are set to pos.
+func withBlockPos(nbt map[string]any, pos define.BlockPos) map[string]any {
+	result := maps.Clone(nbt)
+	result["x"], result["y"], result["z"] = pos[0], pos[1], pos[2]
+	return result
+}
+
+// offsetEntity returns a copy of the NBT of an entity whose "Pos" is moved by origin multiplied by sign,
+// which is 1 to convert a relative position to the world one, and -1 to convert it back.
+func offsetEntity(nbt map[string]any, origin define.BlockPos, sign float32) map[string]any {
+	result := maps.Clone(nbt)
+	if pos, ok := nbt["Pos"].([]any); ok && len(pos) == 3 {
+		newPos := make([]any, 3)
+		for i, value := range pos {
+			if f, ok := value.(float32); ok {
+				value = f + sign*float32(origin[i])
+			}
+			newPos[i] = value
+		}
+		result["Pos"] = newPos
+	}
+	return result
+}
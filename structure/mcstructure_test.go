@@ -0,0 +1,171 @@
+package structure
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// testStructure returns a 3x4x5 structure at (100, -20, 7), which holds a chest with a block entity,
+// a waterlogged fence, some void blocks and an entity.
+func testStructure(t *testing.T) *Structure {
+	chest, found := block.StateToRuntimeID("minecraft:chest", map[string]any{"minecraft:cardinal_direction": "north"})
+	if !found {
+		t.Fatal("chest is not found")
+	}
+	fence, found := block.StateToRuntimeID("minecraft:oak_fence", nil)
+	if !found {
+		t.Fatal("oak fence is not found")
+	}
+
+	s := NewStructure([3]int32{3, 4, 5})
+	s.SetOrigin(define.BlockPos{100, -20, 7})
+	s.SetBlock(0, 0, 0, 0, chest)
+	s.SetBlock(2, 3, 4, 0, fence)
+	s.SetBlock(2, 3, 4, 1, block.WaterRuntimeID)
+	s.SetBlock(1, 1, 1, 0, VoidRuntimeID)
+	s.SetBlock(1, 2, 1, 0, VoidRuntimeID)
+	s.SetBlockEntity(define.BlockPos{0, 0, 0}, map[string]any{"id": "Chest", "CustomName": "test"})
+	s.SetEntities([]map[string]any{
+		{"identifier": "minecraft:pig", "Pos": []any{float32(1.5), float32(2), float32(3.5)}},
+	})
+	return s
+}
+
+// checkStructureEqual checks if the blocks, the block entities and the entities of got are the same as
+// the ones of expected.
+func checkStructureEqual(t *testing.T, expected, got *Structure) {
+	if got.Size() != expected.Size() || got.Origin() != expected.Origin() {
+		t.Fatalf("expected size %v and origin %v, but got %v and %v", expected.Size(), expected.Origin(), got.Size(), got.Origin())
+	}
+	size := expected.Size()
+	for x := range size[0] {
+		for y := range size[1] {
+			for z := range size[2] {
+				for layer := range uint8(2) {
+					if a, b := expected.Block(x, y, z, layer), got.Block(x, y, z, layer); a != b {
+						t.Fatalf("expected block %d at (%d, %d, %d) in layer %d, but got %d", a, x, y, z, layer, b)
+					}
+				}
+			}
+		}
+	}
+
+	blockEntity := got.BlockEntity(define.BlockPos{0, 0, 0})
+	if blockEntity["id"] != "Chest" || blockEntity["CustomName"] != "test" {
+		t.Fatalf("expected the chest block entity at (0, 0, 0), but got %v", blockEntity)
+	}
+	if len(got.BlockEntities()) != 1 {
+		t.Fatalf("expected 1 block entity, but got %d", len(got.BlockEntities()))
+	}
+
+	entities := got.Entities()
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, but got %d", len(entities))
+	}
+	pos, _ := entities[0]["Pos"].([]any)
+	if len(pos) != 3 || pos[0] != float32(1.5) || pos[1] != float32(2) || pos[2] != float32(3.5) {
+		t.Fatalf("expected the entity at the relative position (1.5, 2, 3.5), but got %v", entities[0]["Pos"])
+	}
+}
+
+func TestMCStructureRoundTrip(t *testing.T) {
+	s := testStructure(t)
+	var buf bytes.Buffer
+	if err := s.WriteMCStructure(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadMCStructure(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStructureEqual(t, s, decoded)
+}
+
+func TestMCStructureFileRoundTrip(t *testing.T) {
+	s := testStructure(t)
+	name := filepath.Join(t.TempDir(), "test.mcstructure")
+	if err := s.WriteMCStructureFile(name); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadMCStructureFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStructureEqual(t, s, decoded)
+}
+
+func TestWriteMCStructureFileError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "missing", "test.mcstructure")
+	if err := testStructure(t).WriteMCStructureFile(name); err == nil {
+		t.Fatal("expected an error when writing to a directory that does not exist")
+	}
+}
+
+func TestReadMCStructureInvalidSize(t *testing.T) {
+	for _, test := range []struct {
+		size    []int32
+		indices []int32
+	}{
+		{[]int32{1 << 21, 1 << 21, 1 << 21}, []int32{}},
+		{[]int32{-1, 2, 2}, []int32{}},
+		{[]int32{2, 2, 2}, make([]int32, 7)},
+		{[]int32{1 << 15, 1 << 15, 1}, make([]int32, 1)},
+	} {
+		var buf bytes.Buffer
+		err := nbt.NewEncoderWithEncoding(&buf, nbt.LittleEndian).Encode(map[string]any{
+			"format_version": int32(1),
+			"size":           test.size,
+			"structure": map[string]any{
+				"block_indices": []any{test.indices, []int32{}},
+				"entities":      []any{},
+				"palette":       map[string]any{},
+			},
+			"structure_world_origin": []int32{0, 0, 0},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ReadMCStructure(&buf); err == nil {
+			t.Fatalf("expected an error for size %v with %d block indices", test.size, len(test.indices))
+		}
+	}
+}
+
+func TestWriteMCStructureDowngraded(t *testing.T) {
+	// 1.16.0, which is before tuff is added.
+	const version int32 = 1<<24 | 16<<16
+	tuff, found := block.StateToRuntimeID("minecraft:tuff", nil)
+	if !found {
+		t.Fatal("tuff is not found")
+	}
+	s := NewStructure([3]int32{1, 1, 2})
+	s.SetBlock(0, 0, 0, 0, tuff)
+
+	var buf bytes.Buffer
+	if err := s.WriteMCStructureDowngraded(&buf, version, block.AirRuntimeID); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadMCStructure(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Block(0, 0, 0, 0); got != block.AirRuntimeID {
+		t.Fatalf("expected tuff to be replaced by air, but got %d", got)
+	}
+
+	buf.Reset()
+	if err = s.WriteMCStructure(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err = ReadMCStructure(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Block(0, 0, 0, 0); got != tuff {
+		t.Fatalf("expected tuff from WriteMCStructure, but got %d", got)
+	}
+}
@@ -0,0 +1,150 @@
+package structure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	block_java "github.com/TriM-Organization/bedrock-world-operator/block/java"
+	"github.com/TriM-Organization/bedrock-world-operator/internal/nbtvalue"
+)
+
+// ReadSchemFile reads a Sponge schematic (.schem) file at a path and returns the Structure in it.
+func ReadSchemFile(name string) (*Structure, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("schem: open file: %w", err)
+	}
+	defer f.Close()
+	return ReadSchem(bufio.NewReader(f))
+}
+
+// ReadSchem reads a Sponge schematic (.schem) from r and returns the Structure in it. The version 1, 2 and 3
+// of the format are supported, and the data could be compressed by gzip or not.
+//
+// The Java Edition block states in the palette are converted by block_java.ToBedrock, and the blocks that
+// could not be converted become "minecraft:unknown". The waterlogged blocks are placed with water in
+// chunk.LiquidLayer, and the other blocks in chunk.LiquidLayer are VoidRuntimeID.
+//
+// The block entities and the entities are not imported, because their NBT is not compatible with Bedrock
+// Edition.
+func ReadSchem(r io.Reader) (*Structure, error) {
+	m, err := decodeJavaNBT(r)
+	if err != nil {
+		return nil, fmt.Errorf("schem: %w", err)
+	}
+	// The root compound of version 3 is not named, and
+	// it holds the real one which is named "Schematic".
+	if schematic, ok := m["Schematic"].(map[string]any); ok {
+		m = schematic
+	}
+
+	version, _ := nbtvalue.Int(m["Version"])
+	size, err := schematicSize(m)
+	if err != nil {
+		return nil, fmt.Errorf("schem: %w", err)
+	}
+
+	var (
+		palette map[string]any
+		data    []byte
+	)
+	switch version {
+	case 1, 2:
+		palette, _ = m["Palette"].(map[string]any)
+		data, _ = nbtvalue.Bytes(m["BlockData"])
+	case 3:
+		blocks, _ := m["Blocks"].(map[string]any)
+		palette, _ = blocks["Palette"].(map[string]any)
+		data, _ = nbtvalue.Bytes(blocks["Data"])
+	default:
+		return nil, fmt.Errorf("schem: unsupported version %d", version)
+	}
+
+	// Convert the palette, which maps the Java Edition
+	// block states to the indices used in the data.
+	runtimeIDs := make(map[int][2]uint32, len(palette))
+	for state, value := range palette {
+		index, ok := nbtvalue.Int(value)
+		if !ok {
+			return nil, fmt.Errorf("schem: invalid palette index %v of %#v", value, state)
+		}
+		runtimeIDs[index] = javaStateToBedrock(state)
+	}
+
+	// Each block uses at least one byte of the data, so the
+	// size is checked by it before the blocks are allocated.
+	volume, ok := Volume(size)
+	if !ok {
+		return nil, fmt.Errorf("schem: invalid size %v, whose volume must be no more than %d", size, MaxVolume)
+	}
+	if volume > len(data) {
+		return nil, fmt.Errorf("schem: expected at least %d bytes of block data, but got %d", volume, len(data))
+	}
+
+	s := NewStructure(size)
+	for i, ptr := 0, 0; i < volume; i++ {
+		// The indices are encoded as varints.
+		index, shift := 0, 0
+		for {
+			if ptr >= len(data) {
+				return nil, fmt.Errorf("schem: block data ended at block %d, but the volume is %d", i, volume)
+			}
+			b := data[ptr]
+			ptr++
+			index |= int(b&0x7f) << shift
+			if b&0x80 == 0 {
+				break
+			}
+			if shift += 7; shift > 28 {
+				return nil, fmt.Errorf("schem: block data has a too long varint at block %d", i)
+			}
+		}
+
+		layers, ok := runtimeIDs[index]
+		if !ok {
+			return nil, fmt.Errorf("schem: block index %d out of palette (len=%d)", index, len(runtimeIDs))
+		}
+		// The order of the blocks is (y*length+z)*width+x.
+		x, y, z := int32(i%int(size[0])), int32(i/(int(size[0])*int(size[2]))), int32(i/int(size[0])%int(size[2]))
+		to := s.index(x, y, z)
+		s.blocks[0][to], s.blocks[1][to] = layers[0], layers[1]
+	}
+
+	return s, nil
+}
+
+// javaStateToBedrock converts the Java Edition block state string to the runtime IDs of
+// the blocks in chunk.BlockLayer and chunk.LiquidLayer.
+func javaStateToBedrock(state string) (layers [2]uint32) {
+	layers = [2]uint32{block.UnknownRuntimeID, VoidRuntimeID}
+
+	s, err := block_java.ParseState(state)
+	if err != nil {
+		return
+	}
+	if s.Properties["waterlogged"] == "true" {
+		layers[1] = block.WaterRuntimeID
+	}
+	delete(s.Properties, "waterlogged")
+
+	if runtimeID, _, found := block_java.ToBedrock(s); found {
+		layers[0] = runtimeID
+	}
+	return
+}
+
+// schematicSize returns the size of a Sponge or MCEdit schematic, which
+// is stored in the unsigned TAG_Short "Width", "Height" and "Length".
+func schematicSize(m map[string]any) (size [3]int32, err error) {
+	for i, key := range [3]string{"Width", "Height", "Length"} {
+		value, ok := m[key].(int16)
+		if !ok {
+			return size, fmt.Errorf("invalid %s %v", key, m[key])
+		}
+		size[i] = int32(uint16(value))
+	}
+	return size, nil
+}
@@ -0,0 +1,76 @@
+package structure
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// encodeJavaNBT encodes m as the big endian NBT of Java Edition.
+func encodeJavaNBT(t *testing.T, m map[string]any) *bytes.Buffer {
+	var buf bytes.Buffer
+	if err := nbt.NewEncoderWithEncoding(&buf, nbt.BigEndian).Encode(m); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestReadSchematic(t *testing.T) {
+	// A 2x1x1 schematic holding a stone (1) and a wool of orange (35:1).
+	s, err := ReadSchematic(encodeJavaNBT(t, map[string]any{
+		"Width": int16(2), "Height": int16(1), "Length": int16(1), "Materials": "Alpha",
+		"Blocks": [2]byte{1, 35}, "Data": [2]byte{0, 1},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != [3]int32{2, 1, 1} {
+		t.Fatalf("expected size [2 1 1], but got %v", s.Size())
+	}
+	if name, _, _ := block.RuntimeIDToState(s.Block(1, 0, 0, 0)); name != "minecraft:orange_wool" {
+		t.Fatalf("expected orange wool at (1, 0, 0), but got %v", name)
+	}
+}
+
+func TestReadSchematicMalformed(t *testing.T) {
+	for _, m := range []map[string]any{
+		// The size is the largest one, but there is no block.
+		{"Width": int16(-1), "Height": int16(-1), "Length": int16(-1), "Materials": "Alpha", "Blocks": [0]byte{}, "Data": [0]byte{}},
+		{"Width": int16(2), "Height": int16(2), "Length": int16(2), "Materials": "Alpha", "Blocks": [7]byte{}, "Data": [8]byte{}},
+		{"Width": int16(1), "Height": int16(1), "Length": int16(1), "Materials": "Classic", "Blocks": [1]byte{}, "Data": [1]byte{}},
+	} {
+		if _, err := ReadSchematic(encodeJavaNBT(t, m)); err == nil {
+			t.Fatalf("expected an error for %v", m)
+		}
+	}
+}
+
+func TestReadSchem(t *testing.T) {
+	s, err := ReadSchem(encodeJavaNBT(t, map[string]any{
+		"Version": int32(2), "Width": int16(2), "Height": int16(1), "Length": int16(1),
+		"Palette":   map[string]any{"minecraft:air": int32(0), "minecraft:stone": int32(1)},
+		"BlockData": [2]byte{0, 1},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _, _ := block.RuntimeIDToState(s.Block(1, 0, 0, 0)); name != "minecraft:stone" {
+		t.Fatalf("expected stone at (1, 0, 0), but got %v", name)
+	}
+}
+
+func TestReadSchemMalformed(t *testing.T) {
+	for _, m := range []map[string]any{
+		// The size is the largest one, but there is no block data.
+		{"Version": int32(2), "Width": int16(-1), "Height": int16(-1), "Length": int16(-1), "Palette": map[string]any{}, "BlockData": [0]byte{}},
+		{"Version": int32(2), "Width": int16(2), "Height": int16(2), "Length": int16(2), "Palette": map[string]any{"minecraft:air": int32(0)}, "BlockData": [7]byte{}},
+		{"Version": int32(2), "Width": int16(1), "Height": int16(1), "Length": int16(1), "Palette": map[string]any{"minecraft:air": int32(0)}, "BlockData": [1]byte{5}},
+		{"Version": int32(4), "Width": int16(1), "Height": int16(1), "Length": int16(1)},
+	} {
+		if _, err := ReadSchem(encodeJavaNBT(t, m)); err == nil {
+			t.Fatalf("expected an error for %v", m)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package structure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/internal/nbtvalue"
+)
+
+// ReadSchematicFile reads a MCEdit schematic (.schematic) file at a path and returns the Structure in it.
+func ReadSchematicFile(name string) (*Structure, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("schematic: open file: %w", err)
+	}
+	defer f.Close()
+	return ReadSchematic(bufio.NewReader(f))
+}
+
+// ReadSchematic reads a MCEdit schematic (.schematic) from r and returns the Structure in it. The data could
+// be compressed by gzip or not.
+//
+// The blocks are stored as the legacy numeric IDs and metadata. If "Materials" is "Alpha", which is the most
+// common one, the IDs of Java Edition are converted to the ones of Bedrock Edition first. Then, the blocks are
+// converted by block.LegacyToRuntimeID, and the blocks that could not be converted become "minecraft:unknown".
+// All the blocks in chunk.LiquidLayer are VoidRuntimeID.
+//
+// The origin of the Structure is set to "WEOrigin" if it exists. The block entities and the entities are
+// not imported, because their NBT is not compatible with Bedrock Edition.
+func ReadSchematic(r io.Reader) (*Structure, error) {
+	m, err := decodeJavaNBT(r)
+	if err != nil {
+		return nil, fmt.Errorf("schematic: %w", err)
+	}
+
+	size, err := schematicSize(m)
+	if err != nil {
+		return nil, fmt.Errorf("schematic: %w", err)
+	}
+	materials, _ := m["Materials"].(string)
+	if materials != "Alpha" && materials != "Pocket" {
+		return nil, fmt.Errorf("schematic: unsupported materials %#v", materials)
+	}
+
+	volume, ok := Volume(size)
+	if !ok {
+		return nil, fmt.Errorf("schematic: invalid size %v, whose volume must be no more than %d", size, MaxVolume)
+	}
+
+	// The size is checked against the blocks before they are allocated.
+	ids, _ := nbtvalue.Bytes(m["Blocks"])
+	data, _ := nbtvalue.Bytes(m["Data"])
+	addBlocks, _ := nbtvalue.Bytes(m["AddBlocks"])
+	if len(ids) != volume || len(data) != volume {
+		return nil, fmt.Errorf("schematic: expected %d blocks, but got %d IDs and %d metadata", volume, len(ids), len(data))
+	}
+	s := NewStructure(size)
+
+	runtimeIDs := make(map[[2]uint16]uint32)
+	for i := range volume {
+		id, meta := uint16(ids[i]), uint16(data[i]&0xf)
+		// AddBlocks holds the highest 4 bits of the IDs, where
+		// the one of the even block is in the lower nibble.
+		if i>>1 < len(addBlocks) {
+			if i&1 == 0 {
+				id |= uint16(addBlocks[i>>1]&0x0f) << 8
+			} else {
+				id |= uint16(addBlocks[i>>1]&0xf0) << 4
+			}
+		}
+
+		runtimeID, ok := runtimeIDs[[2]uint16{id, meta}]
+		if !ok {
+			if materials == "Alpha" {
+				runtimeID, ok = javaLegacyToRuntimeID(id, meta)
+			} else {
+				runtimeID, ok = block.LegacyToRuntimeID(id, meta)
+			}
+			if !ok {
+				runtimeID = block.UnknownRuntimeID
+			}
+			runtimeIDs[[2]uint16{id, meta}] = runtimeID
+		}
+
+		// The order of the blocks is (y*length+z)*width+x.
+		x, y, z := int32(i%int(size[0])), int32(i/(int(size[0])*int(size[2]))), int32(i/int(size[0])%int(size[2]))
+		s.blocks[0][s.index(x, y, z)] = runtimeID
+	}
+
+	originX, okX := nbtvalue.Int(m["WEOriginX"])
+	originY, okY := nbtvalue.Int(m["WEOriginY"])
+	originZ, okZ := nbtvalue.Int(m["WEOriginZ"])
+	if okX && okY && okZ {
+		s.origin = define.BlockPos{int32(originX), int32(originY), int32(originZ)}
+	}
+
+	return s, nil
+}
+
+// javaGlazedTerracotta holds the legacy numeric IDs of Bedrock Edition of the glazed
+// terracotta, which is indexed by the color in the order of Java Edition.
+var javaGlazedTerracotta = [16]uint16{220, 221, 222, 223, 224, 225, 226, 227, 228, 229, 219, 231, 232, 233, 234, 235}
+
+// javaLegacyToRuntimeID converts the legacy numeric ID and metadata of Java Edition, which are used by
+// Minecraft 1.12 and the older, to the runtime ID of the current block. Most of the IDs are the same as
+// the ones of Bedrock Edition, and the others are converted to their Bedrock Edition equivalents first.
+//
+// The metadata of the blocks that are not listed below are used as they are, which is correct for most
+// of the blocks.
+func javaLegacyToRuntimeID(id uint16, meta uint16) (runtimeID uint32, found bool) {
+	switch {
+	case id == 36:
+		// The moving pistons could not exist out of the world.
+		id, meta = 0, 0
+	case id == 43 || id == 44:
+		// The quartz slab and the nether brick slab are swapped.
+		if t := meta & 7; t == 6 || t == 7 {
+			meta ^= 1
+		}
+	case id == 95:
+		id = 241
+	case id == 125 || id == 126:
+		id += 32
+	case id == 157:
+		id = 126
+	case id == 158:
+		id = 125
+	case id == 166:
+		return block.StateToRuntimeID("minecraft:barrier", map[string]any{})
+	case id >= 188 && id <= 192:
+		// The fences of the other woods are the variants of the oak one.
+		id, meta = 85, [...]uint16{1, 2, 3, 5, 4}[id-188]
+	case id == 198:
+		id = 208
+	case id == 199:
+		id = 240
+	case id == 202:
+		// Purpur pillar is a variant of purpur block, and both of them keep the axis in the bits 2-3.
+		id, meta = 201, 2|meta&12
+	case id == 204:
+		id, meta = 181, 1
+	case id == 205:
+		id, meta = 182, 1|meta&8
+	case id == 207:
+		// The age of Java Edition is 0-3, and the growth of Bedrock Edition is 0-7.
+		id, meta = 244, [...]uint16{0, 2, 4, 7}[meta&3]
+	case id == 208:
+		id, meta = 198, 0
+	case id == 210 || id == 211:
+		id -= 22
+	case id == 212:
+		id = 207
+	case id == 218:
+		id = 251
+	case id >= 219 && id <= 234:
+		id, meta = 218, id-219
+	case id >= 235 && id <= 250:
+		// The facing of Java Edition is 0-3 for south, west, north and east.
+		id, meta = javaGlazedTerracotta[id-235], [...]uint16{3, 4, 2, 5}[meta&3]
+	case id == 251 || id == 252:
+		id -= 15
+	case id == 255:
+		id = 252
+	}
+	return block.LegacyToRuntimeID(id, meta)
+}
@@ -0,0 +1,151 @@
+package structure
+
+import (
+	"maps"
+	"math"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// VoidRuntimeID is a special block runtime ID in a Structure, which means there is no block at the position,
+// so the block in the world is not changed when the Structure is pasted. It is the block that the index -1
+// means in a .mcstructure file.
+const VoidRuntimeID uint32 = math.MaxUint32
+
+// MaxVolume is the largest count of the blocks of a Structure that is decoded from a file or created by the
+// C API, which keeps the two layers of blocks under 1 GiB, so that a crafted size could not exhaust the memory.
+const MaxVolume = 1 << 27
+
+// Structure is a cuboid of blocks, together with the block entities and the entities in it. Each block has
+// two layers, which are chunk.BlockLayer and chunk.LiquidLayer.
+//
+// All the positions in a Structure are relative to its lowest corner. Methods on Structure must not be called
+// simultaneously from multiple goroutines.
+type Structure struct {
+	// size is the size of the structure on the X, Y and Z axis.
+	size [3]int32
+	// origin is the position in the world of the lowest corner of the structure
+	// when the structure is captured, and it is only used as the metadata.
+	origin define.BlockPos
+
+	// blocks holds the block runtime IDs of the two layers,
+	// which are indexed by (x*size[1]+y)*size[2]+z.
+	blocks [2][]uint32
+	// blockEntities holds the NBT of the block entities,
+	// which is indexed by the relative position of them.
+	blockEntities map[define.BlockPos]map[string]any
+	// entities holds the NBT of the entities, whose "Pos" are relative
+	// to the lowest corner of the structure.
+	entities []map[string]any
+}
+
+// NewStructure creates a new Structure whose size is size. All the blocks in
+// chunk.BlockLayer are air, and the ones in chunk.LiquidLayer are VoidRuntimeID.
+func NewStructure(size [3]int32) *Structure {
+	s := &Structure{
+		size:          size,
+		blockEntities: make(map[define.BlockPos]map[string]any),
+		entities:      make([]map[string]any, 0),
+	}
+
+	volume := int(size[0]) * int(size[1]) * int(size[2])
+	s.blocks[0], s.blocks[1] = make([]uint32, volume), make([]uint32, volume)
+	for i := range volume {
+		s.blocks[0][i], s.blocks[1][i] = block.AirRuntimeID, VoidRuntimeID
+	}
+
+	return s
+}
+
+// Volume returns the count of the blocks of a Structure whose size is size. ok is false if any axis of
+// size is negative, or the volume is larger than MaxVolume.
+func Volume(size [3]int32) (volume int, ok bool) {
+	volume = 1
+	for _, length := range size {
+		if length < 0 {
+			return 0, false
+		}
+		// volume is no more than MaxVolume here, so this never overflows.
+		volume *= int(length)
+		if volume > MaxVolume {
+			return 0, false
+		}
+	}
+	return volume, true
+}
+
+// Size returns the size of the Structure on the X, Y and Z axis.
+func (s *Structure) Size() [3]int32 {
+	return s.size
+}
+
+// Origin returns the position in the world of the lowest corner of the Structure when it is captured.
+func (s *Structure) Origin() define.BlockPos {
+	return s.origin
+}
+
+// SetOrigin sets the position in the world of the lowest corner of the Structure.
+func (s *Structure) SetOrigin(origin define.BlockPos) {
+	s.origin = origin
+}
+
+// Contains reports whether the relative position x, y and z is in the Structure.
+func (s *Structure) Contains(x, y, z int32) bool {
+	return x >= 0 && y >= 0 && z >= 0 && x < s.size[0] && y < s.size[1] && z < s.size[2]
+}
+
+// index returns the index of the relative position x, y and z in the blocks.
+func (s *Structure) index(x, y, z int32) int {
+	return (int(x)*int(s.size[1])+int(y))*int(s.size[2]) + int(z)
+}
+
+// Block returns the runtime ID of the block at the relative position x, y and z in layer, where layer must
+// be 0 or 1. VoidRuntimeID is returned if the position or the layer is out of the Structure.
+func (s *Structure) Block(x, y, z int32, layer uint8) uint32 {
+	if layer > 1 || !s.Contains(x, y, z) {
+		return VoidRuntimeID
+	}
+	return s.blocks[layer][s.index(x, y, z)]
+}
+
+// SetBlock sets the runtime ID of the block at the relative position x, y and z in layer, where layer must
+// be 0 or 1. Nothing happens if the position or the layer is out of the Structure.
+func (s *Structure) SetBlock(x, y, z int32, layer uint8, runtimeID uint32) {
+	if layer > 1 || !s.Contains(x, y, z) {
+		return
+	}
+	s.blocks[layer][s.index(x, y, z)] = runtimeID
+}
+
+// BlockEntity returns the NBT of the block entity at the relative position pos. nil is returned if there
+// is no block entity at there.
+func (s *Structure) BlockEntity(pos define.BlockPos) map[string]any {
+	return s.blockEntities[pos]
+}
+
+// SetBlockEntity sets the NBT of the block entity at the relative position pos,
+// and the block entity is removed if nbt is nil.
+func (s *Structure) SetBlockEntity(pos define.BlockPos, nbt map[string]any) {
+	if nbt == nil {
+		delete(s.blockEntities, pos)
+		return
+	}
+	s.blockEntities[pos] = nbt
+}
+
+// BlockEntities returns the NBT of all block entities, which is indexed by their relative positions.
+// The returned map is a copy, but the NBT in it is not.
+func (s *Structure) BlockEntities() map[define.BlockPos]map[string]any {
+	return maps.Clone(s.blockEntities)
+}
+
+// Entities returns the NBT of all entities, whose "Pos" are relative to the lowest corner of the Structure.
+func (s *Structure) Entities() []map[string]any {
+	return s.entities
+}
+
+// SetEntities sets the NBT of all entities, whose "Pos" must be relative to the lowest corner of the Structure.
+func (s *Structure) SetEntities(entities []map[string]any) {
+	s.entities = entities
+}
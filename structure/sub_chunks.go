@@ -0,0 +1,88 @@
+package structure
+
+import (
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// FromSubChunks builds a Structure from a cuboid of sub chunks, which is indexed by subChunks[x][y][z].
+// The size of the Structure is 16 times of the count of the sub chunks on each axis, and the nil sub
+// chunks are treated as air. Only chunk.BlockLayer and chunk.LiquidLayer are kept.
+func FromSubChunks(subChunks [][][]*chunk.SubChunk) *Structure {
+	var size [3]int32
+	if len(subChunks) > 0 && len(subChunks[0]) > 0 {
+		size = [3]int32{int32(len(subChunks)) << 4, int32(len(subChunks[0])) << 4, int32(len(subChunks[0][0])) << 4}
+	}
+	s := NewStructure(size)
+
+	for subX, plane := range subChunks {
+		for subY, column := range plane {
+			for subZ, sub := range column {
+				if sub == nil {
+					sub = chunk.NewSubChunk(block.AirRuntimeID)
+				}
+				for layer := range uint8(2) {
+					blocks := sub.Blocks(layer)
+					for i, runtimeID := range blocks {
+						// The order of blocks is (x<<8)|(y<<4)|z.
+						x, y, z := int32(subX<<4|i>>8), int32(subY<<4|i>>4&15), int32(subZ<<4|i&15)
+						s.blocks[layer][s.index(x, y, z)] = runtimeID
+					}
+				}
+			}
+		}
+	}
+
+	return s
+}
+
+// SubChunks splits s into a cuboid of sub chunks, which is indexed by [x][y][z]. The lowest corner of s is
+// placed at the lowest corner of the first sub chunk, and the blocks out of s are air. VoidRuntimeID is
+// treated as air as well.
+//
+// The block entities and the entities are not included, because sub chunks could not hold them.
+func (s *Structure) SubChunks() [][][]*chunk.SubChunk {
+	counts := [3]int32{(s.size[0] + 15) >> 4, (s.size[1] + 15) >> 4, (s.size[2] + 15) >> 4}
+
+	result := make([][][]*chunk.SubChunk, counts[0])
+	for subX := range counts[0] {
+		result[subX] = make([][]*chunk.SubChunk, counts[1])
+		for subY := range counts[1] {
+			result[subX][subY] = make([]*chunk.SubChunk, counts[2])
+			for subZ := range counts[2] {
+				result[subX][subY][subZ] = s.subChunk(subX<<4, subY<<4, subZ<<4)
+			}
+		}
+	}
+
+	return result
+}
+
+// subChunk builds the sub chunk whose lowest corner is at the relative position startX, startY and startZ.
+func (s *Structure) subChunk(startX, startY, startZ int32) *chunk.SubChunk {
+	sub := chunk.NewSubChunk(block.AirRuntimeID)
+
+	for layer := range uint8(2) {
+		blocks, empty := make([]uint32, 4096), true
+		for i := range blocks {
+			runtimeID := s.Block(startX+int32(i>>8), startY+int32(i>>4&15), startZ+int32(i&15), layer)
+			if runtimeID == VoidRuntimeID {
+				runtimeID = block.AirRuntimeID
+			}
+			blocks[i], empty = runtimeID, empty && runtimeID == block.AirRuntimeID
+		}
+		if !empty {
+			sub.SetBlocks(layer, blocks)
+		}
+	}
+
+	return sub
+}
+
+// TransformSubChunks mirrors a cuboid of sub chunks by mirror and then rotates it by rotation, where
+// subChunks is indexed by [x][y][z]. The result is a new cuboid of sub chunks that indexed in the same
+// way, and the sub chunks passed are not changed. See Structure.Transform for more information.
+func TransformSubChunks(subChunks [][][]*chunk.SubChunk, rotation define.Rotation, mirror define.Mirror) [][][]*chunk.SubChunk {
+	return FromSubChunks(subChunks).Transform(rotation, mirror).SubChunks()
+}
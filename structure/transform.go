@@ -0,0 +1,132 @@
+package structure
+
+import (
+	"maps"
+	"math"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// Transform returns a new Structure that is s mirrored by mirror and then rotated by rotation around
+// its center, and s is not changed. The states of the blocks (e.g. facing_direction and pillar_axis)
+// are rewritten by block.Mirror and block.Rotate, and the positions and the rotations of the block
+// entities and the entities are transformed as well.
+//
+// The size on the X and Z axis are swapped if rotation is define.Rotation90 or define.Rotation270.
+func (s *Structure) Transform(rotation define.Rotation, mirror define.Mirror) *Structure {
+	rotation %= 4
+
+	newSize := s.size
+	if rotation%2 == 1 {
+		newSize[0], newSize[2] = s.size[2], s.size[0]
+	}
+	result := NewStructure(newSize)
+	result.origin = s.origin
+
+	for x := range s.size[0] {
+		for y := range s.size[1] {
+			for z := range s.size[2] {
+				newX, newZ := s.transformBlockPos(x, z, rotation, mirror)
+				from, to := s.index(x, y, z), result.index(newX, y, newZ)
+				for layer := range result.blocks {
+					result.blocks[layer][to] = transformBlock(s.blocks[layer][from], rotation, mirror)
+				}
+			}
+		}
+	}
+
+	for pos, nbt := range s.blockEntities {
+		newX, newZ := s.transformBlockPos(pos[0], pos[2], rotation, mirror)
+		newNBT := maps.Clone(nbt)
+		if yaw, ok := nbt["Rotation"].(float32); ok {
+			// Skulls hold their rotation in the block entity.
+			newNBT["Rotation"] = transformYaw(yaw, rotation, mirror)
+		}
+		result.blockEntities[define.BlockPos{newX, pos[1], newZ}] = newNBT
+	}
+
+	for _, nbt := range s.entities {
+		newNBT := maps.Clone(nbt)
+		if pos, ok := nbt["Pos"].([]any); ok && len(pos) == 3 {
+			x, _ := pos[0].(float32)
+			z, _ := pos[2].(float32)
+			newX, newZ := s.transformPos(x, z, rotation, mirror)
+			newNBT["Pos"] = []any{newX, pos[1], newZ}
+		}
+		if r, ok := nbt["Rotation"].([]any); ok && len(r) == 2 {
+			if yaw, ok := r[0].(float32); ok {
+				newNBT["Rotation"] = []any{transformYaw(yaw, rotation, mirror), r[1]}
+			}
+		}
+		result.entities = append(result.entities, newNBT)
+	}
+
+	return result
+}
+
+// Rotate returns a new Structure that is s rotated by rotation. See Transform for more information.
+func (s *Structure) Rotate(rotation define.Rotation) *Structure {
+	return s.Transform(rotation, define.MirrorNone)
+}
+
+// Mirror returns a new Structure that is s mirrored by mirror. See Transform for more information.
+func (s *Structure) Mirror(mirror define.Mirror) *Structure {
+	return s.Transform(define.Rotation0, mirror)
+}
+
+// transformBlockPos returns the relative position x and z of a block in the Structure after
+// mirrored by mirror and then rotated by rotation.
+func (s *Structure) transformBlockPos(x, z int32, rotation define.Rotation, mirror define.Mirror) (newX, newZ int32) {
+	sizeX, sizeZ := s.size[0], s.size[2]
+	switch mirror {
+	case define.MirrorX:
+		x = sizeX - 1 - x
+	case define.MirrorZ:
+		z = sizeZ - 1 - z
+	}
+	// Rotating 90 degrees clockwise turns north (-Z) to east (+X).
+	for range rotation {
+		x, z = sizeZ-1-z, x
+		sizeX, sizeZ = sizeZ, sizeX
+	}
+	return x, z
+}
+
+// transformPos is the same as transformBlockPos, but for the float position of an entity.
+func (s *Structure) transformPos(x, z float32, rotation define.Rotation, mirror define.Mirror) (newX, newZ float32) {
+	sizeX, sizeZ := float32(s.size[0]), float32(s.size[2])
+	switch mirror {
+	case define.MirrorX:
+		x = sizeX - x
+	case define.MirrorZ:
+		z = sizeZ - z
+	}
+	for range rotation {
+		x, z = sizeZ-z, x
+		sizeX, sizeZ = sizeZ, sizeX
+	}
+	return x, z
+}
+
+// transformBlock returns the runtime ID of the block whose runtime ID is runtimeID after
+// mirrored by mirror and then rotated by rotation.
+func transformBlock(runtimeID uint32, rotation define.Rotation, mirror define.Mirror) uint32 {
+	if runtimeID == VoidRuntimeID {
+		return VoidRuntimeID
+	}
+	return block.Rotate(block.Mirror(runtimeID, mirror), rotation)
+}
+
+// transformYaw returns yaw after mirrored by mirror and then rotated by rotation, where yaw
+// is in degrees, 0 is south and it increases clockwise. The result is in a range of -180-180.
+func transformYaw(yaw float32, rotation define.Rotation, mirror define.Mirror) float32 {
+	switch mirror {
+	case define.MirrorX:
+		yaw = -yaw
+	case define.MirrorZ:
+		yaw = 180 - yaw
+	}
+	yaw += 90 * float32(rotation)
+	return float32(math.Remainder(float64(yaw), 360))
+}
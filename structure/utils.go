@@ -0,0 +1,31 @@
+package structure
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// decodeJavaNBT decodes the big endian NBT of Java Edition from r, which is decompressed
+// first if it is compressed by gzip.
+func decodeJavaNBT(r io.Reader) (m map[string]any, err error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	} else {
+		r = br
+	}
+
+	if err = nbt.NewDecoderWithEncoding(r, nbt.BigEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode nbt: %w", err)
+	}
+	return m, nil
+}
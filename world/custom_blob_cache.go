@@ -0,0 +1,82 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// UpdateBlobHash computes the blob hashes of all sub chunks of the chunk which in position and
+// in dm dimension, and saves them by SaveFullSubChunkBlobHash. The hashes are the same as the
+// ones that sent to the client when the client blob cache is enabled (see chunk.NetworkEncodeBlobs).
+// If the chunk is not exist, then the blob hash data of this chunk will be delete.
+func (b *BedrockWorld) UpdateBlobHash(dm define.Dimension, position define.ChunkPos) error {
+	c, exists, err := b.LoadChunk(dm, position)
+	if err != nil {
+		return fmt.Errorf("UpdateBlobHash: %v", err)
+	}
+	if !exists {
+		return b.SaveFullSubChunkBlobHash(dm, position, nil)
+	}
+
+	_, blobs := chunk.NetworkEncodeBlobs(c, nil)
+	// The last blob is the biomes, which is not saved.
+	hashes := make([]define.HashWithPosY, 0, len(blobs)-1)
+	for index, blob := range blobs[:len(blobs)-1] {
+		hashes = append(hashes, define.HashWithPosY{
+			Hash: blob.Hash,
+			PosY: int8(c.SubY(int16(index)) >> 4),
+		})
+	}
+
+	err = b.SaveFullSubChunkBlobHash(dm, position, hashes)
+	if err != nil {
+		return fmt.Errorf("UpdateBlobHash: %v", err)
+	}
+	return nil
+}
+
+// ClientCacheMissResponse builds the answer of the ClientCacheBlobStatus packet sent by the client,
+// which holds the blobs that the client does not have.
+//
+// positions holds the chunks in dm dimension that sent to the client, and the blobs are found from
+// them by encoding them in the same way as chunk.NetworkEncodeBlobs. The hashes in status that could
+// not be found are ignored, and the hashes that the client already has are not answered.
+func (b *BedrockWorld) ClientCacheMissResponse(
+	dm define.Dimension,
+	positions []define.ChunkPos,
+	status *packet.ClientCacheBlobStatus,
+) (*packet.ClientCacheMissResponse, error) {
+	missing := make(map[uint64]bool)
+	for _, hash := range status.MissHashes {
+		missing[hash] = true
+	}
+
+	response := &packet.ClientCacheMissResponse{Blobs: make([]protocol.CacheBlob, 0, len(missing))}
+	for _, position := range positions {
+		if len(missing) == 0 {
+			break
+		}
+
+		c, exists, err := b.LoadChunk(dm, position)
+		if err != nil {
+			return nil, fmt.Errorf("ClientCacheMissResponse: %v", err)
+		}
+		if !exists {
+			continue
+		}
+
+		_, blobs := chunk.NetworkEncodeBlobs(c, nil)
+		for _, blob := range blobs {
+			if missing[blob.Hash] {
+				response.Blobs = append(response.Blobs, blob)
+				delete(missing, blob.Hash)
+			}
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,44 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+)
+
+// BlockStatistics counts every block in layer and every biome of the chunks in dm dimension whose
+// positions are in positions. If positions is nil, then all the chunks in dm dimension are counted.
+// The chunks that are not exist are skipped. Use define.ChunkPosArea to count the chunks in a region.
+//
+// The chunks are loaded one by one and counted by chunk.Chunk.BlockCounts and chunk.Chunk.BiomeCounts,
+// so the blocks are never expanded.
+func (b *BedrockWorld) BlockStatistics(dm define.Dimension, layer uint8, positions []define.ChunkPos) (result define.BlockStatistics, err error) {
+	if positions == nil {
+		positions, err = b.ChunkPositions(dm)
+		if err != nil {
+			return result, fmt.Errorf("BlockStatistics: %v", err)
+		}
+	}
+
+	result.Blocks = make(map[uint32]uint64)
+	result.Biomes = make(map[uint32]uint64)
+	for _, position := range positions {
+		c, exists, err := b.LoadChunk(dm, position)
+		if err != nil {
+			return result, fmt.Errorf("BlockStatistics: %v", err)
+		}
+		if !exists {
+			continue
+		}
+
+		for runtimeID, count := range c.BlockCounts(layer) {
+			result.Blocks[runtimeID] += uint64(count)
+		}
+		for biome, count := range c.BiomeCounts() {
+			result.Biomes[biome] += uint64(count)
+		}
+		result.Chunks++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/snbt"
+)
+
+// chunkDataJSON is the JSON representation of a chunk and the NBT data in it. See ExportChunkJSON for more
+// information.
+type chunkDataJSON struct {
+	Dimension     define.Dimension `json:"dimension"`
+	Position      define.ChunkPos  `json:"position"`
+	Chunk         *chunk.Chunk     `json:"chunk"`
+	BlockEntities []string         `json:"block_entities"`
+	Entities      []string         `json:"entities"`
+}
+
+// ExportChunkJSON exports the chunk at position in dm dimension as JSON, which holds the blocks, the biomes,
+// the block entities and the entities of the chunk. exists is false if the chunk is not exist. The format is
+// as follows:
+//
+//	{
+//	  "dimension": 0,
+//	  "position": [0, 0],
+//	  "chunk": {...},
+//	  "block_entities": ["{id:\"Chest\",x:1,y:64,z:2,...}", ...],
+//	  "entities": ["{UniqueID:-4294967295L,identifier:\"minecraft:pig\",...}", ...]
+//	}
+//
+// chunk is the JSON of chunk.Chunk, see chunk.Chunk.MarshalJSON for more information. Each block entity and
+// entity is written as stringified NBT (SNBT) instead of a JSON object, so that the types of the values are
+// kept, see snbt.Marshal for more information. The block entities and the entities are in the order they
+// are stored.
+func (b *BedrockWorld) ExportChunkJSON(dm define.Dimension, position define.ChunkPos) (data []byte, exists bool, err error) {
+	c, exists, err := b.LoadChunk(dm, position)
+	if err != nil {
+		return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+	}
+	if !exists {
+		return nil, false, nil
+	}
+
+	blockEntities, err := b.LoadNBT(dm, position)
+	if err != nil {
+		return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+	}
+	entities, err := b.LoadEntities(dm, position)
+	if err != nil {
+		return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+	}
+
+	result := chunkDataJSON{
+		Dimension:     dm,
+		Position:      position,
+		Chunk:         c,
+		BlockEntities: make([]string, len(blockEntities)),
+		Entities:      make([]string, len(entities)),
+	}
+	for index, blockEntity := range blockEntities {
+		if result.BlockEntities[index], err = snbt.Marshal(blockEntity); err != nil {
+			return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+		}
+	}
+	for index, entity := range entities {
+		if result.Entities[index], err = snbt.Marshal(entity); err != nil {
+			return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+		}
+	}
+
+	data, err = json.Marshal(result)
+	if err != nil {
+		return nil, false, fmt.Errorf("ExportChunkJSON: %v", err)
+	}
+	return data, true, nil
+}
+
+// ImportChunkJSON imports the chunk exported by ExportChunkJSON, which is saved to the dimension and the
+// position in data. The chunk, its block entities and its entities are replaced. dm and position are the
+// dimension and the position of the imported chunk.
+//
+// The range of the chunk must be the same as the one of the dimension, and each entity must hold a
+// "UniqueID" of long, see SaveEntities for more information.
+func (b *BedrockWorld) ImportChunkJSON(data []byte) (dm define.Dimension, position define.ChunkPos, err error) {
+	var src chunkDataJSON
+	if err = json.Unmarshal(data, &src); err != nil {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: %v", err)
+	}
+	if src.Chunk == nil {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: chunk is missing")
+	}
+	if r := src.Dimension.Range(); src.Chunk.Range() != r {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: range %v of the chunk does not match the range %v of %v", src.Chunk.Range(), r, src.Dimension)
+	}
+
+	blockEntities := make([]map[string]any, len(src.BlockEntities))
+	for index, s := range src.BlockEntities {
+		if blockEntities[index], err = snbt.UnmarshalCompound(s); err != nil {
+			return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: block entity %d: %v", index, err)
+		}
+	}
+	entities := make([]map[string]any, len(src.Entities))
+	for index, s := range src.Entities {
+		if entities[index], err = snbt.UnmarshalCompound(s); err != nil {
+			return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: entity %d: %v", index, err)
+		}
+	}
+
+	if err = b.SaveChunk(src.Dimension, src.Position, src.Chunk); err != nil {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: %v", err)
+	}
+	if err = b.SaveNBT(src.Dimension, src.Position, blockEntities); err != nil {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: %v", err)
+	}
+	if err = b.SaveEntities(src.Dimension, src.Position, entities); err != nil {
+		return 0, define.ChunkPos{}, fmt.Errorf("ImportChunkJSON: %v", err)
+	}
+	return src.Dimension, src.Position, nil
+}
@@ -0,0 +1,50 @@
+package world
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	world_define "github.com/TriM-Organization/bedrock-world-operator/world/define"
+)
+
+// ChunkPositions returns the positions of all chunks in dm dimension that exist in the world,
+// which are the chunks that have a version. The positions are in the order of the keys in
+// the leveldb database.
+func (b *BedrockWorld) ChunkPositions(dm define.Dimension) ([]define.ChunkPos, error) {
+	result := make([]define.ChunkPos, 0)
+
+	err := b.Keys(nil, func(key []byte) bool {
+		position, suffix, ok := parseChunkKey(dm, key, 1)
+		if ok && (suffix[0] == world_define.KeyVersion || suffix[0] == world_define.KeyVersionOld) {
+			result = append(result, position)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ChunkPositions: %v", err)
+	}
+
+	return result, nil
+}
+
+// parseChunkKey parses key which is world_define.Sum(dm, position, suffix...), and
+// len(suffix) must be suffixLength. ok is false if key is not such a key.
+func parseChunkKey(dm define.Dimension, key []byte, suffixLength int) (position define.ChunkPos, suffix []byte, ok bool) {
+	indexLength := 8
+	if dm != define.DimensionIDOverworld {
+		indexLength = 12
+	}
+	if len(key) != indexLength+suffixLength {
+		return define.ChunkPos{}, nil, false
+	}
+	if dm != define.DimensionIDOverworld && binary.LittleEndian.Uint32(key[8:12]) != uint32(dm) {
+		return define.ChunkPos{}, nil, false
+	}
+
+	position = define.ChunkPos{
+		int32(binary.LittleEndian.Uint32(key[0:4])),
+		int32(binary.LittleEndian.Uint32(key[4:8])),
+	}
+	return position, key[indexLength:], true
+}
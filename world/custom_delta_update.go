@@ -1,8 +1,15 @@
 package world
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
 	"github.com/TriM-Organization/bedrock-world-operator/define"
 	world_define "github.com/TriM-Organization/bedrock-world-operator/world/define"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
 )
 
 func (b *BedrockWorld) LoadDeltaUpdate(dm define.Dimension, position define.ChunkPos) ([]byte, error) {
@@ -21,3 +28,309 @@ func (b *BedrockWorld) SaveDeltaUpdate(dm define.Dimension, position define.Chun
 	}
 	return b.Put(key, payload)
 }
+
+// AppendDeltaUpdate appends update to the pending delta updates of the chunk which in position and
+// in dm dimension, and sets the delta update time stamp to timeStamp. The pending delta updates
+// are not decoded, so it is cheap to call this function for every recorded change.
+func (b *BedrockWorld) AppendDeltaUpdate(dm define.Dimension, position define.ChunkPos, update define.DeltaUpdate, timeStamp int64) error {
+	payload, err := b.LoadDeltaUpdate(dm, position)
+	if err != nil {
+		return fmt.Errorf("AppendDeltaUpdate: %v", err)
+	}
+
+	encoded, err := EncodeDeltaUpdate(update)
+	if err != nil {
+		return fmt.Errorf("AppendDeltaUpdate: %v", err)
+	}
+
+	err = b.SaveDeltaUpdate(dm, position, append(payload, encoded...))
+	if err != nil {
+		return fmt.Errorf("AppendDeltaUpdate: %v", err)
+	}
+
+	err = b.SaveDeltaUpdateTimeStamp(dm, position, timeStamp)
+	if err != nil {
+		return fmt.Errorf("AppendDeltaUpdate: %v", err)
+	}
+
+	return nil
+}
+
+// ApplyDeltaUpdates folds the pending delta updates of the chunk which in position and in dm dimension
+// into the chunk, and then clears them. The time stamp of the chunk is set to the delta update time stamp.
+//
+// Only the sub chunks that have block changes are loaded and saved, so the other parts of the chunk are not
+// touched. If the chunk is not exist, then it is created. If there are no pending delta updates, then nothing
+// will happen.
+//
+// An error is returned without changing anything if any change is out of the chunk (X or Z is bigger than 15)
+// or any block change is in a layer other than 0 and 1, or if a sub chunk to change could not be decoded.
+func (b *BedrockWorld) ApplyDeltaUpdates(dm define.Dimension, position define.ChunkPos) error {
+	payload, err := b.LoadDeltaUpdate(dm, position)
+	if err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+
+	updates, err := DecodeDeltaUpdates(payload)
+	if err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+	if err = checkDeltaUpdates(updates); err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+
+	// Group the block changes by the sub chunks they are in,
+	// so that each sub chunk is only loaded and saved once.
+	r := dm.Range()
+	subChunks := make(map[int32]*chunk.SubChunk)
+	for _, update := range updates {
+		for _, change := range update.BlockChanges {
+			if int(change.Y) < r[0] || int(change.Y) > r[1] {
+				continue
+			}
+
+			subY := int32(change.Y) >> 4
+			sub, ok := subChunks[subY]
+			if !ok {
+				sub, err = b.loadSubChunkForUpdate(dm, define.SubChunkPos{position[0], subY, position[1]})
+				if err != nil {
+					return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+				}
+				subChunks[subY] = sub
+			}
+
+			sub.SetBlock(change.X, uint8(change.Y), change.Z, change.Layer, change.BlockRuntimeID)
+		}
+	}
+	for subY, sub := range subChunks {
+		err = b.SaveSubChunk(dm, define.SubChunkPos{position[0], subY, position[1]}, sub)
+		if err != nil {
+			return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+		}
+	}
+
+	if err = b.applyNBTChanges(dm, position, updates); err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+
+	if timeStamp := b.LoadDeltaUpdateTimeStamp(dm, position); timeStamp != 0 {
+		err = b.SaveTimeStamp(dm, position, timeStamp)
+		if err != nil {
+			return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+		}
+	}
+
+	err = b.SaveDeltaUpdate(dm, position, nil)
+	if err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+	err = b.SaveDeltaUpdateTimeStamp(dm, position, 0)
+	if err != nil {
+		return fmt.Errorf("ApplyDeltaUpdates: %v", err)
+	}
+
+	return nil
+}
+
+// checkDeltaUpdates checks if all the changes in updates are in the chunk, and all the block
+// changes are in layer 0 or 1, so that they could be applied to the right blocks.
+func checkDeltaUpdates(updates []define.DeltaUpdate) error {
+	for _, update := range updates {
+		for _, change := range update.BlockChanges {
+			if change.X > 15 || change.Z > 15 {
+				return fmt.Errorf("block change at (%d, %d, %d) is out of the chunk", change.X, change.Y, change.Z)
+			}
+			if change.Layer > 1 {
+				return fmt.Errorf("block change at (%d, %d, %d) is in invalid layer %d", change.X, change.Y, change.Z, change.Layer)
+			}
+		}
+		for _, change := range update.NBTChanges {
+			if change.X > 15 || change.Z > 15 {
+				return fmt.Errorf("NBT change at (%d, %d, %d) is out of the chunk", change.X, change.Y, change.Z)
+			}
+		}
+	}
+	return nil
+}
+
+// loadSubChunkForUpdate loads the sub chunk at position, which the block changes are applied to. An empty
+// sub chunk is returned if it is not exist, but an error is returned if it could not be decoded, so that it
+// is not overwritten by the empty one.
+func (b *BedrockWorld) loadSubChunkForUpdate(dm define.Dimension, position define.SubChunkPos) (*chunk.SubChunk, error) {
+	key := world_define.Sum(dm, define.ChunkPos{position[0], position[2]}, world_define.KeySubChunkData, byte(position[1]))
+	data, err := b.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return chunk.NewSubChunk(block.AirRuntimeID), nil
+	}
+
+	sub, _, err := chunk.DecodeSubChunk(bytes.NewBuffer(data), dm.Range(), chunk.DiskEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("decode sub chunk %v: %v", position, err)
+	}
+	return sub, nil
+}
+
+// applyNBTChanges applies the NBT changes in updates to the block entities of the
+// chunk which in position and in dm dimension. The block entities are not loaded
+// if there are no NBT changes.
+func (b *BedrockWorld) applyNBTChanges(dm define.Dimension, position define.ChunkPos, updates []define.DeltaUpdate) error {
+	changes := make([]define.NBTChange, 0)
+	for _, update := range updates {
+		changes = append(changes, update.NBTChanges...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	nbts, err := b.LoadNBT(dm, position)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		x, y, z := position[0]<<4+int32(change.X), int32(change.Y), position[1]<<4+int32(change.Z)
+
+		newNBTs := make([]map[string]any, 0, len(nbts)+1)
+		for _, value := range nbts {
+			vx, _ := value["x"].(int32)
+			vy, _ := value["y"].(int32)
+			vz, _ := value["z"].(int32)
+			if vx != x || vy != y || vz != z {
+				newNBTs = append(newNBTs, value)
+			}
+		}
+
+		if change.NBT != nil {
+			change.NBT["x"], change.NBT["y"], change.NBT["z"] = x, y, z
+			newNBTs = append(newNBTs, change.NBT)
+		}
+		nbts = newNBTs
+	}
+
+	return b.SaveNBT(dm, position, nbts)
+}
+
+// blockChangeSize is the size of an encoded block change, and minNBTChangeSize is the size
+// of an encoded NBT change without NBT. See EncodeDeltaUpdate for the format of them.
+var blockChangeSize = binary.Size(define.BlockChange{})
+
+const minNBTChangeSize = 8
+
+// EncodeDeltaUpdate encodes update to its binary represent, and the
+// encoded delta updates could be simply joined one after another.
+// The format of it is (all in little endian):
+//
+//	uint32: The count of the block changes
+//	for each block change:
+//		uint8:  X
+//		int16:  Y
+//		uint8:  Z
+//		uint8:  Layer
+//		uint32: Block runtime ID
+//	uint32: The count of the NBT changes
+//	for each NBT change:
+//		uint8:  X
+//		int16:  Y
+//		uint8:  Z
+//		uint32: The length of NBT, and 0 means the block entity is removed
+//		[]byte: NBT in little endian
+func EncodeDeltaUpdate(update define.DeltaUpdate) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(update.BlockChanges)))
+	for _, change := range update.BlockChanges {
+		_ = binary.Write(buf, binary.LittleEndian, change)
+	}
+
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(update.NBTChanges)))
+	for _, change := range update.NBTChanges {
+		_ = buf.WriteByte(change.X)
+		_ = binary.Write(buf, binary.LittleEndian, change.Y)
+		_ = buf.WriteByte(change.Z)
+
+		if change.NBT == nil {
+			_ = binary.Write(buf, binary.LittleEndian, uint32(0))
+			continue
+		}
+
+		nbtBuf := bytes.NewBuffer(nil)
+		if err := nbt.NewEncoderWithEncoding(nbtBuf, nbt.LittleEndian).Encode(change.NBT); err != nil {
+			return nil, fmt.Errorf("EncodeDeltaUpdate: %v", err)
+		}
+		_ = binary.Write(buf, binary.LittleEndian, uint32(nbtBuf.Len()))
+		_, _ = buf.Write(nbtBuf.Bytes())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeDeltaUpdates decodes all the delta updates that joined in payload.
+// See EncodeDeltaUpdate for the format of them.
+func DecodeDeltaUpdates(payload []byte) (result []define.DeltaUpdate, err error) {
+	buf := bytes.NewBuffer(payload)
+
+	for buf.Len() > 0 {
+		var update define.DeltaUpdate
+		var count uint32
+
+		if err = binary.Read(buf, binary.LittleEndian, &count); err != nil {
+			return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+		}
+		// The counts are checked by the remaining bytes before the changes are
+		// allocated, so that a broken payload could not exhaust the memory.
+		if uint64(count)*uint64(blockChangeSize) > uint64(buf.Len()) {
+			return nil, fmt.Errorf("DecodeDeltaUpdates: %d block changes need %d bytes, but only %d remain", count, uint64(count)*uint64(blockChangeSize), buf.Len())
+		}
+		update.BlockChanges = make([]define.BlockChange, count)
+		if err = binary.Read(buf, binary.LittleEndian, update.BlockChanges); err != nil {
+			return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+		}
+
+		if err = binary.Read(buf, binary.LittleEndian, &count); err != nil {
+			return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+		}
+		if uint64(count) > uint64(buf.Len()/minNBTChangeSize) {
+			return nil, fmt.Errorf("DecodeDeltaUpdates: %d NBT changes need at least %d bytes, but only %d remain", count, uint64(count)*minNBTChangeSize, buf.Len())
+		}
+		update.NBTChanges = make([]define.NBTChange, count)
+		for i := range update.NBTChanges {
+			change := &update.NBTChanges[i]
+
+			var length uint32
+			if err = binary.Read(buf, binary.LittleEndian, &change.X); err != nil {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+			}
+			if err = binary.Read(buf, binary.LittleEndian, &change.Y); err != nil {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+			}
+			if err = binary.Read(buf, binary.LittleEndian, &change.Z); err != nil {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+			}
+			if err = binary.Read(buf, binary.LittleEndian, &length); err != nil {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+			}
+			if length == 0 {
+				continue
+			}
+
+			nbtData := buf.Next(int(length))
+			if len(nbtData) != int(length) {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: Expect %d bytes of NBT, but got %d", length, len(nbtData))
+			}
+			err = nbt.NewDecoderWithEncoding(bytes.NewBuffer(nbtData), nbt.LittleEndian).Decode(&change.NBT)
+			if err != nil {
+				return nil, fmt.Errorf("DecodeDeltaUpdates: %v", err)
+			}
+		}
+
+		result = append(result, update)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package world
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	world_define "github.com/TriM-Organization/bedrock-world-operator/world/define"
+)
+
+// openTestWorld opens an empty world in a temporary folder, which is closed when the test ends.
+func openTestWorld(t *testing.T) *BedrockWorld {
+	w, err := Open(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = w.CloseWorld() })
+	return w
+}
+
+func TestDeltaUpdateRoundTrip(t *testing.T) {
+	update := define.DeltaUpdate{
+		BlockChanges: []define.BlockChange{{X: 1, Y: -60, Z: 15, Layer: 1, BlockRuntimeID: block.WaterRuntimeID}},
+		NBTChanges: []define.NBTChange{
+			{X: 2, Y: 70, Z: 3, NBT: map[string]any{"id": "Chest"}},
+			{X: 4, Y: 5, Z: 6},
+		},
+	}
+	encoded, err := EncodeDeltaUpdate(update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, err := DecodeDeltaUpdates(append(encoded, encoded...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 delta updates, but got %d", len(updates))
+	}
+	for _, got := range updates {
+		if len(got.BlockChanges) != 1 || got.BlockChanges[0] != update.BlockChanges[0] {
+			t.Fatalf("expected block changes %v, but got %v", update.BlockChanges, got.BlockChanges)
+		}
+		if len(got.NBTChanges) != 2 || got.NBTChanges[0].NBT["id"] != "Chest" || got.NBTChanges[1].NBT != nil {
+			t.Fatalf("expected NBT changes %v, but got %v", update.NBTChanges, got.NBTChanges)
+		}
+	}
+}
+
+func TestDecodeDeltaUpdatesCorrupt(t *testing.T) {
+	huge := binary.LittleEndian.AppendUint32(nil, 0xffffffff)
+	for _, payload := range [][]byte{
+		// A huge count of block changes.
+		huge,
+		// No block change, but a huge count of NBT changes.
+		append(binary.LittleEndian.AppendUint32(nil, 0), huge...),
+		// A block change that is cut.
+		append(binary.LittleEndian.AppendUint32(nil, 1), 1, 2, 3),
+	} {
+		if _, err := DecodeDeltaUpdates(payload); err == nil {
+			t.Fatalf("expected an error for %v", payload)
+		}
+	}
+}
+
+func TestApplyDeltaUpdates(t *testing.T) {
+	w := openTestWorld(t)
+	stone, _ := block.StateToRuntimeID("minecraft:stone", nil)
+	position := define.ChunkPos{3, -2}
+	update := define.DeltaUpdate{BlockChanges: []define.BlockChange{{X: 1, Y: 2, Z: 3, BlockRuntimeID: stone}}}
+	if err := w.AppendDeltaUpdate(define.DimensionIDOverworld, position, update, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ApplyDeltaUpdates(define.DimensionIDOverworld, position); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := w.LoadSubChunk(define.DimensionIDOverworld, define.SubChunkPos{position[0], 0, position[1]})
+	if sub == nil || sub.Block(1, 2, 3, 0) != stone {
+		t.Fatal("expected stone at (1, 2, 3) after the delta updates are applied")
+	}
+	if payload, _ := w.LoadDeltaUpdate(define.DimensionIDOverworld, position); len(payload) != 0 {
+		t.Fatal("expected the delta updates to be cleared")
+	}
+}
+
+func TestApplyDeltaUpdatesInvalid(t *testing.T) {
+	w := openTestWorld(t)
+	stone, _ := block.StateToRuntimeID("minecraft:stone", nil)
+	position := define.ChunkPos{0, 0}
+
+	// The sub chunk at Y 0 could not be decoded, so it must not be overwritten.
+	broken := []byte{0xff, 0xff, 0xff}
+	key := world_define.Sum(define.DimensionIDOverworld, position, world_define.KeySubChunkData, 0)
+	if err := w.Put(key, broken); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range []define.BlockChange{
+		{X: 1, Y: 2, Z: 3, BlockRuntimeID: stone},
+		{X: 16, Y: 20, Z: 0, BlockRuntimeID: stone},
+		{X: 0, Y: 20, Z: 16, BlockRuntimeID: stone},
+		{X: 0, Y: 20, Z: 0, Layer: 2, BlockRuntimeID: stone},
+	} {
+		update := define.DeltaUpdate{BlockChanges: []define.BlockChange{change}}
+		if err := w.AppendDeltaUpdate(define.DimensionIDOverworld, position, update, 100); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.ApplyDeltaUpdates(define.DimensionIDOverworld, position); err == nil {
+			t.Fatalf("expected an error for block change %+v", change)
+		}
+		if err := w.SaveDeltaUpdate(define.DimensionIDOverworld, position, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if data, _ := w.Get(key); !bytes.Equal(data, broken) {
+		t.Fatalf("expected the broken sub chunk to be kept, but got %v", data)
+	}
+	if sub := w.LoadSubChunk(define.DimensionIDOverworld, define.SubChunkPos{0, 1, 0}); sub != nil && !sub.Empty() {
+		t.Fatal("expected sub chunk 1 not to be changed by the invalid block changes")
+	}
+}
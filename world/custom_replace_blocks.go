@@ -0,0 +1,76 @@
+package world
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	world_define "github.com/TriM-Organization/bedrock-world-operator/world/define"
+)
+
+// ReplaceBlocks replaces the blocks in layer of all chunks in dm dimension by mapping, whose keys are
+// the runtime IDs of the blocks to replace and values are the runtime IDs of the new blocks.
+// See ReplaceFunc for more information.
+func (b *BedrockWorld) ReplaceBlocks(dm define.Dimension, layer uint8, mapping map[uint32]uint32) (modified int, err error) {
+	modified, err = b.ReplaceFunc(dm, layer, func(runtimeID uint32) uint32 {
+		if newRuntimeID, ok := mapping[runtimeID]; ok {
+			return newRuntimeID
+		}
+		return runtimeID
+	})
+	if err != nil {
+		return modified, fmt.Errorf("ReplaceBlocks: %v", err)
+	}
+	return modified, nil
+}
+
+// ReplaceFunc replaces the blocks in layer of all chunks in dm dimension by f, which returns the
+// runtime ID of the new block. The sub chunks are streamed from the leveldb database one by one, and
+// only the modified ones are saved back, so the whole world is never loaded into memory. See
+// chunk.SubChunk.ReplaceFunc for how the blocks are replaced.
+//
+// Note that only the sub chunks that are stored in the world are replaced, and modified is the count
+// of the modified sub chunks. The blob hashes of the modified sub chunks are not updated.
+func (b *BedrockWorld) ReplaceFunc(dm define.Dimension, layer uint8, f func(runtimeID uint32) uint32) (modified int, err error) {
+	r := dm.Range()
+
+	keys := make([][]byte, 0)
+	err = b.Keys(nil, func(key []byte) bool {
+		_, suffix, ok := parseChunkKey(dm, key, 2)
+		if ok && suffix[0] == world_define.KeySubChunkData {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ReplaceFunc: %v", err)
+	}
+
+	for _, key := range keys {
+		data, err := b.Get(key)
+		if err != nil {
+			return modified, fmt.Errorf("ReplaceFunc: %v", err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		sub, _, err := chunk.DecodeSubChunk(bytes.NewBuffer(data), r, chunk.DiskEncoding)
+		if err != nil {
+			return modified, fmt.Errorf("ReplaceFunc: %v", err)
+		}
+		if !sub.ReplaceFunc(layer, f) {
+			continue
+		}
+
+		index := int(int8(key[len(key)-1])) - r[0]>>4
+		err = b.Put(key, chunk.EncodeSubChunk(sub, r, index, chunk.DiskEncoding))
+		if err != nil {
+			return modified, fmt.Errorf("ReplaceFunc: %v", err)
+		}
+		modified++
+	}
+
+	return modified, nil
+}
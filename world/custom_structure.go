@@ -0,0 +1,168 @@
+package world
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/TriM-Organization/bedrock-world-operator/block"
+	"github.com/TriM-Organization/bedrock-world-operator/chunk"
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/structure"
+)
+
+// CaptureStructure captures the cuboid between start and end (both inclusive) in dm dimension as a
+// structure.Structure, whose origin is the lowest corner of the cuboid. Both chunk.BlockLayer and
+// chunk.LiquidLayer are captured, where the air in chunk.LiquidLayer is captured as structure.VoidRuntimeID
+// just like the game does. The blocks out of the height range of dm and in the chunks that are not exist
+// are captured as air.
+//
+// The block entities in the cuboid are captured as well, but the entities are not, because they are not
+// stored in the chunks.
+func (b *BedrockWorld) CaptureStructure(dm define.Dimension, start, end define.BlockPos) (*structure.Structure, error) {
+	low := define.BlockPos{min(start[0], end[0]), min(start[1], end[1]), min(start[2], end[2])}
+	high := define.BlockPos{max(start[0], end[0]), max(start[1], end[1]), max(start[2], end[2])}
+	r := dm.Range()
+
+	s := structure.NewStructure([3]int32(high.Sub(low).Add(define.BlockPos{1, 1, 1})))
+	s.SetOrigin(low)
+
+	for _, position := range define.ChunkPosArea(low.ChunkPos(), high.ChunkPos()) {
+		c, exists, err := b.LoadChunk(dm, position)
+		if err != nil {
+			return nil, fmt.Errorf("CaptureStructure: %v", err)
+		}
+		if !exists {
+			c = chunk.NewChunk(block.AirRuntimeID, r)
+		}
+
+		// The part of the cuboid in the chunk, which is relative to the chunk.
+		fromX, toX := max(low[0]-position[0]<<4, 0), min(high[0]-position[0]<<4, 15)
+		fromZ, toZ := max(low[2]-position[1]<<4, 0), min(high[2]-position[1]<<4, 15)
+		fromY, toY := max(low[1], int32(r[0])), min(high[1], int32(r[1]))
+
+		for x := fromX; x <= toX; x++ {
+			for z := fromZ; z <= toZ; z++ {
+				for y := fromY; y <= toY; y++ {
+					relX, relY, relZ := position[0]<<4+x-low[0], y-low[1], position[1]<<4+z-low[2]
+					s.SetBlock(relX, relY, relZ, chunk.BlockLayer, c.Block(uint8(x), int16(y), uint8(z), chunk.BlockLayer))
+					if liquid := c.Block(uint8(x), int16(y), uint8(z), chunk.LiquidLayer); liquid != block.AirRuntimeID {
+						s.SetBlock(relX, relY, relZ, chunk.LiquidLayer, liquid)
+					}
+				}
+			}
+		}
+
+		blockEntities, err := b.LoadNBT(dm, position)
+		if err != nil {
+			return nil, fmt.Errorf("CaptureStructure: %v", err)
+		}
+		for _, nbt := range blockEntities {
+			pos, ok := blockEntityPos(nbt)
+			if !ok {
+				continue
+			}
+			if relative := pos.Sub(low); s.Contains(relative[0], relative[1], relative[2]) {
+				s.SetBlockEntity(relative, nbt)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// PasteStructure pastes s into dm dimension, and the lowest corner of s is placed at origin. The chunks
+// that are not exist are created, and the blocks out of the height range of dm are dropped.
+//
+// The positions whose block in chunk.BlockLayer is structure.VoidRuntimeID are not changed. For the others,
+// the blocks of both layers are replaced, where structure.VoidRuntimeID in chunk.LiquidLayer means air, and
+// the block entities at there are replaced by the ones of s, whose x, y and z are set to the positions in
+// the world. The entities of s are not pasted.
+//
+// Note that the blob hashes of the modified chunks are not updated.
+func (b *BedrockWorld) PasteStructure(dm define.Dimension, origin define.BlockPos, s *structure.Structure) error {
+	size := s.Size()
+	if size[0] <= 0 || size[1] <= 0 || size[2] <= 0 {
+		return nil
+	}
+	high := origin.Add(define.BlockPos(size)).Sub(define.BlockPos{1, 1, 1})
+	r := dm.Range()
+
+	structureBlockEntities := s.BlockEntities()
+	for _, position := range define.ChunkPosArea(origin.ChunkPos(), high.ChunkPos()) {
+		c, exists, err := b.LoadChunk(dm, position)
+		if err != nil {
+			return fmt.Errorf("PasteStructure: %v", err)
+		}
+		if !exists {
+			c = chunk.NewChunk(block.AirRuntimeID, r)
+		}
+
+		// The part of the structure in the chunk, which is relative to the chunk.
+		fromX, toX := max(origin[0]-position[0]<<4, 0), min(high[0]-position[0]<<4, 15)
+		fromZ, toZ := max(origin[2]-position[1]<<4, 0), min(high[2]-position[1]<<4, 15)
+		fromY, toY := max(origin[1], int32(r[0])), min(high[1], int32(r[1]))
+
+		replaced := make(map[define.BlockPos]bool)
+		for x := fromX; x <= toX; x++ {
+			for z := fromZ; z <= toZ; z++ {
+				for y := fromY; y <= toY; y++ {
+					relX, relY, relZ := position[0]<<4+x-origin[0], y-origin[1], position[1]<<4+z-origin[2]
+					runtimeID := s.Block(relX, relY, relZ, chunk.BlockLayer)
+					if runtimeID == structure.VoidRuntimeID {
+						continue
+					}
+					liquid := s.Block(relX, relY, relZ, chunk.LiquidLayer)
+					if liquid == structure.VoidRuntimeID {
+						liquid = block.AirRuntimeID
+					}
+					c.SetBlock(uint8(x), int16(y), uint8(z), chunk.BlockLayer, runtimeID)
+					c.SetBlock(uint8(x), int16(y), uint8(z), chunk.LiquidLayer, liquid)
+					replaced[define.BlockPos{position[0]<<4 + x, y, position[1]<<4 + z}] = true
+				}
+			}
+		}
+		if len(replaced) == 0 {
+			continue
+		}
+
+		oldBlockEntities, err := b.LoadNBT(dm, position)
+		if err != nil {
+			return fmt.Errorf("PasteStructure: %v", err)
+		}
+		blockEntities := make([]map[string]any, 0, len(oldBlockEntities))
+		for _, nbt := range oldBlockEntities {
+			if pos, ok := blockEntityPos(nbt); !ok || !replaced[pos] {
+				blockEntities = append(blockEntities, nbt)
+			}
+		}
+		for pos := range replaced {
+			nbt, ok := structureBlockEntities[pos.Sub(origin)]
+			if !ok {
+				continue
+			}
+			nbt = maps.Clone(nbt)
+			nbt["x"], nbt["y"], nbt["z"] = pos[0], pos[1], pos[2]
+			blockEntities = append(blockEntities, nbt)
+		}
+
+		c.Compact()
+		if err = b.SaveChunk(dm, position, c); err != nil {
+			return fmt.Errorf("PasteStructure: %v", err)
+		}
+		if err = b.SaveNBT(dm, position, blockEntities); err != nil {
+			return fmt.Errorf("PasteStructure: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// blockEntityPos returns the position in the world of the block entity whose NBT is nbt.
+func blockEntityPos(nbt map[string]any) (pos define.BlockPos, ok bool) {
+	for i, key := range [3]string{"x", "y", "z"} {
+		if pos[i], ok = nbt[key].(int32); !ok {
+			return pos, false
+		}
+	}
+	return pos, true
+}
@@ -1,8 +1,11 @@
 package world
 
 import (
+	"slices"
+
 	"github.com/deatil/go-cryptobin/cryptobin/crypto"
 	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
 )
 
 // Database wrapper a level database,
@@ -74,6 +77,22 @@ func (db *database) Delete(key []byte) error {
 	return db.ldb.Delete(key, nil)
 }
 
+// Keys calls f with every key in the DB that starts with prefix in order,
+// until f returns false. A nil prefix means all keys.
+//
+// The keys are read from a snapshot of the DB, so it is safe to modify
+// the DB in f. It is safe to modify the contents of the key passed to f.
+func (db *database) Keys(prefix []byte, f func(key []byte) bool) error {
+	iter := db.ldb.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		if !f(slices.Clone(iter.Key())) {
+			break
+		}
+	}
+	return iter.Error()
+}
+
 // Close closes the DB. This will also releases any outstanding snapshot,
 // abort any in-flight compaction and discard open transaction.
 //
@@ -3,7 +3,9 @@ package world
 import (
 	"github.com/TriM-Organization/bedrock-world-operator/chunk"
 	"github.com/TriM-Organization/bedrock-world-operator/define"
+	"github.com/TriM-Organization/bedrock-world-operator/structure"
 	"github.com/TriM-Organization/bedrock-world-operator/world/leveldat"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
 // LevelDB represent to a level database
@@ -13,6 +15,7 @@ type LevelDB interface {
 	Delete(key []byte) error
 	Get(key []byte) (value []byte, err error)
 	Has(key []byte) (has bool, err error)
+	Keys(prefix []byte, f func(key []byte) bool) error
 	Put(key []byte, value []byte) error
 }
 
@@ -27,6 +30,7 @@ type StandardBedrockWorld interface {
 	LoadBiomes(dm define.Dimension, position define.ChunkPos) ([]byte, error)
 	SaveBiomes(dm define.Dimension, position define.ChunkPos, payload []byte) error
 
+	ChunkPositions(dm define.Dimension) ([]define.ChunkPos, error)
 	LoadChunkPayloadOnly(dm define.Dimension, position define.ChunkPos) (subchunksBytes [][]byte, exists bool, err error)
 	LoadChunk(dm define.Dimension, position define.ChunkPos) (c *chunk.Chunk, exists bool, err error)
 	SaveChunkPayloadOnly(dm define.Dimension, position define.ChunkPos, subchunksBytes [][]byte) error
@@ -39,6 +43,9 @@ type StandardBedrockWorld interface {
 	LoadNBT(dm define.Dimension, position define.ChunkPos) ([]map[string]any, error)
 	SaveNBTPayloadOnly(dm define.Dimension, position define.ChunkPos, data []byte) error
 	SaveNBT(dm define.Dimension, position define.ChunkPos, data []map[string]any) error
+
+	LoadEntities(dm define.Dimension, position define.ChunkPos) ([]map[string]any, error)
+	SaveEntities(dm define.Dimension, position define.ChunkPos, data []map[string]any) error
 }
 
 // CustomBedrockWorld is the function that
@@ -47,6 +54,8 @@ type StandardBedrockWorld interface {
 type CustomBedrockWorld interface {
 	LoadDeltaUpdate(dm define.Dimension, position define.ChunkPos) ([]byte, error)
 	SaveDeltaUpdate(dm define.Dimension, position define.ChunkPos, payload []byte) error
+	AppendDeltaUpdate(dm define.Dimension, position define.ChunkPos, update define.DeltaUpdate, timeStamp int64) error
+	ApplyDeltaUpdates(dm define.Dimension, position define.ChunkPos) error
 
 	LoadTimeStamp(dm define.Dimension, position define.ChunkPos) (timeStamp int64)
 	SaveTimeStamp(dm define.Dimension, position define.ChunkPos, timeStamp int64) error
@@ -57,6 +66,19 @@ type CustomBedrockWorld interface {
 	SaveFullSubChunkBlobHash(dm define.Dimension, position define.ChunkPos, newHash []define.HashWithPosY) error
 	LoadSubChunkBlobHash(dm define.Dimension, position define.SubChunkPos) (hash uint64, found bool)
 	SaveSubChunkBlobHash(dm define.Dimension, position define.SubChunkPos, hash uint64) error
+
+	ReplaceBlocks(dm define.Dimension, layer uint8, mapping map[uint32]uint32) (modified int, err error)
+	ReplaceFunc(dm define.Dimension, layer uint8, f func(runtimeID uint32) uint32) (modified int, err error)
+	BlockStatistics(dm define.Dimension, layer uint8, positions []define.ChunkPos) (result define.BlockStatistics, err error)
+
+	CaptureStructure(dm define.Dimension, start, end define.BlockPos) (*structure.Structure, error)
+	PasteStructure(dm define.Dimension, origin define.BlockPos, s *structure.Structure) error
+
+	ExportChunkJSON(dm define.Dimension, position define.ChunkPos) (data []byte, exists bool, err error)
+	ImportChunkJSON(data []byte) (dm define.Dimension, position define.ChunkPos, err error)
+
+	UpdateBlobHash(dm define.Dimension, position define.ChunkPos) error
+	ClientCacheMissResponse(dm define.Dimension, positions []define.ChunkPos, status *packet.ClientCacheBlobStatus) (*packet.ClientCacheMissResponse, error)
 }
 
 // World is a interface that implements
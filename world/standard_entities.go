@@ -0,0 +1,118 @@
+package world
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/TriM-Organization/bedrock-world-operator/define"
+	world_define "github.com/TriM-Organization/bedrock-world-operator/world/define"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// entityKey returns the key of the entity whose unique ID is id.
+func entityKey(id int64) []byte {
+	return binary.LittleEndian.AppendUint64([]byte(world_define.KeyEntity), uint64(id))
+}
+
+// entityIdentifiersKey returns the key of the unique IDs of the entities in the chunk at position.
+func entityIdentifiersKey(dm define.Dimension, position define.ChunkPos) []byte {
+	return append([]byte(world_define.KeyEntityIdentifiers), world_define.Index(dm, position)...)
+}
+
+// LoadEntities loads all entities from the chunk position passed. The entities are stored by their unique IDs
+// since 1.18.30, and the ones stored in the legacy format (all in one key of the chunk) are loaded as well.
+func (b *BedrockWorld) LoadEntities(dm define.Dimension, position define.ChunkPos) ([]map[string]any, error) {
+	result := make([]map[string]any, 0)
+
+	ids, err := b.Get(entityIdentifiersKey(dm, position))
+	if err != nil {
+		return nil, fmt.Errorf("LoadEntities: %v", err)
+	}
+	for i := 0; i+8 <= len(ids); i += 8 {
+		data, err := b.Get(entityKey(int64(binary.LittleEndian.Uint64(ids[i:]))))
+		if err != nil {
+			return nil, fmt.Errorf("LoadEntities: %v", err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		var m map[string]any
+		if err = nbt.UnmarshalEncoding(data, &m, nbt.LittleEndian); err != nil {
+			return nil, fmt.Errorf("LoadEntities: decode nbt: %v", err)
+		}
+		result = append(result, m)
+	}
+
+	data, err := b.Get(world_define.Sum(dm, position, world_define.KeyEntitiesOld))
+	if err != nil {
+		return nil, fmt.Errorf("LoadEntities: %v", err)
+	}
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+	for buf.Len() != 0 {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("LoadEntities: decode nbt: %v", err)
+		}
+		result = append(result, m)
+	}
+
+	return result, nil
+}
+
+// SaveEntities saves all entities to the chunk position passed, which replaces the entities in it. The entities
+// are stored by their unique IDs, so each of them must hold a "UniqueID" of int64. The legacy entities of the
+// chunk are removed, because they are saved in the current format instead.
+func (b *BedrockWorld) SaveEntities(dm define.Dimension, position define.ChunkPos, data []map[string]any) error {
+	idsBuf := bytes.NewBuffer(nil)
+	payloads := make(map[int64][]byte, len(data))
+	for _, entity := range data {
+		id, ok := entity["UniqueID"].(int64)
+		if !ok {
+			return fmt.Errorf("SaveEntities: entity has no UniqueID of int64: %v", entity["identifier"])
+		}
+		payload, err := nbt.MarshalEncoding(entity, nbt.LittleEndian)
+		if err != nil {
+			return fmt.Errorf("SaveEntities: encode nbt: %v", err)
+		}
+		if _, ok := payloads[id]; !ok {
+			_ = binary.Write(idsBuf, binary.LittleEndian, id)
+		}
+		payloads[id] = payload
+	}
+
+	idsKey := entityIdentifiersKey(dm, position)
+	oldIDs, err := b.Get(idsKey)
+	if err != nil {
+		return fmt.Errorf("SaveEntities: %v", err)
+	}
+	for i := 0; i+8 <= len(oldIDs); i += 8 {
+		id := int64(binary.LittleEndian.Uint64(oldIDs[i:]))
+		if _, ok := payloads[id]; ok {
+			continue
+		}
+		if err := b.Delete(entityKey(id)); err != nil {
+			return fmt.Errorf("SaveEntities: %v", err)
+		}
+	}
+	if err := b.Delete(world_define.Sum(dm, position, world_define.KeyEntitiesOld)); err != nil {
+		return fmt.Errorf("SaveEntities: %v", err)
+	}
+
+	if len(payloads) == 0 {
+		if err := b.Delete(idsKey); err != nil {
+			return fmt.Errorf("SaveEntities: %v", err)
+		}
+		return nil
+	}
+	for id, payload := range payloads {
+		if err := b.Put(entityKey(id), payload); err != nil {
+			return fmt.Errorf("SaveEntities: %v", err)
+		}
+	}
+	if err := b.Put(idsKey, idsBuf.Bytes()); err != nil {
+		return fmt.Errorf("SaveEntities: %v", err)
+	}
+	return nil
+}